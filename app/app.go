@@ -15,7 +15,7 @@ import (
 
 	"github.com/netcloth/netcloth-chain/app/protocol"
 	v0 "github.com/netcloth/netcloth-chain/app/v0"
-	"github.com/netcloth/netcloth-chain/app/v0/auth"
+	"github.com/netcloth/netcloth-chain/app/v0/vm"
 	"github.com/netcloth/netcloth-chain/baseapp"
 	"github.com/netcloth/netcloth-chain/codec"
 	sdk "github.com/netcloth/netcloth-chain/types"
@@ -75,7 +75,7 @@ func (app *NCHApp) SimulationManager() *module.SimulationManager {
 }
 
 // NewNCHApp returns a reference to an initialized NCHApp
-func NewNCHApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest bool, invCheckPeriod uint, baseAppOptions ...func(*baseapp.BaseApp)) *NCHApp {
+func NewNCHApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest bool, invCheckPeriod uint, logIndex *vm.LogIndex, baseAppOptions ...func(*baseapp.BaseApp)) *NCHApp {
 	baseApp := baseapp.NewBaseApp(appName, logger, db, baseAppOptions...)
 
 	baseApp.SetCommitMultiStoreTracer(traceStore)
@@ -100,7 +100,7 @@ func NewNCHApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest bo
 		}
 	}
 
-	engine.Add(v0.NewProtocolV0(0, logger, protocolKeeper, app.DeliverTx, invCheckPeriod, nil))
+	engine.Add(v0.NewProtocolV0(0, logger, protocolKeeper, app.DeliverTx, invCheckPeriod, nil, logIndex))
 
 	loaded, current := engine.LoadCurrentProtocol(app.GetCms().GetKVStore(mainStoreKey))
 	if !loaded {
@@ -109,7 +109,7 @@ func NewNCHApp(logger log.Logger, db dbm.DB, traceStore io.Writer, loadLatest bo
 	logger.Info(fmt.Sprintf("launch app with protocol version: %d", current))
 
 	// set txDeocder
-	app.SetTxDecoder(auth.DefaultTxDecoder(engine.GetCurrentProtocol().GetCodec()))
+	app.SetTxDecoder(vm.TxDecoder(engine.GetCurrentProtocol().GetCodec()))
 
 	return app
 }
@@ -144,7 +144,7 @@ func (app *NCHApp) postEndBlocker(res *abci.ResponseEndBlock) {
 
 	success := app.Engine.Activate(appVersion)
 	if success {
-		app.SetTxDecoder(auth.DefaultTxDecoder(app.Engine.GetCurrentProtocol().GetCodec()))
+		app.SetTxDecoder(vm.TxDecoder(app.Engine.GetCurrentProtocol().GetCodec()))
 		return
 	}
 
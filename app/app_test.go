@@ -25,7 +25,7 @@ var (
 
 func TestExport(t *testing.T) {
 	db := db.NewMemDB()
-	app := NewNCHApp(log.NewTMLogger(log.NewSyncWriter(os.Stdout)), db, nil, true, 0)
+	app := NewNCHApp(log.NewTMLogger(log.NewSyncWriter(os.Stdout)), db, nil, true, 0, nil)
 
 	// make  simulation of abci.RequestInitChain
 	genDoc, err := tm.GenesisDocFromFile("./genesis/genesis.json")
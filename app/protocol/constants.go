@@ -41,6 +41,7 @@ const (
 
 	ParamsTStoreKey  = "transient_" + ParamsStoreKey
 	StakingTStoreKey = "transient_" + StakingStoreKey
+	VMTStoreKey      = "transient_" + VMStoreKey
 )
 
 // all store keys
@@ -65,5 +66,6 @@ var (
 	TKeys = sdk.NewTransientStoreKeys(
 		ParamsTStoreKey,
 		StakingTStoreKey,
+		VMTStoreKey,
 	)
 )
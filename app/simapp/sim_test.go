@@ -25,7 +25,7 @@ func TestFullAppSimulation(t *testing.T) {
 		require.NoError(t, os.RemoveAll(dir))
 	}()
 
-	app := app.NewNCHApp(logger, db, nil, true, FlagPeriodValue, baseapp.FauxMerkleMode())
+	app := app.NewNCHApp(logger, db, nil, true, FlagPeriodValue, nil, baseapp.FauxMerkleMode())
 
 	// run randomized simulation
 	curProtocol := app.Engine.GetCurrentProtocol()
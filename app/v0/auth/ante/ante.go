@@ -9,9 +9,12 @@ import (
 // NewAnteHandler returns an AnteHandler that checks and increments sequence
 // numbers, checks signatures & account numbers, and deducts fees from the first
 // signer.
+// Ethereum txs are handled by a separate chain which recovers the sender from
+// the signature, checks its chain id against the evm chain id, checks the nonce
+// against the sender's sequence and deducts gasPrice*gasLimit from the sender.
 
-func NewAnteHandler(ak auth.AccountKeeper, supplyKeeper types.SupplyKeeper, sigGasConsumer SignatureVerificationGasConsumer) sdk.AnteHandler {
-	return sdk.ChainAnteDecorators(
+func NewAnteHandler(ak auth.AccountKeeper, supplyKeeper types.SupplyKeeper, evmKeeper types.EVMKeeper, sigGasConsumer SignatureVerificationGasConsumer) sdk.AnteHandler {
+	stdAnteHandler := sdk.ChainAnteDecorators(
 		NewSetUpContextDecorator(), // outermost AnteDecorator. SetUpContext must be called first
 		NewFeePreprocessDecorator(ak),
 		NewMempoolFeeDecorator(),
@@ -25,4 +28,25 @@ func NewAnteHandler(ak auth.AccountKeeper, supplyKeeper types.SupplyKeeper, sigG
 		NewSigVerificationDecorator(ak),
 		NewIncrementSequenceDecorator(ak), // innermost AnteDecorator
 	)
+
+	ethAnteHandler := sdk.ChainAnteDecorators(
+		NewSetUpContextDecorator(), // outermost AnteDecorator. SetUpContext must be called first
+		NewFeePreprocessDecorator(ak),
+		NewMempoolFeeDecorator(),
+		NewValidateBasicDecorator(),
+		NewEthConsumeTxSizeGasDecorator(ak),
+		NewEthChainIDDecorator(evmKeeper),
+		NewEthSigVerificationDecorator(ak),
+		NewEthNonceVerificationDecorator(ak),
+		NewDeductFeeDecorator(ak, supplyKeeper),
+		NewEthIncrementSequenceDecorator(ak), // innermost AnteDecorator
+	)
+
+	return func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, error) {
+		if _, ok := tx.(EthereumTx); ok {
+			return ethAnteHandler(ctx, tx, simulate)
+		}
+
+		return stdAnteHandler(ctx, tx, simulate)
+	}
 }
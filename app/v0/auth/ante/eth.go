@@ -0,0 +1,187 @@
+package ante
+
+import (
+	"math/big"
+
+	"github.com/netcloth/netcloth-chain/app/v0/auth"
+	"github.com/netcloth/netcloth-chain/app/v0/auth/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
+)
+
+// EthereumTx defines a Tx carrying an Ethereum signed transaction. Its sender
+// is recovered from the signature instead of being declared by the tx, and its
+// nonce must match the sender's account sequence.
+type EthereumTx interface {
+	FeeTx
+	GetNonce() uint64
+	ChainID() *big.Int
+	RecoverSender() (sdk.AccAddress, error)
+}
+
+// EthConsumeTxSizeGasDecorator consumes gas proportional to the size of an Ethereum tx
+// CONTRACT: Tx must implement EthereumTx interface
+type EthConsumeTxSizeGasDecorator struct {
+	ak auth.AccountKeeper
+}
+
+func NewEthConsumeTxSizeGasDecorator(ak auth.AccountKeeper) EthConsumeTxSizeGasDecorator {
+	return EthConsumeTxSizeGasDecorator{
+		ak: ak,
+	}
+}
+
+func (ecgts EthConsumeTxSizeGasDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	if _, ok := tx.(EthereumTx); !ok {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be an EthereumTx")
+	}
+
+	params := ecgts.ak.GetParams(ctx)
+	ctx.GasMeter().ConsumeGas(params.TxSizeCostPerByte*sdk.Gas(len(ctx.TxBytes())), "txSize")
+
+	return next(ctx, tx, simulate)
+}
+
+// EthChainIDDecorator rejects Ethereum txs which are not replay protected or whose
+// EIP-155 chain id differs from the evm chain id param, so txs signed for another
+// chain can not be replayed. Ethereum txs are rejected while the param is unset.
+// CONTRACT: Tx must implement EthereumTx interface
+type EthChainIDDecorator struct {
+	ek types.EVMKeeper
+}
+
+func NewEthChainIDDecorator(ek types.EVMKeeper) EthChainIDDecorator {
+	return EthChainIDDecorator{
+		ek: ek,
+	}
+}
+
+func (ecid EthChainIDDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	ethTx, ok := tx.(EthereumTx)
+	if !ok {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be an EthereumTx")
+	}
+
+	chainID := ethTx.ChainID()
+	if chainID == nil {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "unprotected Ethereum txs are not allowed, sign with an EIP-155 chain id")
+	}
+
+	evmChainID := ecid.ek.GetEVMChainID(ctx)
+	if evmChainID == 0 {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "Ethereum txs are disabled until the evm chain id is set")
+	}
+
+	if !chainID.IsUint64() || chainID.Uint64() != evmChainID {
+		return ctx, sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "invalid chain id; got %s, expected %d", chainID, evmChainID)
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// EthSigVerificationDecorator recovers the sender of an Ethereum tx from its
+// secp256k1 signature, consumes the signature verification gas and checks that
+// the recovered sender matches the fee payer and exists. As for StdTxs, a simulated
+// tx isn't verified: its signature is a placeholder and its sender the fee payer.
+// CONTRACT: Tx must implement EthereumTx interface
+type EthSigVerificationDecorator struct {
+	ak auth.AccountKeeper
+}
+
+func NewEthSigVerificationDecorator(ak auth.AccountKeeper) EthSigVerificationDecorator {
+	return EthSigVerificationDecorator{
+		ak: ak,
+	}
+}
+
+func (esvd EthSigVerificationDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (newCtx sdk.Context, err error) {
+	ethTx, ok := tx.(EthereumTx)
+	if !ok {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be an EthereumTx")
+	}
+
+	params := esvd.ak.GetParams(ctx)
+	ctx.GasMeter().ConsumeGas(params.SigVerifyCostSecp256k1, "ante verify: secp256k1")
+
+	sender := ethTx.FeePayer()
+	if !simulate {
+		sender, err = ethTx.RecoverSender()
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	if !sender.Equals(ethTx.FeePayer()) {
+		return ctx, sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "recovered sender %s does not match fee payer %s", sender, ethTx.FeePayer())
+	}
+
+	if _, err := GetSignerAcc(ctx, esvd.ak, sender); err != nil {
+		return ctx, err
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// EthNonceVerificationDecorator checks that the nonce of an Ethereum tx equals the
+// sequence of the sender's account.
+// CONTRACT: Tx must implement EthereumTx interface
+type EthNonceVerificationDecorator struct {
+	ak auth.AccountKeeper
+}
+
+func NewEthNonceVerificationDecorator(ak auth.AccountKeeper) EthNonceVerificationDecorator {
+	return EthNonceVerificationDecorator{
+		ak: ak,
+	}
+}
+
+func (envd EthNonceVerificationDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	ethTx, ok := tx.(EthereumTx)
+	if !ok {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be an EthereumTx")
+	}
+
+	acc, err := GetSignerAcc(ctx, envd.ak, ethTx.FeePayer())
+	if err != nil {
+		return ctx, err
+	}
+
+	if ethTx.GetNonce() != acc.GetSequence() {
+		return ctx, sdkerrors.Wrapf(sdkerrors.ErrInvalidSequence, "invalid nonce; got %d, expected %d", ethTx.GetNonce(), acc.GetSequence())
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// EthIncrementSequenceDecorator increments the sequence of the sender of an Ethereum tx.
+// Like IncrementSequenceDecorator, the sequence is not incremented on CheckTx.
+// CONTRACT: Tx must implement EthereumTx interface
+type EthIncrementSequenceDecorator struct {
+	ak auth.AccountKeeper
+}
+
+func NewEthIncrementSequenceDecorator(ak auth.AccountKeeper) EthIncrementSequenceDecorator {
+	return EthIncrementSequenceDecorator{
+		ak: ak,
+	}
+}
+
+func (eisd EthIncrementSequenceDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	// no need to increment sequence on CheckTx or RecheckTx
+	if ctx.IsCheckTx() && !simulate {
+		return next(ctx, tx, simulate)
+	}
+
+	ethTx, ok := tx.(EthereumTx)
+	if !ok {
+		return ctx, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be an EthereumTx")
+	}
+
+	acc := eisd.ak.GetAccount(ctx, ethTx.FeePayer())
+	if err := acc.SetSequence(acc.GetSequence() + 1); err != nil {
+		panic(err)
+	}
+	eisd.ak.SetAccount(ctx, acc)
+
+	return next(ctx, tx, simulate)
+}
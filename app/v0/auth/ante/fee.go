@@ -37,7 +37,13 @@ func (fpd FeePreprocessDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulat
 		return ctx, sdkerrors.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
 	}
 
-	if !simulate && ctx.BlockHeight() != 0 {
+	if ctx.BlockHeight() != 0 {
+		// the params are read in simulate mode too, a simulated tx consumes the gas of the read
+		feeParams := fpd.ak.GetParams(ctx)
+		if simulate {
+			return next(ctx, tx, simulate)
+		}
+
 		gasLimit := feeTx.GetGas()
 		feeCoins := feeTx.GetFee()
 
@@ -45,7 +51,6 @@ func (fpd FeePreprocessDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulat
 			return ctx, sdkerrors.Wrapf(sdkerrors.ErrGasLimitError, "%d", int64(gasLimit))
 		}
 
-		feeParams := fpd.ak.GetParams(ctx)
 		gasPriceThreshold := sdk.NewInt(int64(feeParams.GasPriceThreshold))
 		gasPrice := feeCoins.AmountOf(sdk.NativeTokenName).Quo(sdk.NewInt(int64(gasLimit)))
 
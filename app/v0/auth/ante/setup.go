@@ -60,6 +60,12 @@ func (sud SetUpContextDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate
 
 // SetGasMeter returns a new context with a gas meter set from a given context.
 func SetGasMeter(simulate bool, ctx sdk.Context, gasLimit uint64) sdk.Context {
+	// A simulation keeps the gas limit set by its caller, e.g. to search the
+	// smallest gas limit under which a tx succeeds.
+	if simulate && ctx.BlockHeight() != 0 && ctx.GasMeter().Limit() > 0 {
+		return ctx.WithGasMeter(sdk.NewGasMeter(ctx.GasMeter().Limit()))
+	}
+
 	// In various cases such as simulation and during the genesis block, we do not
 	// meter any gas utilization.
 	if simulate || ctx.BlockHeight() == 0 {
@@ -19,6 +19,12 @@ func NewRefundKeeper(cdc *codec.Codec, key sdk.StoreKey) RefundKeeper {
 	}
 }
 
+// feeTx is implemented by every tx type which pays a fee, e.g. StdTx and Ethereum txs
+type feeTx interface {
+	sdk.Tx
+	GetFee() sdk.Coins
+}
+
 func NewFeeRefundHandler(am AccountKeeper, supplyKeeper auth.SupplyKeeper, rk RefundKeeper) sdk.FeeRefundHandler {
 	return func(ctx sdk.Context, tx sdk.Tx, txResult sdk.Result) (actualCostFee sdk.Coin, err error) {
 		txAccount := GetFeePayers(ctx)
@@ -26,13 +32,13 @@ func NewFeeRefundHandler(am AccountKeeper, supplyKeeper auth.SupplyKeeper, rk Re
 			return sdk.Coin{}, nil
 		}
 
-		stdTx, ok := tx.(StdTx)
+		feeTx, ok := tx.(feeTx)
 		if !ok {
 			return sdk.Coin{}, nil
 		}
 		ctx = ctx.WithGasMeter(sdk.NewInfiniteGasMeter())
 
-		fee := getFee(stdTx.Fee.Amount)
+		fee := getFee(feeTx.GetFee())
 
 		// if all gas has been consumed, then there is no need to run the fee refund process
 		if txResult.GasWanted <= txResult.GasUsed {
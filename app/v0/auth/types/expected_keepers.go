@@ -12,3 +12,8 @@ type SupplyKeeper interface {
 	GetModuleAccount(ctx sdk.Context, moduleName string) exported.ModuleAccountI
 	GetModuleAddress(moduleName string) sdk.AccAddress
 }
+
+// EVMKeeper defines the expected vm Keeper, used to check the chain id of Ethereum txs (noalias)
+type EVMKeeper interface {
+	GetEVMChainID(ctx sdk.Context) uint64
+}
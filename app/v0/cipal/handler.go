@@ -32,6 +32,10 @@ func handleMsgIPALClaim(ctx sdk.Context, k Keeper, msg MsgIPALClaim) (*sdk.Resul
 		return nil, sdkerrors.Wrap(ErrCIPALClaimUserRequestSigVerify, "user signature verify failed")
 	}
 
+	if err := k.ValidateServiceInfo(ctx, msg.UserRequest.Params.ServiceInfo); err != nil {
+		return nil, err
+	}
+
 	obj, found := k.GetCIPALObject(ctx, msg.UserRequest.Params.UserAddress)
 	if found {
 		updateIndex := -1
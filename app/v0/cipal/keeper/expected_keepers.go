@@ -9,3 +9,8 @@ import (
 type AccountKeeper interface {
 	GetAccount(ctx sdk.Context, addr sdk.AccAddress) exported.Account
 }
+
+// ServiceTypeKeeper defines the expected keeper of the service type registry (noalias)
+type ServiceTypeKeeper interface {
+	ValidateServiceEndpoint(ctx sdk.Context, serviceType uint64, endpoint string) error
+}
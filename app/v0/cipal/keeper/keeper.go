@@ -12,16 +12,18 @@ import (
 )
 
 type Keeper struct {
-	storeKey   sdk.StoreKey
-	cdc        *codec.Codec
-	paramstore params.Subspace
+	storeKey          sdk.StoreKey
+	cdc               *codec.Codec
+	paramstore        params.Subspace
+	serviceTypeKeeper ServiceTypeKeeper
 }
 
-func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec, paramstore params.Subspace) Keeper {
+func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec, paramstore params.Subspace, serviceTypeKeeper ServiceTypeKeeper) Keeper {
 	return Keeper{
-		storeKey:   storeKey,
-		cdc:        cdc,
-		paramstore: paramstore.WithKeyTable(ParamKeyTable()),
+		storeKey:          storeKey,
+		cdc:               cdc,
+		paramstore:        paramstore.WithKeyTable(ParamKeyTable()),
+		serviceTypeKeeper: serviceTypeKeeper,
 	}
 }
 
@@ -72,3 +74,8 @@ func (k Keeper) SetCIPALObject(ctx sdk.Context, obj types.CIPALObject) {
 	store.Set(types.GetCIPALObjectKey(obj.UserAddress), bz)
 	//ctx.Logger().Info(string(types.GetCIPALObjectKey(obj.UserAddress)))
 }
+
+// ValidateServiceInfo checks the service type of info is registered and its address matches the type
+func (k Keeper) ValidateServiceInfo(ctx sdk.Context, info types.ServiceInfo) error {
+	return k.serviceTypeKeeper.ValidateServiceEndpoint(ctx, info.Type, info.Address)
+}
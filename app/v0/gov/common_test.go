@@ -104,7 +104,7 @@ func NewNCHApp(t *testing.T) *NCHAppWithGenAccounts {
 	err := baseApp.LoadLatestVersion(protocol.Keys[protocol.MainStoreKey])
 	require.Nil(t, err)
 
-	engine.Add(v0.NewProtocolV0(0, logger, protocolKeeper, baseApp.DeliverTx, 10, nil))
+	engine.Add(v0.NewProtocolV0(0, logger, protocolKeeper, baseApp.DeliverTx, 10, nil, nil))
 
 	engine.LoadProtocol(0)
 
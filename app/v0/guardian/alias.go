@@ -12,27 +12,47 @@ const (
 	RouterKey      = types.RouterKey
 	QuerierRoute   = types.QuerierRoute
 	QueryProfilers = types.QueryProfilers
+	QueryDeployers = types.QueryDeployers
+	QueryReporters = types.QueryReporters
 	StoreKey       = types.StoreKey
 )
 
 type (
 	MsgAddProfiler    = types.MsgAddProfiler
 	MsgDeleteProfiler = types.MsgDeleteProfiler
+	MsgAddDeployer    = types.MsgAddDeployer
+	MsgDeleteDeployer = types.MsgDeleteDeployer
+	MsgAddReporter    = types.MsgAddReporter
+	MsgDeleteReporter = types.MsgDeleteReporter
 	Guardian          = types.Guardian
 	Profilers         = types.Profilers
+	Deployers         = types.Deployers
+	Reporters         = types.Reporters
 )
 
 var (
 	NewMsgAddProfiler       = types.NewMsgAddProfiler
 	NewMsgDeleteProfiler    = types.NewMsgDeleteProfiler
+	NewMsgAddDeployer       = types.NewMsgAddDeployer
+	NewMsgDeleteDeployer    = types.NewMsgDeleteDeployer
+	NewMsgAddReporter       = types.NewMsgAddReporter
+	NewMsgDeleteReporter    = types.NewMsgDeleteReporter
 	NewGuardian             = types.NewGuardian
 	GetProfilerKey          = types.GetProfilerKey
 	GetProfilersSubspaceKey = types.GetProfilersSubspaceKey
+	GetDeployerKey          = types.GetDeployerKey
+	GetDeployersSubspaceKey = types.GetDeployersSubspaceKey
+	GetReporterKey          = types.GetReporterKey
+	GetReportersSubspaceKey = types.GetReportersSubspaceKey
 
 	ErrInvalidOperator       = types.ErrInvalidOperator
 	ErrProfilerNotExists     = types.ErrProfilerNotExists
 	ErrDeleteGenesisProfiler = types.ErrDeleteGenesisProfiler
 	ErrProfilerExists        = types.ErrProfilerExists
+	ErrDeployerExists        = types.ErrDeployerExists
+	ErrDeployerNotExists     = types.ErrDeployerNotExists
+	ErrReporterExists        = types.ErrReporterExists
+	ErrReporterNotExists     = types.ErrReporterNotExists
 	ErrInvalidDescription    = types.ErrInvalidDescription
 	ErrAddressEmpty          = types.ErrAddressEmpty
 	ErrAddedByEmpty          = types.ErrAddedByEmpty
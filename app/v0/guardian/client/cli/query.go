@@ -22,6 +22,8 @@ func GetQueryCmd(cdc *codec.Codec) *cobra.Command {
 
 	guardianQueryCmd.AddCommand(client.GetCommands(
 		GetCmdQueryProfilers(cdc),
+		GetCmdQueryDeployers(cdc),
+		GetCmdQueryReporters(cdc),
 	)...)
 
 	return guardianQueryCmd
@@ -53,3 +55,53 @@ func GetCmdQueryProfilers(cdc *codec.Codec) *cobra.Command {
 	}
 	return cmd
 }
+
+func GetCmdQueryDeployers(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "deployers",
+		Short:   "Query for all accounts allowed to deploy contracts",
+		Example: "nchcli query guardian deployers",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryDeployers), nil)
+			if err != nil {
+				return err
+			}
+
+			var deployers types.Deployers
+			err = cdc.UnmarshalJSON(res, &deployers)
+			if err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(deployers)
+		},
+	}
+	return cmd
+}
+
+func GetCmdQueryReporters(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "reporters",
+		Short:   "Query for all accounts allowed to report ipal nodes",
+		Example: "nchcli query guardian reporters",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryReporters), nil)
+			if err != nil {
+				return err
+			}
+
+			var reporters types.Reporters
+			err = cdc.UnmarshalJSON(res, &reporters)
+			if err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(reporters)
+		},
+	}
+	return cmd
+}
@@ -27,6 +27,10 @@ func GuardianCmd(cdc *codec.Codec) *cobra.Command {
 	txCmd.AddCommand(client.PostCommands(
 		GetCmdCreateProfiler(cdc),
 		GetCmdDeleteProfiler(cdc),
+		GetCmdAddDeployer(cdc),
+		GetCmdDeleteDeployer(cdc),
+		GetCmdAddReporter(cdc),
+		GetCmdDeleteReporter(cdc),
 	)...)
 
 	return txCmd
@@ -112,3 +116,145 @@ func GetCmdDeleteProfiler(cdc *codec.Codec) *cobra.Command {
 
 	return cmd
 }
+
+func GetCmdAddDeployer(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add-deployer",
+		Short:   "Add an account allowed to deploy contracts",
+		Example: "nchcli guardian add-deployer --from=<key-name> --address=<added address> --description=<name>",
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			fromAddr := cliCtx.GetFromAddress()
+
+			deployerAddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddress))
+			if err != nil {
+				return err
+			}
+
+			description := viper.GetString(FlagDescription)
+			if len(description) == 0 {
+				return fmt.Errorf("must use --description flag")
+			}
+
+			msg := types.NewMsgAddDeployer(description, deployerAddr, fromAddr)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(FlagAddress, "", "bech32 encoded account address")
+	cmd.Flags().String(FlagDescription, "", "description of account")
+
+	cmd.MarkFlagRequired(FlagAddress)
+	cmd.MarkFlagRequired(FlagDescription)
+
+	return cmd
+}
+
+func GetCmdDeleteDeployer(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete-deployer",
+		Short:   "Delete an account allowed to deploy contracts",
+		Example: "nchcli guardian delete-deployer --from=<key-name> --address=<deleted address>",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			fromAddr := cliCtx.GetFromAddress()
+
+			deployerAddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddress))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgDeleteDeployer(deployerAddr, fromAddr)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(FlagAddress, "", "bech32 encoded account address")
+	cmd.MarkFlagRequired(FlagAddress)
+
+	return cmd
+}
+
+func GetCmdAddReporter(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add-reporter",
+		Short:   "Add an account allowed to report ipal nodes",
+		Example: "nchcli guardian add-reporter --from=<key-name> --address=<added address> --description=<name>",
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			fromAddr := cliCtx.GetFromAddress()
+
+			reporterAddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddress))
+			if err != nil {
+				return err
+			}
+
+			description := viper.GetString(FlagDescription)
+			if len(description) == 0 {
+				return fmt.Errorf("must use --description flag")
+			}
+
+			msg := types.NewMsgAddReporter(description, reporterAddr, fromAddr)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(FlagAddress, "", "bech32 encoded account address")
+	cmd.Flags().String(FlagDescription, "", "description of account")
+
+	cmd.MarkFlagRequired(FlagAddress)
+	cmd.MarkFlagRequired(FlagDescription)
+
+	return cmd
+}
+
+func GetCmdDeleteReporter(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete-reporter",
+		Short:   "Delete an account allowed to report ipal nodes",
+		Example: "nchcli guardian delete-reporter --from=<key-name> --address=<deleted address>",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			fromAddr := cliCtx.GetFromAddress()
+
+			reporterAddr, err := sdk.AccAddressFromBech32(viper.GetString(FlagAddress))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgDeleteReporter(reporterAddr, fromAddr)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(FlagAddress, "", "bech32 encoded account address")
+	cmd.MarkFlagRequired(FlagAddress)
+
+	return cmd
+}
@@ -7,11 +7,15 @@ import (
 
 type GenesisState struct {
 	Profilers []types.Guardian `json:"profilers"`
+	Deployers []types.Guardian `json:"deployers"`
+	Reporters []types.Guardian `json:"reporters"`
 }
 
-func NewGenesisState(profilers []types.Guardian) GenesisState {
+func NewGenesisState(profilers, deployers, reporters []types.Guardian) GenesisState {
 	return GenesisState{
 		Profilers: profilers,
+		Deployers: deployers,
+		Reporters: reporters,
 	}
 }
 
@@ -19,6 +23,14 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
 	for _, profiler := range data.Profilers {
 		keeper.AddProfiler(ctx, profiler)
 	}
+
+	for _, deployer := range data.Deployers {
+		keeper.AddDeployer(ctx, deployer)
+	}
+
+	for _, reporter := range data.Reporters {
+		keeper.AddReporter(ctx, reporter)
+	}
 }
 
 func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
@@ -31,12 +43,12 @@ func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
 		profilers = append(profilers, profiler)
 	}
 
-	return NewGenesisState(profilers)
+	return NewGenesisState(profilers, k.GetAllDeployers(ctx), k.GetAllReporters(ctx))
 }
 
 func DefaultGenesisState() GenesisState {
 	guardian := Guardian{Description: "genesis", AccountType: Genesis}
-	return NewGenesisState([]Guardian{guardian})
+	return NewGenesisState([]Guardian{guardian}, nil, nil)
 }
 
 func (gs GenesisState) Contains(addr sdk.Address) bool {
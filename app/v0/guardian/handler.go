@@ -12,6 +12,14 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handleMsgAddProfiler(ctx, k, msg)
 		case MsgDeleteProfiler:
 			return handleMsgDeleteProfiler(ctx, k, msg)
+		case MsgAddDeployer:
+			return handleMsgAddDeployer(ctx, k, msg)
+		case MsgDeleteDeployer:
+			return handleMsgDeleteDeployer(ctx, k, msg)
+		case MsgAddReporter:
+			return handleMsgAddReporter(ctx, k, msg)
+		case MsgDeleteReporter:
+			return handleMsgDeleteReporter(ctx, k, msg)
 		default:
 			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized %s message type: %T", ModuleName, msg)
 		}
@@ -56,3 +64,55 @@ func handleMsgDeleteProfiler(ctx sdk.Context, k Keeper, msg MsgDeleteProfiler) (
 	}
 	return &sdk.Result{}, nil
 }
+
+func handleMsgAddDeployer(ctx sdk.Context, k Keeper, msg MsgAddDeployer) (*sdk.Result, error) {
+	if _, found := k.GetProfiler(ctx, msg.AddedBy); !found {
+		return nil, ErrInvalidOperator(msg.AddedBy)
+	}
+
+	if _, found := k.GetDeployer(ctx, msg.Address); found {
+		return nil, ErrDeployerExists(msg.Address)
+	}
+
+	k.AddDeployer(ctx, NewGuardian(msg.Description, Ordinary, msg.Address, msg.AddedBy))
+	return &sdk.Result{}, nil
+}
+
+func handleMsgDeleteDeployer(ctx sdk.Context, k Keeper, msg MsgDeleteDeployer) (*sdk.Result, error) {
+	if _, found := k.GetProfiler(ctx, msg.DeletedBy); !found {
+		return nil, ErrInvalidOperator(msg.DeletedBy)
+	}
+
+	if _, found := k.GetDeployer(ctx, msg.Address); !found {
+		return nil, ErrDeployerNotExists(msg.Address)
+	}
+
+	k.DeleteDeployer(ctx, msg.Address)
+	return &sdk.Result{}, nil
+}
+
+func handleMsgAddReporter(ctx sdk.Context, k Keeper, msg MsgAddReporter) (*sdk.Result, error) {
+	if _, found := k.GetProfiler(ctx, msg.AddedBy); !found {
+		return nil, ErrInvalidOperator(msg.AddedBy)
+	}
+
+	if _, found := k.GetReporter(ctx, msg.Address); found {
+		return nil, ErrReporterExists(msg.Address)
+	}
+
+	k.AddReporter(ctx, NewGuardian(msg.Description, Ordinary, msg.Address, msg.AddedBy))
+	return &sdk.Result{}, nil
+}
+
+func handleMsgDeleteReporter(ctx sdk.Context, k Keeper, msg MsgDeleteReporter) (*sdk.Result, error) {
+	if _, found := k.GetProfiler(ctx, msg.DeletedBy); !found {
+		return nil, ErrInvalidOperator(msg.DeletedBy)
+	}
+
+	if _, found := k.GetReporter(ctx, msg.Address); !found {
+		return nil, ErrReporterNotExists(msg.Address)
+	}
+
+	k.DeleteReporter(ctx, msg.Address)
+	return &sdk.Result{}, nil
+}
@@ -43,6 +43,76 @@ func (k Keeper) GetProfiler(ctx sdk.Context, addr sdk.AccAddress) (guardian Guar
 	return guardian, false
 }
 
+func (k Keeper) AddDeployer(ctx sdk.Context, deployer Guardian) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(deployer)
+	store.Set(GetDeployerKey(deployer.Address), bz)
+}
+
+func (k Keeper) DeleteDeployer(ctx sdk.Context, address sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(GetDeployerKey(address))
+}
+
+func (k Keeper) GetDeployer(ctx sdk.Context, addr sdk.AccAddress) (deployer Guardian, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetDeployerKey(addr))
+	if bz != nil {
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &deployer)
+		return deployer, true
+	}
+	return deployer, false
+}
+
+// GetAllDeployers returns the accounts allowed to deploy contracts
+func (k Keeper) GetAllDeployers(ctx sdk.Context) (deployers []Guardian) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GetDeployersSubspaceKey())
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var deployer Guardian
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &deployer)
+		deployers = append(deployers, deployer)
+	}
+	return
+}
+
+func (k Keeper) AddReporter(ctx sdk.Context, reporter Guardian) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(reporter)
+	store.Set(GetReporterKey(reporter.Address), bz)
+}
+
+func (k Keeper) DeleteReporter(ctx sdk.Context, address sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(GetReporterKey(address))
+}
+
+func (k Keeper) GetReporter(ctx sdk.Context, addr sdk.AccAddress) (reporter Guardian, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(GetReporterKey(addr))
+	if bz != nil {
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &reporter)
+		return reporter, true
+	}
+	return reporter, false
+}
+
+// GetAllReporters returns the accounts allowed to report ipal nodes
+func (k Keeper) GetAllReporters(ctx sdk.Context) (reporters []Guardian) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, GetReportersSubspaceKey())
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var reporter Guardian
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &reporter)
+		reporters = append(reporters, reporter)
+	}
+	return
+}
+
 func (k Keeper) ProfilersIterator(ctx sdk.Context) sdk.Iterator {
 	store := ctx.KVStore(k.storeKey)
 	return sdk.KVStorePrefixIterator(store, GetProfilersSubspaceKey())
@@ -14,6 +14,10 @@ func NewQuerier(k Keeper) sdk.Querier {
 		switch path[0] {
 		case QueryProfilers:
 			return queryProfilers(ctx, k)
+		case QueryDeployers:
+			return queryDeployers(ctx, k)
+		case QueryReporters:
+			return queryReporters(ctx, k)
 		default:
 			return nil, errors.New("unknown guardian query endpoint")
 		}
@@ -37,3 +41,19 @@ func queryProfilers(ctx sdk.Context, k Keeper) ([]byte, error) {
 	}
 	return bz, nil
 }
+
+func queryDeployers(ctx sdk.Context, k Keeper) ([]byte, error) {
+	bz, err := codec.MarshalJSONIndent(k.cdc, k.GetAllDeployers(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
+
+func queryReporters(ctx sdk.Context, k Keeper) ([]byte, error) {
+	bz, err := codec.MarshalJSONIndent(k.cdc, k.GetAllReporters(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
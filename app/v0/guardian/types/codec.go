@@ -7,6 +7,10 @@ import (
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgAddProfiler{}, "nch/guardian/MsgAddProfiler", nil)
 	cdc.RegisterConcrete(MsgDeleteProfiler{}, "nch/guardian/MsgDeleteProfiler", nil)
+	cdc.RegisterConcrete(MsgAddDeployer{}, "nch/guardian/MsgAddDeployer", nil)
+	cdc.RegisterConcrete(MsgDeleteDeployer{}, "nch/guardian/MsgDeleteDeployer", nil)
+	cdc.RegisterConcrete(MsgAddReporter{}, "nch/guardian/MsgAddReporter", nil)
+	cdc.RegisterConcrete(MsgDeleteReporter{}, "nch/guardian/MsgDeleteReporter", nil)
 	cdc.RegisterConcrete(Guardian{}, "nch/guardian/Guardian", nil)
 }
 
@@ -11,9 +11,13 @@ const (
 	CodeInvalidOperator       = 100
 	CodeProfilerExists        = 101
 	CodeProfilerNotExists     = 102
+	CodeDeployerExists        = 103
+	CodeDeployerNotExists     = 104
 	CodeInvalidDescription    = 105
 	CodeDeleteGenesisProfiler = 106
 	CodeInvalidGuardian       = 108
+	CodeReporterExists        = 109
+	CodeReporterNotExists     = 110
 	CodeAddressEmpty          = 120
 	CodeAddedByEmpty          = 121
 	CodeDeletedByEmpty        = 122
@@ -35,6 +39,22 @@ func ErrProfilerExists(profiler sdk.AccAddress) error {
 	return sdkerrors.New(ModuleName, CodeProfilerExists, fmt.Sprintf("profiler %s already exists", profiler))
 }
 
+func ErrDeployerExists(deployer sdk.AccAddress) error {
+	return sdkerrors.New(ModuleName, CodeDeployerExists, fmt.Sprintf("deployer %s already exists", deployer))
+}
+
+func ErrDeployerNotExists(deployer sdk.AccAddress) error {
+	return sdkerrors.New(ModuleName, CodeDeployerNotExists, fmt.Sprintf("deployer %s is not existed", deployer))
+}
+
+func ErrReporterExists(reporter sdk.AccAddress) error {
+	return sdkerrors.New(ModuleName, CodeReporterExists, fmt.Sprintf("reporter %s already exists", reporter))
+}
+
+func ErrReporterNotExists(reporter sdk.AccAddress) error {
+	return sdkerrors.New(ModuleName, CodeReporterNotExists, fmt.Sprintf("reporter %s is not existed", reporter))
+}
+
 func ErrInvalidDescription() error {
 	return sdkerrors.New(ModuleName, CodeInvalidDescription, fmt.Sprintf("description is invalid, length should be in range 1 to %d", MaxDescLenght))
 }
@@ -14,6 +14,8 @@ const (
 
 var (
 	profilerKey = []byte{0x00}
+	deployerKey = []byte{0x01}
+	reporterKey = []byte{0x02}
 )
 
 func GetProfilerKey(addr sdk.AccAddress) []byte {
@@ -23,3 +25,19 @@ func GetProfilerKey(addr sdk.AccAddress) []byte {
 func GetProfilersSubspaceKey() []byte {
 	return profilerKey
 }
+
+func GetDeployerKey(addr sdk.AccAddress) []byte {
+	return append(deployerKey, addr.Bytes()...)
+}
+
+func GetDeployersSubspaceKey() []byte {
+	return deployerKey
+}
+
+func GetReporterKey(addr sdk.AccAddress) []byte {
+	return append(reporterKey, addr.Bytes()...)
+}
+
+func GetReportersSubspaceKey() []byte {
+	return reporterKey
+}
@@ -4,7 +4,7 @@ import (
 	sdk "github.com/netcloth/netcloth-chain/types"
 )
 
-var _, _ sdk.Msg = MsgAddProfiler{}, MsgDeleteProfiler{}
+var _, _, _, _, _, _ sdk.Msg = MsgAddProfiler{}, MsgDeleteProfiler{}, MsgAddDeployer{}, MsgDeleteDeployer{}, MsgAddReporter{}, MsgDeleteReporter{}
 
 type MsgAddProfiler struct {
 	AddGuardian
@@ -75,6 +75,144 @@ func (m MsgDeleteProfiler) GetSigners() []sdk.AccAddress {
 	return []sdk.AccAddress{m.DeletedBy}
 }
 
+type MsgAddDeployer struct {
+	AddGuardian
+}
+
+func NewMsgAddDeployer(description string, address, addedBy sdk.AccAddress) MsgAddDeployer {
+	return MsgAddDeployer{
+		AddGuardian: AddGuardian{
+			Description: description,
+			Address:     address,
+			AddedBy:     addedBy,
+		},
+	}
+}
+
+func (m MsgAddDeployer) Route() string {
+	return RouterKey
+}
+
+func (m MsgAddDeployer) Type() string {
+	return "MsgAddDeployer"
+}
+
+func (m MsgAddDeployer) ValidateBasic() error {
+	return m.AddGuardian.ValidateBasic()
+}
+
+func (m MsgAddDeployer) GetSignBytes() []byte {
+	bz := msgCdc.MustMarshalJSON(m)
+	return sdk.MustSortJSON(bz)
+}
+
+func (m MsgAddDeployer) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{m.AddedBy}
+}
+
+type MsgDeleteDeployer struct {
+	DeleteGuardian
+}
+
+func NewMsgDeleteDeployer(address, deletedBy sdk.AccAddress) MsgDeleteDeployer {
+	return MsgDeleteDeployer{
+		DeleteGuardian: DeleteGuardian{
+			Address:   address,
+			DeletedBy: deletedBy,
+		},
+	}
+}
+
+func (m MsgDeleteDeployer) Route() string {
+	return RouterKey
+}
+
+func (m MsgDeleteDeployer) Type() string {
+	return "MsgDeleteDeployer"
+}
+
+func (m MsgDeleteDeployer) ValidateBasic() error {
+	return m.DeleteGuardian.ValidateBasic()
+}
+
+func (m MsgDeleteDeployer) GetSignBytes() []byte {
+	bz := msgCdc.MustMarshalJSON(m)
+	return sdk.MustSortJSON(bz)
+}
+
+func (m MsgDeleteDeployer) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{m.DeletedBy}
+}
+
+type MsgAddReporter struct {
+	AddGuardian
+}
+
+func NewMsgAddReporter(description string, address, addedBy sdk.AccAddress) MsgAddReporter {
+	return MsgAddReporter{
+		AddGuardian: AddGuardian{
+			Description: description,
+			Address:     address,
+			AddedBy:     addedBy,
+		},
+	}
+}
+
+func (m MsgAddReporter) Route() string {
+	return RouterKey
+}
+
+func (m MsgAddReporter) Type() string {
+	return "MsgAddReporter"
+}
+
+func (m MsgAddReporter) ValidateBasic() error {
+	return m.AddGuardian.ValidateBasic()
+}
+
+func (m MsgAddReporter) GetSignBytes() []byte {
+	bz := msgCdc.MustMarshalJSON(m)
+	return sdk.MustSortJSON(bz)
+}
+
+func (m MsgAddReporter) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{m.AddedBy}
+}
+
+type MsgDeleteReporter struct {
+	DeleteGuardian
+}
+
+func NewMsgDeleteReporter(address, deletedBy sdk.AccAddress) MsgDeleteReporter {
+	return MsgDeleteReporter{
+		DeleteGuardian: DeleteGuardian{
+			Address:   address,
+			DeletedBy: deletedBy,
+		},
+	}
+}
+
+func (m MsgDeleteReporter) Route() string {
+	return RouterKey
+}
+
+func (m MsgDeleteReporter) Type() string {
+	return "MsgDeleteReporter"
+}
+
+func (m MsgDeleteReporter) ValidateBasic() error {
+	return m.DeleteGuardian.ValidateBasic()
+}
+
+func (m MsgDeleteReporter) GetSignBytes() []byte {
+	bz := msgCdc.MustMarshalJSON(m)
+	return sdk.MustSortJSON(bz)
+}
+
+func (m MsgDeleteReporter) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{m.DeletedBy}
+}
+
 type AddGuardian struct {
 	Description string         `json:"description"`
 	Address     sdk.AccAddress `json:"address"`
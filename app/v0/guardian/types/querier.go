@@ -2,4 +2,6 @@ package types
 
 const (
 	QueryProfilers = "profilers"
+	QueryDeployers = "deployers"
+	QueryReporters = "reporters"
 )
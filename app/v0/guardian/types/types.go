@@ -43,6 +43,42 @@ func (ps Profilers) String() (out string) {
 	return strings.TrimSpace(out)
 }
 
+// Deployers are the accounts allowed to deploy contracts when the vm deploy
+// permission is the guardian whitelist
+type Deployers []Guardian
+
+func (ds Deployers) String() (out string) {
+	if len(ds) == 0 {
+		return "[]"
+	}
+	for _, val := range ds {
+		out += fmt.Sprintf(`Deployer
+  Address:       %s
+  Description:   %s
+  AddedBy:       %s
+`, val.Address, val.Description, val.AddedBy)
+	}
+	return strings.TrimSpace(out)
+}
+
+// Reporters are the accounts allowed to report the downtime and the misbehaviour
+// of ipal nodes
+type Reporters []Guardian
+
+func (rs Reporters) String() (out string) {
+	if len(rs) == 0 {
+		return "[]"
+	}
+	for _, val := range rs {
+		out += fmt.Sprintf(`Reporter
+  Address:       %s
+  Description:   %s
+  AddedBy:       %s
+`, val.Address, val.Description, val.AddedBy)
+	}
+	return strings.TrimSpace(out)
+}
+
 type Trustees []Guardian
 
 func (ts Trustees) String() (out string) {
@@ -11,29 +11,113 @@ const (
 	RouterKey         = types.RouterKey
 	QuerierRoute      = types.QuerierRoute
 	DefaultParamspace = keeper.DefaultParamspace
+
+	ReportDowntime     = types.ReportDowntime
+	ReportMisbehaviour = types.ReportMisbehaviour
+
+	DoNotModifyDesc = types.DoNotModifyDesc
+
+	Chatting = types.Chatting
+	Storage  = types.Storage
+
+	RewardPoolName = types.RewardPoolName
 )
 
 var (
-	NewKeeper              = keeper.NewKeeper
-	NewQuerier             = keeper.NewQuerier
-	RegisterCodec          = types.RegisterCodec
-	NewIPALNodeObject      = types.NewIPALNode
-	NewMsgIPALNodeClaim    = types.NewMsgIPALNodeClaim
-	ModuleCdc              = types.ModuleCdc
-	AttributeValueCategory = types.AttributeValueCategory
-	NewEndpoint            = types.NewEndpoint
-	ErrEmptyInputs         = types.ErrEmptyInputs
-	ErrBadDenom            = types.ErrBadDenom
-	ErrBondInsufficient    = types.ErrBondInsufficient
-	ErrMonikerExist        = types.ErrMonikerExist
-	ErrEndpointsFormat     = types.ErrEndpointsFormat
-	ErrEndpointsEmpty      = types.ErrEndpointsEmpty
-	ErrEndpointsDuplicate  = types.ErrEndpointsDuplicate
+	NewKeeper                     = keeper.NewKeeper
+	NewQuerier                    = keeper.NewQuerier
+	RegisterCodec                 = types.RegisterCodec
+	NewIPALNodeObject             = types.NewIPALNode
+	NewMsgIPALNodeClaim           = types.NewMsgIPALNodeClaim
+	NewMsgIPALNodeReport          = types.NewMsgIPALNodeReport
+	NewMsgIPALNodeUnjail          = types.NewMsgIPALNodeUnjail
+	NewMsgIPALNodeUnclaim         = types.NewMsgIPALNodeUnclaim
+	NewMsgIPALNodeEditEndpoints   = types.NewMsgIPALNodeEditEndpoints
+	NewMsgIPALNodeEditDescription = types.NewMsgIPALNodeEditDescription
+	NewMsgAddServiceType          = types.NewMsgAddServiceType
+	NewMsgDeleteServiceType       = types.NewMsgDeleteServiceType
+	NewMsgWithdrawIPALNodeReward  = types.NewMsgWithdrawIPALNodeReward
+	NewMsgSetIPALWithdrawAddress  = types.NewMsgSetIPALWithdrawAddress
+	NewMsgIPALDelegate            = types.NewMsgIPALDelegate
+	NewMsgIPALUndelegate          = types.NewMsgIPALUndelegate
+	NewDelegation                 = types.NewDelegation
+	NewServiceTypeInfo            = types.NewServiceTypeInfo
+	DefaultServiceTypes           = types.DefaultServiceTypes
+	NewLivenessInfo               = types.NewLivenessInfo
+	ModuleCdc                     = types.ModuleCdc
+	AttributeValueCategory        = types.AttributeValueCategory
+	EventTypeReport               = types.EventTypeReport
+	EventTypeSlash                = types.EventTypeSlash
+	EventTypeUnjail               = types.EventTypeUnjail
+	EventTypeUnclaim              = types.EventTypeUnclaim
+	EventTypeEditEndpoints        = types.EventTypeEditEndpoints
+	EventTypeEditDescription      = types.EventTypeEditDescription
+	EventTypeAddServiceType       = types.EventTypeAddServiceType
+	EventTypeDeleteServiceType    = types.EventTypeDeleteServiceType
+	EventTypeRewards              = types.EventTypeRewards
+	EventTypeWithdrawRewards      = types.EventTypeWithdrawRewards
+	EventTypeSetWithdrawAddress   = types.EventTypeSetWithdrawAddress
+	EventTypeDelegate             = types.EventTypeDelegate
+	EventTypeUndelegate           = types.EventTypeUndelegate
+	AttributeKeyOperator          = types.AttributeKeyOperator
+	AttributeKeyReporter          = types.AttributeKeyReporter
+	AttributeKeyReportType        = types.AttributeKeyReportType
+	AttributeKeyAmount            = types.AttributeKeyAmount
+	AttributeKeyCompletionTime    = types.AttributeKeyCompletionTime
+	AttributeKeyEndpoints         = types.AttributeKeyEndpoints
+	AttributeKeyMoniker           = types.AttributeKeyMoniker
+	AttributeKeyServiceType       = types.AttributeKeyServiceType
+	AttributeKeyWithdrawAddress   = types.AttributeKeyWithdrawAddress
+	AttributeKeyDelegator         = types.AttributeKeyDelegator
+	NewEndpoint                   = types.NewEndpoint
+	ErrEmptyInputs                = types.ErrEmptyInputs
+	ErrBadDenom                   = types.ErrBadDenom
+	ErrBondInsufficient           = types.ErrBondInsufficient
+	ErrMonikerExist               = types.ErrMonikerExist
+	ErrEndpointsFormat            = types.ErrEndpointsFormat
+	ErrEndpointsEmpty             = types.ErrEndpointsEmpty
+	ErrEndpointsDuplicate         = types.ErrEndpointsDuplicate
+	ErrIPALNodeNotExist           = types.ErrIPALNodeNotExist
+	ErrIPALNodeJailed             = types.ErrIPALNodeJailed
+	ErrIPALNodeNotJailed          = types.ErrIPALNodeNotJailed
+	ErrJailPeriod                 = types.ErrJailPeriod
+	ErrInvalidReporter            = types.ErrInvalidReporter
+	ErrInvalidReportType          = types.ErrInvalidReportType
+	ErrDuplicateReport            = types.ErrDuplicateReport
+	ErrLivenessDisabled           = types.ErrLivenessDisabled
+	ErrInvalidServiceType         = types.ErrInvalidServiceType
+	ErrServiceTypeUnknown         = types.ErrServiceTypeUnknown
+	ErrServiceTypeExists          = types.ErrServiceTypeExists
+	ErrInvalidProfiler            = types.ErrInvalidProfiler
+	ErrNoRewards                  = types.ErrNoRewards
+	ErrInvalidDelegator           = types.ErrInvalidDelegator
+	ErrNoDelegation               = types.ErrNoDelegation
+	ErrDelegationTooLow           = types.ErrDelegationTooLow
 )
 
 type (
-	Keeper           = keeper.Keeper
-	MsgIPALNodeClaim = types.MsgIPALNodeClaim
-	Endpoint         = types.Endpoint
-	Endpoints        = types.Endpoints
+	Keeper                     = keeper.Keeper
+	MsgIPALNodeClaim           = types.MsgIPALNodeClaim
+	MsgIPALNodeReport          = types.MsgIPALNodeReport
+	MsgIPALNodeUnjail          = types.MsgIPALNodeUnjail
+	MsgIPALNodeUnclaim         = types.MsgIPALNodeUnclaim
+	MsgIPALNodeEditEndpoints   = types.MsgIPALNodeEditEndpoints
+	MsgIPALNodeEditDescription = types.MsgIPALNodeEditDescription
+	MsgAddServiceType          = types.MsgAddServiceType
+	MsgDeleteServiceType       = types.MsgDeleteServiceType
+	MsgWithdrawIPALNodeReward  = types.MsgWithdrawIPALNodeReward
+	MsgSetIPALWithdrawAddress  = types.MsgSetIPALWithdrawAddress
+	NodeRewards                = types.NodeRewards
+	WithdrawAddress            = types.WithdrawAddress
+	MsgIPALDelegate            = types.MsgIPALDelegate
+	MsgIPALUndelegate          = types.MsgIPALUndelegate
+	Delegation                 = types.Delegation
+	Delegations                = types.Delegations
+	ServiceType                = types.ServiceType
+	ServiceTypeInfo            = types.ServiceTypeInfo
+	ServiceTypeInfos           = types.ServiceTypeInfos
+	Endpoint                   = types.Endpoint
+	Endpoints                  = types.Endpoints
+	LivenessInfo               = types.LivenessInfo
+	ReportType                 = types.ReportType
 )
@@ -9,4 +9,11 @@ const (
 	flagDetails               = "details"
 	flagExtension             = "extension"
 	flagBond                  = "bond"
+	flagOperator              = "operator"
+	flagReportType            = "type"
+	flagEvidence              = "evidence"
+	flagName                  = "name"
+	flagDescription           = "description"
+	flagURLSchema             = "url_schema"
+	flagEndpointRegex         = "endpoint_regex"
 )
@@ -27,6 +27,11 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		GetCmdQueryParams(queryRoute, cdc),
 		GetCmdQueryIPALNodeList(cdc),
 		GetCmdQueryIPALNode(cdc),
+		GetCmdQueryLiveness(queryRoute, cdc),
+		GetCmdQueryServiceTypes(queryRoute, cdc),
+		GetCmdQueryRewards(queryRoute, cdc),
+		GetCmdQueryDelegations(queryRoute, cdc),
+		GetCmdQueryDelegatorDelegations(queryRoute, cdc),
 	)...)
 
 	return ipalQueryCmd
@@ -115,3 +120,158 @@ $ %s query ipal node [address]`, version.ClientName)),
 		},
 	}
 }
+
+func GetCmdQueryLiveness(storeName string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "liveness [address]",
+		Short: "Query the liveness of an IPALNode",
+		Long: strings.TrimSpace(fmt.Sprintf(`Query the missed availability windows and the jail period of an IPALNode by accAddr.
+Example:
+$ %s query ipal liveness [address]`, version.ClientName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			bz, err := cdc.MarshalJSON(types.NewQueryIPALNodeParams(addr))
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, types.QueryLiveness), bz)
+			if err != nil {
+				return err
+			}
+
+			var info types.LivenessInfo
+			cdc.MustUnmarshalJSON(res, &info)
+			return cliCtx.PrintOutput(info)
+		},
+	}
+}
+
+func GetCmdQueryServiceTypes(storeName string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "service-types",
+		Args:  cobra.NoArgs,
+		Short: "Query the registered service types",
+		Long: strings.TrimSpace(fmt.Sprintf(`List the service types of the registry, with the schema and the regex of their endpoints.
+Example:
+$ %s query ipal service-types`, version.ClientName)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, types.QueryServiceTypes), nil)
+			if err != nil {
+				return err
+			}
+
+			var serviceTypes types.ServiceTypeInfos
+			cdc.MustUnmarshalJSON(res, &serviceTypes)
+			return cliCtx.PrintOutput(serviceTypes)
+		},
+	}
+}
+
+func GetCmdQueryRewards(storeName string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rewards [address]",
+		Short: "Query the outstanding rewards of an IPALNode",
+		Long: strings.TrimSpace(fmt.Sprintf(`Query the rewards of an IPALNode by accAddr, which are not withdrawn yet.
+Example:
+$ %s query ipal rewards [address]`, version.ClientName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			bz, err := cdc.MarshalJSON(types.NewQueryIPALNodeParams(addr))
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, types.QueryRewards), bz)
+			if err != nil {
+				return err
+			}
+
+			var rewards sdk.Coins
+			cdc.MustUnmarshalJSON(res, &rewards)
+			return cliCtx.PrintOutput(rewards)
+		},
+	}
+}
+
+func GetCmdQueryDelegations(storeName string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delegations [address]",
+		Short: "Query the delegations to an IPALNode",
+		Long: strings.TrimSpace(fmt.Sprintf(`Query the bonds delegated to an IPALNode by accAddr.
+Example:
+$ %s query ipal delegations [address]`, version.ClientName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			bz, err := cdc.MarshalJSON(types.NewQueryIPALNodeParams(addr))
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, types.QueryDelegations), bz)
+			if err != nil {
+				return err
+			}
+
+			var delegations types.Delegations
+			cdc.MustUnmarshalJSON(res, &delegations)
+			return cliCtx.PrintOutput(delegations)
+		},
+	}
+}
+
+func GetCmdQueryDelegatorDelegations(storeName string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delegator-delegations [address]",
+		Short: "Query the delegations of a delegator",
+		Long: strings.TrimSpace(fmt.Sprintf(`Query the bonds delegated to IPALNodes by a delegator.
+Example:
+$ %s query ipal delegator-delegations [address]`, version.ClientName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			addr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			bz, err := cdc.MarshalJSON(types.NewQueryIPALNodeParams(addr))
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", storeName, types.QueryDelegatorDelegations), bz)
+			if err != nil {
+				return err
+			}
+
+			var delegations types.Delegations
+			cdc.MustUnmarshalJSON(res, &delegations)
+			return cliCtx.PrintOutput(delegations)
+		},
+	}
+}
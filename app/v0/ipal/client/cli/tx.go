@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -22,6 +23,17 @@ func IPALCmd(cdc *codec.Codec) *cobra.Command {
 	}
 	txCmd.AddCommand(
 		IPALNodeClaimCmd(cdc),
+		IPALNodeReportCmd(cdc),
+		IPALNodeUnjailCmd(cdc),
+		IPALNodeUnclaimCmd(cdc),
+		IPALNodeEditEndpointsCmd(cdc),
+		IPALNodeEditDescriptionCmd(cdc),
+		AddServiceTypeCmd(cdc),
+		DeleteServiceTypeCmd(cdc),
+		WithdrawRewardsCmd(cdc),
+		SetWithdrawAddrCmd(cdc),
+		DelegateCmd(cdc),
+		UndelegateCmd(cdc),
 	)
 	return txCmd
 }
@@ -84,3 +96,346 @@ func IPALNodeClaimCmd(cdc *codec.Codec) *cobra.Command {
 
 	return cmd
 }
+
+func IPALNodeReportCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "report",
+		Short:   "Create and sign a IPALNodeReport tx, reporting the downtime or the misbehaviour of an ipal node",
+		Example: "nchcli ipal report --from=<user key name> --operator=<ipal node operator address> --type=<downtime|misbehaviour> --evidence=<evidence>",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			operator, err := sdk.AccAddressFromBech32(viper.GetString(flagOperator))
+			if err != nil {
+				return err
+			}
+
+			reportType, err := types.ReportTypeFromString(viper.GetString(flagReportType))
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgIPALNodeReport(cliCtx.GetFromAddress(), operator, reportType, viper.GetString(flagEvidence))
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(flagOperator, "", "operator address of the reported ipal node")
+	cmd.Flags().String(flagReportType, types.ReportDowntime.String(), "report type, downtime or misbehaviour")
+	cmd.Flags().String(flagEvidence, "", "evidence of the reported fault")
+
+	cmd.MarkFlagRequired(flagOperator)
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func IPALNodeUnjailCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "unjail",
+		Short:   "Create and sign a IPALNodeUnjail tx, bringing back a jailed ipal node once its jail period is over",
+		Example: "nchcli ipal unjail --from=<user key name>",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgIPALNodeUnjail(cliCtx.GetFromAddress())
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func IPALNodeUnclaimCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "unclaim",
+		Short:   "Create and sign a IPALNodeUnclaim tx, leaving the ipal node registry and unbonding the whole bond",
+		Example: "nchcli tx ipal unclaim --from=<user key name>",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgIPALNodeUnclaim(cliCtx.GetFromAddress())
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func IPALNodeEditEndpointsCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "edit-endpoints",
+		Short:   "Create and sign a IPALNodeEditEndpoints tx, replacing the endpoints of an ipal node",
+		Example: "nchcli tx ipal edit-endpoints --from=<user key name> --endpoints=<endpoints>",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			endpointDelimiter := viper.GetString(flagEndpointDelimiter)
+			endpointTypeDelimiter := viper.GetString(flagEndpointTypeDelimiter)
+			endpoints, err := types.EndpointsFromString(viper.GetString(flagEndpoints), endpointDelimiter, endpointTypeDelimiter)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgIPALNodeEditEndpoints(cliCtx.GetFromAddress(), endpoints)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(flagEndpoints, "", "ipal node endpoints, in format: serviceType|endpoint,serviceType|endpoint (e.g. 1|192.168.1.100:10000,2|192.168.1.101:20000)")
+	cmd.Flags().String(flagEndpointDelimiter, ",", "endpoints delimiter, e.g. '#' as delimiter: 1|192.168.1.100:10000#2|192.168.1.101:20000")
+	cmd.Flags().String(flagEndpointTypeDelimiter, "|", "endpoint delimiter, e.g. '-' as delimiter: 1-192.168.1.100:10000,2-192.168.1.101:20000")
+
+	cmd.MarkFlagRequired(flagEndpoints)
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func IPALNodeEditDescriptionCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "edit-description",
+		Short:   "Create and sign a IPALNodeEditDescription tx, editing the moniker, website, details and extension of an ipal node",
+		Example: "nchcli tx ipal edit-description --from=<user key name> --moniker=<name> --website=<website> --details=<details> --extension=<extension>",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgIPALNodeEditDescription(
+				cliCtx.GetFromAddress(),
+				viper.GetString(flagMoniker),
+				viper.GetString(flagWebsite),
+				viper.GetString(flagDetails),
+				viper.GetString(flagExtension),
+			)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(flagMoniker, types.DoNotModifyDesc, "ipal node moniker")
+	cmd.Flags().String(flagWebsite, types.DoNotModifyDesc, "ipal node website")
+	cmd.Flags().String(flagDetails, types.DoNotModifyDesc, "ipal node details")
+	cmd.Flags().String(flagExtension, types.DoNotModifyDesc, "extension for future user define")
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func AddServiceTypeCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add-service-type [id]",
+		Short:   "Create and sign a AddServiceType tx, registering a service type, sent by a profiler",
+		Example: "nchcli tx ipal add-service-type 3 --from=<profiler key name> --name=<name> --description=<description> --url_schema=<schema> --endpoint_regex=<regex>",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			serviceType := types.NewServiceTypeInfo(id, viper.GetString(flagName), viper.GetString(flagDescription),
+				viper.GetString(flagURLSchema), viper.GetString(flagEndpointRegex))
+			msg := types.NewMsgAddServiceType(cliCtx.GetFromAddress(), serviceType)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd.Flags().String(flagName, "", "service type name")
+	cmd.Flags().String(flagDescription, "", "service type description")
+	cmd.Flags().String(flagURLSchema, "", "schema of the endpoint urls, for wallets")
+	cmd.Flags().String(flagEndpointRegex, types.DefaultEndpointRegex, "regex the endpoints must match, any endpoint when empty")
+
+	cmd.MarkFlagRequired(flagName)
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func DeleteServiceTypeCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delete-service-type [id]",
+		Short:   "Create and sign a DeleteServiceType tx, deleting a service type from the registry, sent by a profiler",
+		Example: "nchcli tx ipal delete-service-type 3 --from=<profiler key name>",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgDeleteServiceType(cliCtx.GetFromAddress(), id)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func WithdrawRewardsCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "withdraw-rewards",
+		Short:   "Create and sign a WithdrawIPALNodeReward tx, sending the outstanding rewards of the ipal node operator or delegator to its withdraw address",
+		Example: "nchcli tx ipal withdraw-rewards --from=<user key name>",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			msg := types.NewMsgWithdrawIPALNodeReward(cliCtx.GetFromAddress())
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func SetWithdrawAddrCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "set-withdraw-addr [withdraw-addr]",
+		Short:   "Create and sign a SetIPALWithdrawAddress tx, changing the address receiving the rewards of the ipal node operator or delegator",
+		Example: "nchcli tx ipal set-withdraw-addr nch1... --from=<user key name>",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			withdrawAddr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgSetIPALWithdrawAddress(cliCtx.GetFromAddress(), withdrawAddr)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func DelegateCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "delegate [operator-addr] [amount]",
+		Short:   "Create and sign a IPALDelegate tx, delegating a bond to the ipal node of the operator",
+		Example: "nchcli tx ipal delegate nch1... 1000000000000pnch --from=<user key name>",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			operator, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			amount, err := sdk.ParseCoin(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgIPALDelegate(cliCtx.GetFromAddress(), operator, amount)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func UndelegateCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "undelegate [operator-addr] [amount]",
+		Short:   "Create and sign a IPALUndelegate tx, unbonding a bond delegated to the ipal node of the operator",
+		Example: "nchcli tx ipal undelegate nch1... 1000000000000pnch --from=<user key name>",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			operator, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			amount, err := sdk.ParseCoin(args[1])
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgIPALUndelegate(cliCtx.GetFromAddress(), operator, amount)
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+
+	cmd = client.PostCommands(cmd)[0]
+
+	return cmd
+}
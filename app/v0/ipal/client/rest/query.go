@@ -25,6 +25,31 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router) {
 		nodeHandlerFn(cliCtx),
 	).Methods("GET")
 
+	r.HandleFunc(
+		"/ipal/liveness/{accAddr}",
+		livenessHandlerFn(cliCtx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/ipal/rewards/{accAddr}",
+		rewardsHandlerFn(cliCtx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/ipal/delegations/{accAddr}",
+		delegationsHandlerFn(cliCtx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/ipal/delegator_delegations/{accAddr}",
+		delegatorDelegationsHandlerFn(cliCtx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		"/ipal/service_types",
+		serviceTypesHandlerFn(cliCtx),
+	).Methods("GET")
+
 	r.HandleFunc(
 		"/ipal/nodes",
 		nodesHandlerFn(cliCtx),
@@ -136,3 +161,37 @@ func nodeHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
 func nodesHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
 	return queryNodes(cliCtx, fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryIPALNodes))
 }
+
+func livenessHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return queryNode(cliCtx, fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryLiveness))
+}
+
+func rewardsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return queryNode(cliCtx, fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryRewards))
+}
+
+func delegationsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return queryNode(cliCtx, fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryDelegations))
+}
+
+func delegatorDelegationsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return queryNode(cliCtx, fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryDelegatorDelegations))
+}
+
+func serviceTypesHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryServiceTypes), nil)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
@@ -9,4 +9,5 @@ import (
 // RegisterRoutes registers the routes from the different modules for the LCD.
 func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
 	registerQueryRoutes(cliCtx, r)
+	registerTxRoutes(cliCtx, r)
 }
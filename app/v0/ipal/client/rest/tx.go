@@ -0,0 +1,259 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/netcloth/netcloth-chain/app/v0/auth/client/utils"
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	"github.com/netcloth/netcloth-chain/client/context"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	"github.com/netcloth/netcloth-chain/types/rest"
+)
+
+func registerTxRoutes(cliCtx context.CLIContext, r *mux.Router) {
+	r.HandleFunc(
+		"/ipal/unclaim",
+		unclaimHandlerFn(cliCtx),
+	).Methods("POST")
+
+	r.HandleFunc(
+		"/ipal/edit_endpoints",
+		editEndpointsHandlerFn(cliCtx),
+	).Methods("POST")
+
+	r.HandleFunc(
+		"/ipal/edit_description",
+		editDescriptionHandlerFn(cliCtx),
+	).Methods("POST")
+
+	r.HandleFunc(
+		"/ipal/withdraw_rewards",
+		withdrawRewardsHandlerFn(cliCtx),
+	).Methods("POST")
+
+	r.HandleFunc(
+		"/ipal/withdraw_address",
+		setWithdrawAddrHandlerFn(cliCtx),
+	).Methods("POST")
+
+	r.HandleFunc(
+		"/ipal/delegate",
+		delegateHandlerFn(cliCtx),
+	).Methods("POST")
+
+	r.HandleFunc(
+		"/ipal/undelegate",
+		undelegateHandlerFn(cliCtx),
+	).Methods("POST")
+}
+
+// UnclaimReq defines the properties of an unclaim request's body, the operator is the sender
+type UnclaimReq struct {
+	BaseReq rest.BaseReq `json:"base_req" yaml:"base_req"`
+}
+
+// EditEndpointsReq defines the properties of an edit endpoints request's body, the operator is the sender
+type EditEndpointsReq struct {
+	BaseReq   rest.BaseReq    `json:"base_req" yaml:"base_req"`
+	Endpoints types.Endpoints `json:"endpoints" yaml:"endpoints"`
+}
+
+// EditDescriptionReq defines the properties of an edit description request's body, the operator is the sender.
+// The fields set to [do-not-modify] are left unchanged.
+type EditDescriptionReq struct {
+	BaseReq   rest.BaseReq `json:"base_req" yaml:"base_req"`
+	Moniker   string       `json:"moniker" yaml:"moniker"`
+	Website   string       `json:"website" yaml:"website"`
+	Details   string       `json:"details" yaml:"details"`
+	Extension string       `json:"extension" yaml:"extension"`
+}
+
+// WithdrawRewardsReq defines the properties of a withdraw rewards request's body, the operator is the sender
+type WithdrawRewardsReq struct {
+	BaseReq rest.BaseReq `json:"base_req" yaml:"base_req"`
+}
+
+// SetWithdrawAddrReq defines the properties of a set withdraw address request's body, the operator is the sender
+type SetWithdrawAddrReq struct {
+	BaseReq         rest.BaseReq   `json:"base_req" yaml:"base_req"`
+	WithdrawAddress sdk.AccAddress `json:"withdraw_address" yaml:"withdraw_address"`
+}
+
+// DelegateReq defines the properties of a delegate or an undelegate request's body, the delegator is the sender
+type DelegateReq struct {
+	BaseReq         rest.BaseReq   `json:"base_req" yaml:"base_req"`
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"`
+	Amount          sdk.Coin       `json:"amount" yaml:"amount"`
+}
+
+// readSender reads the base request of a tx request and returns its sender
+func readSender(w http.ResponseWriter, baseReq *rest.BaseReq) (sdk.AccAddress, bool) {
+	*baseReq = baseReq.Sanitize()
+	if !baseReq.ValidateBasic(w) {
+		return nil, false
+	}
+
+	operator, err := sdk.AccAddressFromBech32(baseReq.From)
+	if err != nil {
+		rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	return operator, true
+}
+
+func unclaimHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req UnclaimReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		operator, ok := readSender(w, &req.BaseReq)
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgIPALNodeUnclaim(operator)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func editEndpointsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req EditEndpointsReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		operator, ok := readSender(w, &req.BaseReq)
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgIPALNodeEditEndpoints(operator, req.Endpoints)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func editDescriptionHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req EditDescriptionReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		operator, ok := readSender(w, &req.BaseReq)
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgIPALNodeEditDescription(operator, req.Moniker, req.Website, req.Details, req.Extension)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func withdrawRewardsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WithdrawRewardsReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		operator, ok := readSender(w, &req.BaseReq)
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgWithdrawIPALNodeReward(operator)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func setWithdrawAddrHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req SetWithdrawAddrReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		operator, ok := readSender(w, &req.BaseReq)
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgSetIPALWithdrawAddress(operator, req.WithdrawAddress)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func delegateHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req DelegateReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		delegator, ok := readSender(w, &req.BaseReq)
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgIPALDelegate(delegator, req.OperatorAddress, req.Amount)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
+
+func undelegateHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req DelegateReq
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			return
+		}
+
+		delegator, ok := readSender(w, &req.BaseReq)
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgIPALUndelegate(delegator, req.OperatorAddress, req.Amount)
+		if err := msg.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, req.BaseReq, []sdk.Msg{msg})
+	}
+}
@@ -1,6 +1,8 @@
 package ipal
 
 import (
+	"sort"
+
 	"github.com/netcloth/netcloth-chain/app/v0/ipal/keeper"
 	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
 	sdk "github.com/netcloth/netcloth-chain/types"
@@ -9,22 +11,122 @@ import (
 
 // InitGenesis new ipal genesis
 func InitGenesis(ctx sdk.Context, keeper keeper.Keeper, data types.GenesisState) []abci.ValidatorUpdate {
-	keeper.SetParams(ctx, data.Params)
+	// the genesis of the chains started before the liveness and reward params leaves out their fractions, disabled at zero
+	params := data.Params
+	for _, fraction := range []*sdk.Dec{&params.MinAvailablePerWindow, &params.SlashFractionDowntime, &params.SlashFractionMisbehaviour,
+		&params.RewardFraction} {
+		if fraction.IsNil() {
+			*fraction = sdk.ZeroDec()
+		}
+	}
+	keeper.SetParams(ctx, params)
+
+	for _, serviceType := range data.ServiceTypes {
+		keeper.SetServiceType(ctx, serviceType)
+	}
+
+	delegatedBonds := make(map[string]sdk.Int)
+	for _, delegation := range data.Delegations {
+		keeper.SetDelegation(ctx, delegation)
+
+		operator := delegation.OperatorAddress.String()
+		if delegatedBond, ok := delegatedBonds[operator]; ok {
+			delegatedBonds[operator] = delegatedBond.Add(delegation.Amount.Amount)
+		} else {
+			delegatedBonds[operator] = delegation.Amount.Amount
+		}
+	}
 
 	for _, node := range data.IPALNodes {
 		node.Bond = sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(0))
+		node.DelegatedBond = sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(0))
+		if delegatedBond, ok := delegatedBonds[node.OperatorAddress.String()]; ok {
+			node.DelegatedBond.Amount = delegatedBond
+		}
 		keeper.CreateIPALNode(ctx, node)
 	}
+
+	for _, nodeRewards := range data.Rewards {
+		keeper.SetOutstandingRewards(ctx, nodeRewards.Address, nodeRewards.Rewards)
+	}
+
+	for _, withdrawAddress := range data.WithdrawAddresses {
+		if err := keeper.SetWithdrawAddr(ctx, withdrawAddress.Address, withdrawAddress.WithdrawAddress); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, info := range data.LivenessInfos {
+		keeper.SetLivenessInfo(ctx, info)
+	}
+
+	for _, nodeMissedWindows := range data.MissedWindows {
+		for _, missed := range nodeMissedWindows.MissedWindows {
+			keeper.SetMissedWindowBitArray(ctx, nodeMissedWindows.Address, missed.Index, missed.Missed)
+		}
+	}
 	return []abci.ValidatorUpdate{}
 }
 
 // ExportGenesis returns a GenesisState for a given context and keeper.
 func ExportGenesis(ctx sdk.Context, keeper Keeper) types.GenesisState {
 	params := keeper.GetParams(ctx)
-	ipalNodes := keeper.GetAllIPALNodes(ctx)
 
-	return types.GenesisState{
-		Params:    params,
-		IPALNodes: ipalNodes,
+	var ipalNodes types.IPALNodes
+	keeper.IterateIPALNodes(ctx, func(node types.IPALNode) (stop bool) {
+		ipalNodes = append(ipalNodes, node)
+		return false
+	})
+
+	var livenessInfos []types.LivenessInfo
+	var missedWindows []types.NodeMissedWindows
+	keeper.IterateLivenessInfos(ctx, func(info types.LivenessInfo) (stop bool) {
+		livenessInfos = append(livenessInfos, info)
+
+		nodeMissedWindows := types.NodeMissedWindows{Address: info.Address}
+		keeper.IterateMissedWindowBitArray(ctx, info.Address, func(index int64, missed bool) (stop bool) {
+			nodeMissedWindows.MissedWindows = append(nodeMissedWindows.MissedWindows, types.MissedWindow{Index: index, Missed: missed})
+			return false
+		})
+		missedWindows = append(missedWindows, nodeMissedWindows)
+		return false
+	})
+
+	// the rewards not settled yet are exported with the outstanding rewards, the reward ratios start over
+	holders := make(map[string]sdk.AccAddress)
+	keeper.IterateOutstandingRewards(ctx, func(addr sdk.AccAddress, _ sdk.Coins) (stop bool) {
+		holders[addr.String()] = addr
+		return false
+	})
+	for _, node := range ipalNodes {
+		holders[node.OperatorAddress.String()] = node.OperatorAddress
+	}
+
+	var withdrawAddresses []types.WithdrawAddress
+	keeper.IterateWithdrawAddrs(ctx, func(operator, withdrawAddr sdk.AccAddress) (stop bool) {
+		withdrawAddresses = append(withdrawAddresses, types.WithdrawAddress{Address: operator, WithdrawAddress: withdrawAddr})
+		return false
+	})
+
+	var delegations types.Delegations
+	keeper.IterateDelegations(ctx, func(delegation types.Delegation) (stop bool) {
+		delegations = append(delegations, delegation)
+		holders[delegation.DelegatorAddress.String()] = delegation.DelegatorAddress
+		return false
+	})
+
+	holderKeys := make([]string, 0, len(holders))
+	for key := range holders {
+		holderKeys = append(holderKeys, key)
 	}
+	sort.Strings(holderKeys)
+
+	var rewards []types.NodeRewards
+	for _, key := range holderKeys {
+		if holderRewards := keeper.GetRewards(ctx, holders[key]); !holderRewards.IsZero() {
+			rewards = append(rewards, types.NodeRewards{Address: holders[key], Rewards: holderRewards})
+		}
+	}
+
+	return types.NewGenesisState(params, ipalNodes, livenessInfos, missedWindows, keeper.GetAllServiceTypes(ctx), rewards, withdrawAddresses, delegations)
 }
@@ -1,6 +1,10 @@
 package ipal
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/netcloth/netcloth-chain/app/v0/ipal/keeper"
@@ -16,6 +20,28 @@ func NewHandler(k Keeper) sdk.Handler {
 		switch msg := msg.(type) {
 		case MsgIPALNodeClaim:
 			return handleMsgIPALNodeClaim(ctx, k, msg)
+		case MsgIPALNodeReport:
+			return handleMsgIPALNodeReport(ctx, k, msg)
+		case MsgIPALNodeUnjail:
+			return handleMsgIPALNodeUnjail(ctx, k, msg)
+		case MsgIPALNodeUnclaim:
+			return handleMsgIPALNodeUnclaim(ctx, k, msg)
+		case MsgIPALNodeEditEndpoints:
+			return handleMsgIPALNodeEditEndpoints(ctx, k, msg)
+		case MsgIPALNodeEditDescription:
+			return handleMsgIPALNodeEditDescription(ctx, k, msg)
+		case MsgAddServiceType:
+			return handleMsgAddServiceType(ctx, k, msg)
+		case MsgDeleteServiceType:
+			return handleMsgDeleteServiceType(ctx, k, msg)
+		case MsgWithdrawIPALNodeReward:
+			return handleMsgWithdrawIPALNodeReward(ctx, k, msg)
+		case MsgSetIPALWithdrawAddress:
+			return handleMsgSetIPALWithdrawAddress(ctx, k, msg)
+		case MsgIPALDelegate:
+			return handleMsgIPALDelegate(ctx, k, msg)
+		case MsgIPALUndelegate:
+			return handleMsgIPALUndelegate(ctx, k, msg)
 		default:
 			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized %s message type: %T", ModuleName, msg)
 		}
@@ -50,7 +76,265 @@ func handleMsgIPALNodeClaim(ctx sdk.Context, k Keeper, m MsgIPALNodeClaim) (*sdk
 	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
 }
 
+func handleMsgIPALNodeReport(ctx sdk.Context, k Keeper, m MsgIPALNodeReport) (*sdk.Result, error) {
+	err := k.HandleReport(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeReport,
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyReportType, m.ReportType.String()),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+			sdk.NewAttribute(AttributeKeyReporter, m.Reporter.String()),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgIPALNodeUnjail(ctx sdk.Context, k Keeper, m MsgIPALNodeUnjail) (*sdk.Result, error) {
+	err := k.Unjail(ctx, m.OperatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeUnjail,
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgIPALNodeUnclaim(ctx sdk.Context, k Keeper, m MsgIPALNodeUnclaim) (*sdk.Result, error) {
+	node, completionTime, err := k.DoIPALNodeUnclaim(ctx, m.OperatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeUnclaim,
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyAmount, node.Bond.String()),
+			sdk.NewAttribute(AttributeKeyCompletionTime, completionTime.Format(time.RFC3339)),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgIPALNodeEditEndpoints(ctx sdk.Context, k Keeper, m MsgIPALNodeEditEndpoints) (*sdk.Result, error) {
+	err := k.DoIPALNodeEditEndpoints(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeEditEndpoints,
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyEndpoints, strings.ReplaceAll(m.Endpoints.String(), "\n", ",")),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgIPALNodeEditDescription(ctx sdk.Context, k Keeper, m MsgIPALNodeEditDescription) (*sdk.Result, error) {
+	m.TrimSpace()
+
+	err := m.ValidateBasic()
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := k.DoIPALNodeEditDescription(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeEditDescription,
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyMoniker, node.Moniker),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgAddServiceType(ctx sdk.Context, k Keeper, m MsgAddServiceType) (*sdk.Result, error) {
+	if !k.IsProfiler(ctx, m.AddedBy) {
+		return nil, sdkerrors.Wrapf(ErrInvalidProfiler, "%s is not a profiler", m.AddedBy)
+	}
+
+	if _, found := k.GetServiceType(ctx, m.ServiceType.ID); found {
+		return nil, sdkerrors.Wrapf(ErrServiceTypeExists, "service type: %d", m.ServiceType.ID)
+	}
+
+	k.SetServiceType(ctx, m.ServiceType)
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeAddServiceType,
+			sdk.NewAttribute(AttributeKeyServiceType, strconv.FormatUint(m.ServiceType.ID, 10)),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgDeleteServiceType(ctx sdk.Context, k Keeper, m MsgDeleteServiceType) (*sdk.Result, error) {
+	if !k.IsProfiler(ctx, m.DeletedBy) {
+		return nil, sdkerrors.Wrapf(ErrInvalidProfiler, "%s is not a profiler", m.DeletedBy)
+	}
+
+	if _, found := k.GetServiceType(ctx, m.ID); !found {
+		return nil, sdkerrors.Wrapf(ErrServiceTypeUnknown, "service type: %d", m.ID)
+	}
+
+	k.DeleteServiceType(ctx, m.ID)
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeDeleteServiceType,
+			sdk.NewAttribute(AttributeKeyServiceType, strconv.FormatUint(m.ID, 10)),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgWithdrawIPALNodeReward(ctx sdk.Context, k Keeper, m MsgWithdrawIPALNodeReward) (*sdk.Result, error) {
+	rewards, err := k.WithdrawRewards(ctx, m.OperatorAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeWithdrawRewards,
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyAmount, rewards.String()),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgSetIPALWithdrawAddress(ctx sdk.Context, k Keeper, m MsgSetIPALWithdrawAddress) (*sdk.Result, error) {
+	err := k.SetWithdrawAddr(ctx, m.OperatorAddress, m.WithdrawAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeSetWithdrawAddress,
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyWithdrawAddress, m.WithdrawAddress.String()),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgIPALDelegate(ctx sdk.Context, k Keeper, m MsgIPALDelegate) (*sdk.Result, error) {
+	err := k.Delegate(ctx, m.DelegatorAddress, m.OperatorAddress, m.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeDelegate,
+			sdk.NewAttribute(AttributeKeyDelegator, m.DelegatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyAmount, m.Amount.String()),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgIPALUndelegate(ctx sdk.Context, k Keeper, m MsgIPALUndelegate) (*sdk.Result, error) {
+	completionTime, err := k.Undelegate(ctx, m.DelegatorAddress, m.OperatorAddress, m.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvents(sdk.Events{
+		sdk.NewEvent(
+			EventTypeUndelegate,
+			sdk.NewAttribute(AttributeKeyDelegator, m.DelegatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyOperator, m.OperatorAddress.String()),
+			sdk.NewAttribute(AttributeKeyAmount, m.Amount.String()),
+			sdk.NewAttribute(AttributeKeyCompletionTime, completionTime.Format(time.RFC3339)),
+		),
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, AttributeValueCategory),
+		),
+	})
+
+	return &sdk.Result{Events: ctx.EventManager().Events()}, nil
+}
+
+// BeginBlocker funds the reward pool and shares it among the ipal nodes, running after the mint
+// and before the distribution of the fees
+func BeginBlocker(ctx sdk.Context, k keeper.Keeper) {
+	k.AllocateRewards(ctx)
+}
+
 func EndBlocker(ctx sdk.Context, k keeper.Keeper) []abci.ValidatorUpdate {
+	k.HandleAvailabilityWindow(ctx)
+
 	matureUnstakings := k.DequeueAllMatureUnBondingQueue(ctx, ctx.BlockHeader().Time)
 	for _, matureUnstaking := range matureUnstakings {
 		k.DoUnbond(ctx, matureUnstaking)
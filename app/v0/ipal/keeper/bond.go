@@ -63,6 +63,9 @@ func (k Keeper) DequeueAllMatureUnBondingQueue(ctx sdk.Context, curTime time.Tim
 
 		matureUnBondings = append(matureUnBondings, tMatureUnBondings...)
 		store.Delete(itr.Key())
+		for _, v := range tMatureUnBondings {
+			store.Delete(types.GetUnBondingByNodeKey(v.OperatorAddress, v.EndTime))
+		}
 	}
 
 	return matureUnBondings
@@ -76,8 +79,66 @@ func (k Keeper) DoUnbond(ctx sdk.Context, unBonding types.UnBonding) error {
 	return err
 }
 
-func (k Keeper) toUnbondingQueue(ctx sdk.Context, aa sdk.AccAddress, amt sdk.Coin) {
+// toUnbondingQueue moves amt bonded by aa to the ipal node of operator to the unbonding queue
+func (k Keeper) toUnbondingQueue(ctx sdk.Context, aa, operator sdk.AccAddress, amt sdk.Coin) {
 	endTime := ctx.BlockHeader().Time.Add(k.GetUnbondingTime(ctx))
-	unBonding := types.NewUnBonding(aa, amt, endTime)
+	unBonding := types.NewUnBonding(aa, operator, amt, ctx.BlockHeight(), endTime)
 	k.InsertUnBondingQueue(ctx, unBonding, endTime)
+
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetUnBondingByNodeKey(operator, endTime), []byte{})
+}
+
+// slashUnBondings slashes fraction of the bonds unbonded from the ipal node of operator since infractionHeight,
+// still in the unbonding queue, and returns the slashed amount
+func (k Keeper) slashUnBondings(ctx sdk.Context, operator sdk.AccAddress, infractionHeight int64, fraction sdk.Dec) sdk.Int {
+	store := ctx.KVStore(k.storeKey)
+	prefix := types.GetUnBondingsByNodeKey(operator)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	var timeSliceKeys [][]byte
+	for ; iterator.Valid(); iterator.Next() {
+		timeSliceKeys = append(timeSliceKeys, append(sdk.CopyBytes(types.UnBondingKey), iterator.Key()[len(prefix):]...))
+	}
+	iterator.Close()
+
+	slashed := sdk.ZeroInt()
+	for _, key := range timeSliceKeys {
+		indexKey := append(sdk.CopyBytes(prefix), key[len(types.UnBondingKey):]...)
+		value := store.Get(key)
+		if value == nil {
+			store.Delete(indexKey)
+			continue
+		}
+
+		var unBondings, remaining types.UnBondings
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &unBondings)
+		indexed := false
+		for _, unBonding := range unBondings {
+			if !unBonding.OperatorAddress.Equals(operator) {
+				remaining = append(remaining, unBonding)
+				continue
+			}
+
+			if unBonding.CreationHeight >= infractionHeight {
+				amount := unBonding.Amount.Amount.ToDec().Mul(fraction).TruncateInt()
+				unBonding.Amount = unBonding.Amount.Sub(sdk.NewCoin(unBonding.Amount.Denom, amount))
+				slashed = slashed.Add(amount)
+			}
+			if unBonding.Amount.IsPositive() {
+				remaining = append(remaining, unBonding)
+				indexed = true
+			}
+		}
+
+		if !indexed {
+			store.Delete(indexKey)
+		}
+		if len(remaining) == 0 {
+			store.Delete(key)
+			continue
+		}
+		store.Set(key, k.cdc.MustMarshalBinaryLengthPrefixed(remaining))
+	}
+
+	return slashed
 }
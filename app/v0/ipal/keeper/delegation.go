@@ -0,0 +1,187 @@
+package keeper
+
+import (
+	"time"
+
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
+)
+
+// GetDelegation returns the bond delegated by delegator to the ipal node of operator
+func (k Keeper) GetDelegation(ctx sdk.Context, delegator, operator sdk.AccAddress) (delegation types.Delegation, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.GetDelegationKey(delegator, operator))
+	if value == nil {
+		return delegation, false
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &delegation)
+	return delegation, true
+}
+
+// SetDelegation sets a delegation and indexes it by ipal node
+func (k Keeper) SetDelegation(ctx sdk.Context, delegation types.Delegation) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetDelegationKey(delegation.DelegatorAddress, delegation.OperatorAddress), k.cdc.MustMarshalBinaryLengthPrefixed(delegation))
+	store.Set(types.GetDelegationByNodeKey(delegation.OperatorAddress, delegation.DelegatorAddress), []byte{})
+}
+
+func (k Keeper) removeDelegation(ctx sdk.Context, delegation types.Delegation) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetDelegationKey(delegation.DelegatorAddress, delegation.OperatorAddress))
+	store.Delete(types.GetDelegationByNodeKey(delegation.OperatorAddress, delegation.DelegatorAddress))
+	k.deleteBondRewardRatio(ctx, delegation.OperatorAddress, delegation.DelegatorAddress)
+}
+
+// GetDelegatorDelegations returns the delegations of a delegator
+func (k Keeper) GetDelegatorDelegations(ctx sdk.Context, delegator sdk.AccAddress) (delegations types.Delegations) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.GetDelegationsKey(delegator))
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var delegation types.Delegation
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &delegation)
+		delegations = append(delegations, delegation)
+	}
+	return delegations
+}
+
+// GetNodeDelegations returns the delegations to an ipal node
+func (k Keeper) GetNodeDelegations(ctx sdk.Context, operator sdk.AccAddress) (delegations types.Delegations) {
+	store := ctx.KVStore(k.storeKey)
+	prefix := types.GetDelegationsByNodeKey(operator)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		delegator := sdk.AccAddress(iterator.Key()[len(prefix):])
+		if delegation, found := k.GetDelegation(ctx, delegator, operator); found {
+			delegations = append(delegations, delegation)
+		}
+	}
+	return delegations
+}
+
+// IterateDelegations iterates over all the delegations
+func (k Keeper) IterateDelegations(ctx sdk.Context, handler func(delegation types.Delegation) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.DelegationKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var delegation types.Delegation
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &delegation)
+		if handler(delegation) {
+			break
+		}
+	}
+}
+
+// Delegate bonds amount of delegator to the ipal node of operator, the node must not be jailed
+func (k Keeper) Delegate(ctx sdk.Context, delegator, operator sdk.AccAddress, amount sdk.Coin) error {
+	node, found := k.GetIPALNode(ctx, operator)
+	if !found {
+		return sdkerrors.Wrapf(types.ErrIPALNodeNotExist, "operator: %s", operator)
+	}
+
+	if node.Jailed {
+		return sdkerrors.Wrapf(types.ErrIPALNodeJailed, "operator: %s", operator)
+	}
+
+	if delegator.Equals(operator) {
+		return sdkerrors.Wrap(types.ErrInvalidDelegator, "the operator bonds by claiming the node")
+	}
+
+	if amount.Denom != node.Bond.Denom {
+		return sdkerrors.Wrapf(types.ErrBadDenom, "amount denom must be %s", node.Bond.Denom)
+	}
+
+	if err := k.bond(ctx, delegator, amount); err != nil {
+		return err
+	}
+
+	delegation, found := k.GetDelegation(ctx, delegator, operator)
+	if !found {
+		delegation = types.NewDelegation(delegator, operator, sdk.NewCoin(amount.Denom, sdk.ZeroInt()))
+	}
+	k.settleRewards(ctx, node, delegator, delegation.Amount.Amount)
+	delegation.Amount = delegation.Amount.Add(amount)
+	k.SetDelegation(ctx, delegation)
+
+	delegated := node
+	delegated.DelegatedBond = node.GetDelegatedBond().Add(amount)
+	k.updateIPALNode(ctx, node, delegated)
+	return nil
+}
+
+// Undelegate moves amount of the bond delegated by delegator to the ipal node of operator to the unbonding queue,
+// returns the unbonding completion time
+func (k Keeper) Undelegate(ctx sdk.Context, delegator, operator sdk.AccAddress, amount sdk.Coin) (completionTime time.Time, err error) {
+	delegation, found := k.GetDelegation(ctx, delegator, operator)
+	if !found {
+		return completionTime, sdkerrors.Wrapf(types.ErrNoDelegation, "delegator: %s, operator: %s", delegator, operator)
+	}
+
+	if amount.Denom != delegation.Amount.Denom {
+		return completionTime, sdkerrors.Wrapf(types.ErrBadDenom, "amount denom must be %s", delegation.Amount.Denom)
+	}
+
+	if delegation.Amount.IsLT(amount) {
+		return completionTime, sdkerrors.Wrapf(types.ErrDelegationTooLow, "delegation: %s, amount: %s", delegation.Amount, amount)
+	}
+
+	node, found := k.GetIPALNode(ctx, operator)
+	if found {
+		k.settleRewards(ctx, node, delegator, delegation.Amount.Amount)
+	}
+
+	delegation.Amount = delegation.Amount.Sub(amount)
+	k.updateDelegations(ctx, types.Delegations{delegation})
+
+	if found {
+		undelegated := node
+		undelegated.DelegatedBond = node.GetDelegatedBond().Sub(amount)
+		k.updateIPALNode(ctx, node, undelegated)
+	}
+
+	k.toUnbondingQueue(ctx, delegator, operator, amount)
+	return ctx.BlockHeader().Time.Add(k.GetUnbondingTime(ctx)), nil
+}
+
+// unbondDelegations settles the rewards of the delegations to a removed ipal node and moves them to the
+// unbonding queue
+func (k Keeper) unbondDelegations(ctx sdk.Context, node types.IPALNode) {
+	for _, delegation := range k.GetNodeDelegations(ctx, node.OperatorAddress) {
+		k.settleRewards(ctx, node, delegation.DelegatorAddress, delegation.Amount.Amount)
+		if delegation.Amount.IsPositive() {
+			k.toUnbondingQueue(ctx, delegation.DelegatorAddress, node.OperatorAddress, delegation.Amount)
+		}
+		k.removeDelegation(ctx, delegation)
+	}
+}
+
+// slashDelegations returns the delegations less fraction of their amount, and the slashed amount
+func slashDelegations(delegations types.Delegations, fraction sdk.Dec) (types.Delegations, sdk.Int) {
+	slashed := sdk.ZeroInt()
+	slashedDelegations := make(types.Delegations, len(delegations))
+	for i, delegation := range delegations {
+		amount := delegation.Amount.Amount.ToDec().Mul(fraction).TruncateInt()
+		delegation.Amount = delegation.Amount.Sub(sdk.NewCoin(delegation.Amount.Denom, amount))
+		slashedDelegations[i] = delegation
+		slashed = slashed.Add(amount)
+	}
+	return slashedDelegations, slashed
+}
+
+// updateDelegations sets the delegations, the empty ones are removed
+func (k Keeper) updateDelegations(ctx sdk.Context, delegations types.Delegations) {
+	for _, delegation := range delegations {
+		if delegation.Amount.IsZero() {
+			k.removeDelegation(ctx, delegation)
+			continue
+		}
+		k.SetDelegation(ctx, delegation)
+	}
+}
@@ -0,0 +1,153 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/netcloth/netcloth-chain/app/v0/auth"
+	"github.com/netcloth/netcloth-chain/app/v0/guardian"
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestDelegation(t *testing.T) {
+	ctx, k, _, _, addrs := createTestInput(t)
+	operator, delegator1, delegator2 := addrs[0], addrs[1], addrs[2]
+	bond := k.GetMinBond(ctx)
+	amount := sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(2))
+
+	err := k.Delegate(ctx, delegator1, operator, amount)
+	require.True(t, types.ErrIPALNodeNotExist.Is(err))
+
+	claimNode(t, ctx, k, operator, bond)
+	err = k.Delegate(ctx, operator, operator, amount)
+	require.True(t, types.ErrInvalidDelegator.Is(err))
+
+	// the delegations add to the bond ordering the node
+	require.NoError(t, k.Delegate(ctx, delegator1, operator, amount))
+	require.NoError(t, k.Delegate(ctx, delegator1, operator, bond))
+	require.NoError(t, k.Delegate(ctx, delegator2, operator, bond))
+	node, _ := k.GetIPALNode(ctx, operator)
+	require.Equal(t, bond, node.Bond)
+	require.Equal(t, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(4)), node.DelegatedBond)
+	require.Equal(t, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(5)), node.TotalBond())
+	require.Equal(t, types.IPALNodes{node}, k.GetAllIPALNodes(ctx))
+
+	delegation, found := k.GetDelegation(ctx, delegator1, operator)
+	require.True(t, found)
+	require.Equal(t, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(3)), delegation.Amount)
+	require.Len(t, k.GetNodeDelegations(ctx, operator), 2)
+	require.Equal(t, types.Delegations{delegation}, k.GetDelegatorDelegations(ctx, delegator1))
+
+	// the undelegated bond goes through the unbonding queue
+	_, err = k.Undelegate(ctx, delegator1, operator, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(4)))
+	require.True(t, types.ErrDelegationTooLow.Is(err))
+	completionTime, err := k.Undelegate(ctx, delegator1, operator, delegation.Amount)
+	require.NoError(t, err)
+	require.Equal(t, ctx.BlockHeader().Time.Add(k.GetUnbondingTime(ctx)), completionTime)
+	require.Equal(t, types.UnBondings{types.NewUnBonding(delegator1, operator, delegation.Amount, ctx.BlockHeight(), completionTime)}, k.GetUnBondingQueueTimeSlice(ctx, completionTime))
+	_, found = k.GetDelegation(ctx, delegator1, operator)
+	require.False(t, found)
+	_, err = k.Undelegate(ctx, delegator1, operator, bond)
+	require.True(t, types.ErrNoDelegation.Is(err))
+
+	node, _ = k.GetIPALNode(ctx, operator)
+	require.Equal(t, bond, node.DelegatedBond)
+
+	// the delegations of an unclaimed node are unbonded
+	_, _, err = k.DoIPALNodeUnclaim(ctx, operator)
+	require.NoError(t, err)
+	require.Empty(t, k.GetNodeDelegations(ctx, operator))
+	require.Len(t, k.GetUnBondingQueueTimeSlice(ctx, completionTime), 3)
+}
+
+func TestDelegationSlash(t *testing.T) {
+	ctx, k, gk, sk, addrs := createTestInput(t)
+	operator, reporter, delegator := addrs[0], addrs[1], addrs[2]
+	gk.AddReporter(ctx, guardian.NewGuardian("reporter", guardian.Ordinary, reporter, reporter))
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator, bond)
+	require.NoError(t, k.Delegate(ctx, delegator, operator, bond))
+
+	require.NoError(t, k.HandleReport(ctx, types.NewMsgIPALNodeReport(reporter, operator, types.ReportMisbehaviour, "")))
+	slashed := bond.Amount.ToDec().Mul(k.GetSlashFractionMisbehaviour(ctx)).TruncateInt()
+	node, _ := k.GetIPALNode(ctx, operator)
+	require.Equal(t, bond.Amount.Sub(slashed), node.Bond.Amount)
+	require.Equal(t, bond.Amount.Sub(slashed), node.DelegatedBond.Amount)
+	delegation, _ := k.GetDelegation(ctx, delegator, operator)
+	require.Equal(t, bond.Amount.Sub(slashed), delegation.Amount.Amount)
+	require.Equal(t, slashed.MulRaw(2), sk.GetModuleAccount(ctx, auth.FeeCollectorName).GetCoins().AmountOf(sdk.NativeTokenName))
+
+	// a jailed node takes no delegations
+	err := k.Delegate(ctx, delegator, operator, bond)
+	require.True(t, types.ErrIPALNodeJailed.Is(err))
+}
+
+func TestUnBondingSlash(t *testing.T) {
+	ctx, k, gk, sk, addrs := createTestInput(t)
+	operator, other, delegator, reporter := addrs[0], addrs[1], addrs[2], addrs[3]
+	gk.AddReporter(ctx, guardian.NewGuardian("reporter", guardian.Ordinary, reporter, reporter))
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(2)))
+	claimNode(t, ctx, k, other, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(2)))
+	require.NoError(t, k.Delegate(ctx, delegator, operator, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(2))))
+
+	// unbonded before the liveness window of the report
+	ctx = ctx.WithBlockHeight(5)
+	_, err := k.Undelegate(ctx, delegator, operator, bond)
+	require.NoError(t, err)
+
+	// unbonded within the liveness window of the report
+	ctx = ctx.WithBlockHeight(20)
+	completionTime, err := k.Undelegate(ctx, delegator, operator, bond)
+	require.NoError(t, err)
+	claimNode(t, ctx, k, operator, bond)
+	claimNode(t, ctx, k, other, bond)
+
+	ctx = ctx.WithBlockHeight(k.GetLivenessWindow(ctx)*k.GetAvailabilityWindow(ctx) + 19)
+	require.NoError(t, k.HandleReport(ctx, types.NewMsgIPALNodeReport(reporter, operator, types.ReportMisbehaviour, "")))
+
+	// the bonds unbonded from the node since the start of the liveness window are slashed
+	slashed := bond.Amount.ToDec().Mul(k.GetSlashFractionMisbehaviour(ctx)).TruncateInt()
+	slashedBond := sdk.NewCoin(bond.Denom, bond.Amount.Sub(slashed))
+	require.Equal(t, types.UnBondings{
+		types.NewUnBonding(delegator, operator, bond, 5, completionTime),
+		types.NewUnBonding(delegator, operator, slashedBond, 20, completionTime),
+		types.NewUnBonding(operator, operator, slashedBond, 20, completionTime),
+		types.NewUnBonding(other, other, bond, 20, completionTime),
+	}, k.GetUnBondingQueueTimeSlice(ctx, completionTime))
+
+	// the self-bond left is slashed too, the delegation undelegated in full is gone
+	require.Equal(t, slashed.MulRaw(3), sk.GetModuleAccount(ctx, auth.FeeCollectorName).GetCoins().AmountOf(sdk.NativeTokenName))
+
+	// the slashed bonds are unbonded
+	ctx = ctx.WithBlockTime(completionTime)
+	unBondings := k.DequeueAllMatureUnBondingQueue(ctx, completionTime)
+	require.Len(t, unBondings, 4)
+	require.Empty(t, k.GetUnBondingQueueTimeSlice(ctx, completionTime))
+	require.Zero(t, k.slashUnBondings(ctx, operator, 0, sdk.OneDec()).Int64())
+}
+
+func TestDelegationRewards(t *testing.T) {
+	ctx, k, _, sk, addrs := createTestInput(t)
+	operator, delegator, payer := addrs[0], addrs[1], addrs[3]
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator, bond)
+	require.NoError(t, k.Delegate(ctx, delegator, operator, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(3))))
+
+	fees := sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(5*sdk.NativeTokenFraction)))
+	require.NoError(t, sk.SendCoinsFromAccountToModule(ctx, payer, auth.FeeCollectorName, fees))
+	k.AllocateRewards(ctx)
+
+	pool := fees.AmountOf(sdk.NativeTokenName).ToDec().Mul(k.GetRewardFraction(ctx)).TruncateInt()
+	require.Equal(t, pool.QuoRaw(4), k.GetRewards(ctx, operator).AmountOf(sdk.NativeTokenName))
+	require.Equal(t, pool.QuoRaw(4).MulRaw(3), k.GetRewards(ctx, delegator).AmountOf(sdk.NativeTokenName))
+
+	rewards, err := k.WithdrawRewards(ctx, delegator)
+	require.NoError(t, err)
+	require.Equal(t, pool.QuoRaw(4).MulRaw(3), rewards.AmountOf(sdk.NativeTokenName))
+}
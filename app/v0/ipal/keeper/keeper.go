@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
 	"github.com/netcloth/netcloth-chain/app/v0/params"
@@ -12,26 +13,45 @@ import (
 
 // Keeper defines the ipal store
 type Keeper struct {
-	storeKey     sdk.StoreKey
-	cdc          *codec.Codec
-	supplyKeeper types.SupplyKeeper
-	paramstore   params.Subspace
+	storeKey         sdk.StoreKey
+	cdc              *codec.Codec
+	supplyKeeper     types.SupplyKeeper
+	guardianKeeper   types.GuardianKeeper
+	cipalKeeper      types.CIPALKeeper
+	paramstore       params.Subspace
+	feeCollectorName string // name of the FeeCollector ModuleAccount receiving the slashed bonds and funding the rewards
+	blacklistedAddrs map[string]bool
 }
 
 // NewKeeper creates a new ipal Keeper instance
-func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec, supplyKeeper types.SupplyKeeper, paramstore params.Subspace) Keeper {
+func NewKeeper(storeKey sdk.StoreKey, cdc *codec.Codec, supplyKeeper types.SupplyKeeper, guardianKeeper types.GuardianKeeper,
+	paramstore params.Subspace, feeCollectorName string, blacklistedAddrs map[string]bool) Keeper {
 	if addr := supplyKeeper.GetModuleAddress(types.ModuleName); addr == nil {
 		panic(fmt.Sprintf("%s module account has not been set", types.ModuleName))
 	}
 
+	if addr := supplyKeeper.GetModuleAddress(types.RewardPoolName); addr == nil {
+		panic(fmt.Sprintf("%s module account has not been set", types.RewardPoolName))
+	}
+
 	return Keeper{
-		storeKey:     storeKey,
-		cdc:          cdc,
-		supplyKeeper: supplyKeeper,
-		paramstore:   paramstore.WithKeyTable(ParamKeyTable()),
+		storeKey:         storeKey,
+		cdc:              cdc,
+		supplyKeeper:     supplyKeeper,
+		guardianKeeper:   guardianKeeper,
+		paramstore:       paramstore.WithKeyTable(ParamKeyTable()),
+		feeCollectorName: feeCollectorName,
+		blacklistedAddrs: blacklistedAddrs,
 	}
 }
 
+// WithCIPALKeeper returns the keeper accepting the downtime reports of the cipal users of the ipal
+// nodes held by cipalKeeper, the cipal keeper depending on the service types of the ipal keeper
+func (k Keeper) WithCIPALKeeper(cipalKeeper types.CIPALKeeper) Keeper {
+	k.cipalKeeper = cipalKeeper
+	return k
+}
+
 // GetIPALNode returns a IPAL object by operator address
 func (k Keeper) GetIPALNode(ctx sdk.Context, operator sdk.AccAddress) (obj types.IPALNode, found bool) {
 	store := ctx.KVStore(k.storeKey)
@@ -82,27 +102,35 @@ func (k Keeper) delIPALNodeByMonikerIndex(ctx sdk.Context, moniker string) {
 	store.Delete(types.GetIPALNodeByMonikerKey(moniker))
 }
 
-// CreateIPALNode sets a new IPAL object
+// CreateIPALNode sets a new IPAL object, a jailed one is left out of the bond index
 func (k Keeper) CreateIPALNode(ctx sdk.Context, node types.IPALNode) {
+	k.initNodeRewards(ctx, node)
 	k.setIPALNode(ctx, node)
-	k.setIPALNodeByBond(ctx, node)
+	if !node.Jailed {
+		k.setIPALNodeByBond(ctx, node)
+	}
 	k.setIPALNodeByMonikerIndex(ctx, node)
 }
 
 func (k Keeper) updateIPALNode(ctx sdk.Context, old types.IPALNode, new types.IPALNode) {
+	k.updateNodeRewards(ctx, old, new)
 	k.setIPALNode(ctx, new)
 
 	k.delIPALNodeByBond(ctx, old)
-	k.setIPALNodeByBond(ctx, new)
+	if !new.Jailed {
+		k.setIPALNodeByBond(ctx, new)
+	}
 
 	k.delIPALNodeByMonikerIndex(ctx, old.Moniker)
 	k.setIPALNodeByMonikerIndex(ctx, new)
 }
 
 func (k Keeper) deleteIPALNode(ctx sdk.Context, obj types.IPALNode) {
+	k.deleteNodeRewards(ctx, obj)
 	k.delIPALNode(ctx, obj.OperatorAddress)
 	k.delIPALNodeByBond(ctx, obj)
 	k.delIPALNodeByMonikerIndex(ctx, obj.Moniker)
+	k.deleteLiveness(ctx, obj.OperatorAddress)
 }
 
 func (k Keeper) bond(ctx sdk.Context, aa sdk.AccAddress, amt sdk.Coin) error {
@@ -112,8 +140,15 @@ func (k Keeper) bond(ctx sdk.Context, aa sdk.AccAddress, amt sdk.Coin) error {
 // DoIPALNodeClaim - updates ipal object and bond coins
 func (k Keeper) DoIPALNodeClaim(ctx sdk.Context, m types.MsgIPALNodeClaim) (err error) {
 	minBond := k.GetMinBond(ctx)
+	if m.Bond.IsGTE(minBond) {
+		if err := k.ValidateEndpoints(ctx, m.Endpoints); err != nil {
+			return err
+		}
+	}
+
 	n, found := k.GetIPALNode(ctx, m.OperatorAddress)
 	if found {
+		k.settleRewards(ctx, n, n.OperatorAddress, n.Bond.Amount)
 		if m.Bond.IsGTE(minBond) {
 			if n.Bond.IsLT(m.Bond) {
 				err := k.bond(ctx, m.OperatorAddress, m.Bond.Sub(n.Bond))
@@ -121,13 +156,16 @@ func (k Keeper) DoIPALNodeClaim(ctx sdk.Context, m types.MsgIPALNodeClaim) (err
 					return err
 				}
 			} else if m.Bond.IsLT(n.Bond) {
-				k.toUnbondingQueue(ctx, m.OperatorAddress, n.Bond.Sub(m.Bond))
+				k.toUnbondingQueue(ctx, m.OperatorAddress, m.OperatorAddress, n.Bond.Sub(m.Bond))
 			}
 
 			ipalNode := types.NewIPALNode(m.OperatorAddress, m.Moniker, m.Website, m.Details, m.Extension, m.Endpoints, m.Bond)
+			ipalNode.Jailed = n.Jailed
+			ipalNode.DelegatedBond = n.GetDelegatedBond()
 			k.updateIPALNode(ctx, n, ipalNode)
 		} else {
-			k.toUnbondingQueue(ctx, m.OperatorAddress, n.Bond)
+			k.toUnbondingQueue(ctx, m.OperatorAddress, m.OperatorAddress, n.Bond)
+			k.unbondDelegations(ctx, n)
 			k.deleteIPALNode(ctx, n)
 		}
 	} else {
@@ -147,7 +185,59 @@ func (k Keeper) DoIPALNodeClaim(ctx sdk.Context, m types.MsgIPALNodeClaim) (err
 	return nil
 }
 
-// GetAllIPALNodes - lists all ipal objects
+// DoIPALNodeUnclaim - deletes the ipal object and unbonds its whole bond and the delegations, returns the unbonding
+// completion time
+func (k Keeper) DoIPALNodeUnclaim(ctx sdk.Context, operator sdk.AccAddress) (node types.IPALNode, completionTime time.Time, err error) {
+	node, found := k.GetIPALNode(ctx, operator)
+	if !found {
+		return node, completionTime, sdkerrors.Wrapf(types.ErrIPALNodeNotExist, "operator: %s", operator)
+	}
+
+	completionTime = ctx.BlockHeader().Time.Add(k.GetUnbondingTime(ctx))
+	k.settleRewards(ctx, node, operator, node.Bond.Amount)
+	if node.Bond.IsPositive() {
+		k.toUnbondingQueue(ctx, operator, operator, node.Bond)
+	}
+	k.unbondDelegations(ctx, node)
+	k.deleteIPALNode(ctx, node)
+	return node, completionTime, nil
+}
+
+// DoIPALNodeEditEndpoints - replaces the endpoints of the ipal object
+func (k Keeper) DoIPALNodeEditEndpoints(ctx sdk.Context, m types.MsgIPALNodeEditEndpoints) error {
+	node, found := k.GetIPALNode(ctx, m.OperatorAddress)
+	if !found {
+		return sdkerrors.Wrapf(types.ErrIPALNodeNotExist, "operator: %s", m.OperatorAddress)
+	}
+
+	if err := k.ValidateEndpoints(ctx, m.Endpoints); err != nil {
+		return err
+	}
+
+	edited := node
+	edited.Endpoints = m.Endpoints
+	k.updateIPALNode(ctx, node, edited)
+	return nil
+}
+
+// DoIPALNodeEditDescription - updates the moniker, website, details and extension of the ipal object
+func (k Keeper) DoIPALNodeEditDescription(ctx sdk.Context, m types.MsgIPALNodeEditDescription) (types.IPALNode, error) {
+	node, found := k.GetIPALNode(ctx, m.OperatorAddress)
+	if !found {
+		return node, sdkerrors.Wrapf(types.ErrIPALNodeNotExist, "operator: %s", m.OperatorAddress)
+	}
+
+	edited := node.UpdateDescription(m)
+	acc, monikerExist := k.GetIPALNodeAddByMoniker(ctx, edited.Moniker)
+	if monikerExist && !acc.Equals(m.OperatorAddress) {
+		return node, sdkerrors.Wrapf(types.ErrMonikerExist, "moniker: [%s] already exist", edited.Moniker)
+	}
+
+	k.updateIPALNode(ctx, node, edited)
+	return edited, nil
+}
+
+// GetAllIPALNodes - lists all ipal objects but the jailed ones, by bond
 func (k Keeper) GetAllIPALNodes(ctx sdk.Context) (ipalNodes types.IPALNodes) {
 	store := ctx.KVStore(k.storeKey)
 	iterator := sdk.KVStorePrefixIterator(store, types.IPALNodeByBondKey)
@@ -159,3 +249,16 @@ func (k Keeper) GetAllIPALNodes(ctx sdk.Context) (ipalNodes types.IPALNodes) {
 	}
 	return ipalNodes
 }
+
+// IterateIPALNodes iterates over all ipal objects, the jailed ones included
+func (k Keeper) IterateIPALNodes(ctx sdk.Context, handler func(node types.IPALNode) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.IPALNodeKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		if handler(types.MustUnmarshalIPALNode(k.cdc, iterator.Value())) {
+			break
+		}
+	}
+}
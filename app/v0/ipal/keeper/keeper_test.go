@@ -0,0 +1,113 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestIPALNodeUnclaim(t *testing.T) {
+	ctx, k, _, _, addrs := createTestInput(t)
+	operator := addrs[0]
+
+	_, _, err := k.DoIPALNodeUnclaim(ctx, operator)
+	require.True(t, types.ErrIPALNodeNotExist.Is(err))
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator, bond)
+
+	node, completionTime, err := k.DoIPALNodeUnclaim(ctx, operator)
+	require.NoError(t, err)
+	require.Equal(t, bond, node.Bond)
+	require.Equal(t, ctx.BlockHeader().Time.Add(k.GetUnbondingTime(ctx)), completionTime)
+
+	_, found := k.GetIPALNode(ctx, operator)
+	require.False(t, found)
+	require.Empty(t, k.GetAllIPALNodes(ctx))
+	_, found = k.GetIPALNodeAddByMoniker(ctx, operator.String())
+	require.False(t, found)
+
+	unBondings := k.GetUnBondingQueueTimeSlice(ctx, completionTime)
+	require.Equal(t, types.UnBondings{types.NewUnBonding(operator, operator, bond, ctx.BlockHeight(), completionTime)}, unBondings)
+}
+
+func TestIPALNodeEdit(t *testing.T) {
+	ctx, k, _, _, addrs := createTestInput(t)
+	operator, other := addrs[0], addrs[1]
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator, bond)
+	claimNode(t, ctx, k, other, bond)
+
+	endpoints := types.Endpoints{types.NewEndpoint(2, "192.168.1.101:20000")}
+	require.NoError(t, k.DoIPALNodeEditEndpoints(ctx, types.NewMsgIPALNodeEditEndpoints(operator, endpoints)))
+	node, _ := k.GetIPALNode(ctx, operator)
+	require.Equal(t, endpoints, node.Endpoints)
+	require.Equal(t, bond, node.Bond)
+
+	err := k.DoIPALNodeEditEndpoints(ctx, types.NewMsgIPALNodeEditEndpoints(addrs[2], endpoints))
+	require.True(t, types.ErrIPALNodeNotExist.Is(err))
+
+	// the moniker of another node can't be taken
+	msg := types.NewMsgIPALNodeEditDescription(operator, other.String(), types.DoNotModifyDesc, types.DoNotModifyDesc, types.DoNotModifyDesc)
+	_, err = k.DoIPALNodeEditDescription(ctx, msg)
+	require.True(t, types.ErrMonikerExist.Is(err))
+
+	msg = types.NewMsgIPALNodeEditDescription(operator, "renamed", "https://netcloth.org", types.DoNotModifyDesc, types.DoNotModifyDesc)
+	node, err = k.DoIPALNodeEditDescription(ctx, msg)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", node.Moniker)
+	require.Equal(t, "https://netcloth.org", node.Website)
+	require.Equal(t, endpoints, node.Endpoints)
+
+	acc, found := k.GetIPALNodeAddByMoniker(ctx, "renamed")
+	require.True(t, found)
+	require.Equal(t, operator, acc)
+	_, found = k.GetIPALNodeAddByMoniker(ctx, operator.String())
+	require.False(t, found)
+
+	nodes := k.GetAllIPALNodes(ctx)
+	require.Len(t, nodes, 2)
+	for _, n := range nodes {
+		require.True(t, n.Bond.IsEqual(bond))
+	}
+}
+
+func TestServiceTypeRegistry(t *testing.T) {
+	ctx, k, _, _, addrs := createTestInput(t)
+	operator := addrs[0]
+	bond := k.GetMinBond(ctx)
+
+	// any endpoint is accepted until a service type is registered
+	endpoints := types.Endpoints{types.NewEndpoint(7, "not an endpoint")}
+	require.NoError(t, k.DoIPALNodeClaim(ctx, types.NewMsgIPALNodeClaim(operator, "node", "", "", "", endpoints, bond)))
+
+	for _, serviceType := range types.DefaultServiceTypes() {
+		k.SetServiceType(ctx, serviceType)
+	}
+	require.Equal(t, types.DefaultServiceTypes(), k.GetAllServiceTypes(ctx))
+
+	err := k.DoIPALNodeEditEndpoints(ctx, types.NewMsgIPALNodeEditEndpoints(operator, endpoints))
+	require.True(t, types.ErrServiceTypeUnknown.Is(err))
+
+	endpoints = types.Endpoints{types.NewEndpoint(uint64(types.Chatting), "not an endpoint")}
+	err = k.DoIPALNodeEditEndpoints(ctx, types.NewMsgIPALNodeEditEndpoints(operator, endpoints))
+	require.True(t, types.ErrEndpointsFormat.Is(err))
+
+	endpoints = types.Endpoints{
+		types.NewEndpoint(uint64(types.Chatting), "192.168.1.100:10000"),
+		types.NewEndpoint(uint64(types.Storage), "https://storage.netcloth.org/files"),
+	}
+	require.NoError(t, k.DoIPALNodeEditEndpoints(ctx, types.NewMsgIPALNodeEditEndpoints(operator, endpoints)))
+
+	// the claims below the min bond leave without checking the endpoints
+	k.DeleteServiceType(ctx, uint64(types.Storage))
+	_, found := k.GetServiceType(ctx, uint64(types.Storage))
+	require.False(t, found)
+	err = k.DoIPALNodeClaim(ctx, types.NewMsgIPALNodeClaim(operator, "node", "", "", "", endpoints, bond))
+	require.True(t, types.ErrServiceTypeUnknown.Is(err))
+	require.NoError(t, k.DoIPALNodeClaim(ctx, types.NewMsgIPALNodeClaim(operator, "node", "", "", "", endpoints, sdk.NewCoin(bond.Denom, sdk.ZeroInt()))))
+}
@@ -0,0 +1,349 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
+)
+
+// GetLivenessInfo returns the liveness info of an ipal node by operator address
+func (k Keeper) GetLivenessInfo(ctx sdk.Context, operator sdk.AccAddress) (info types.LivenessInfo, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetLivenessInfoKey(operator))
+	if bz == nil {
+		return info, false
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &info)
+	return info, true
+}
+
+func (k Keeper) SetLivenessInfo(ctx sdk.Context, info types.LivenessInfo) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(info)
+	store.Set(types.GetLivenessInfoKey(info.Address), bz)
+}
+
+// IterateLivenessInfos iterates over the liveness infos of all ipal nodes
+func (k Keeper) IterateLivenessInfos(ctx sdk.Context, handler func(info types.LivenessInfo) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iter := sdk.KVStorePrefixIterator(store, types.LivenessInfoKey)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var info types.LivenessInfo
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iter.Value(), &info)
+		if handler(info) {
+			break
+		}
+	}
+}
+
+func (k Keeper) getMissedWindowBitArray(ctx sdk.Context, operator sdk.AccAddress, index int64) (missed bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(types.GetMissedWindowBitArrayKey(operator, index))
+	if bz == nil {
+		// lazy: treat empty key as not missed
+		return false
+	}
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &missed)
+	return
+}
+
+// SetMissedWindowBitArray sets whether an ipal node missed the window at index of its missed window bit array
+func (k Keeper) SetMissedWindowBitArray(ctx sdk.Context, operator sdk.AccAddress, index int64, missed bool) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.cdc.MustMarshalBinaryLengthPrefixed(missed)
+	store.Set(types.GetMissedWindowBitArrayKey(operator, index), bz)
+}
+
+// IterateMissedWindowBitArray iterates over the missed window bit array of an ipal node
+func (k Keeper) IterateMissedWindowBitArray(ctx sdk.Context, operator sdk.AccAddress, handler func(index int64, missed bool) (stop bool)) {
+	// Array may be sparse
+	for index := int64(0); index < k.GetLivenessWindow(ctx); index++ {
+		store := ctx.KVStore(k.storeKey)
+		bz := store.Get(types.GetMissedWindowBitArrayKey(operator, index))
+		if bz == nil {
+			continue
+		}
+
+		var missed bool
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(bz, &missed)
+		if handler(index, missed) {
+			break
+		}
+	}
+}
+
+func (k Keeper) clearMissedWindowBitArray(ctx sdk.Context, operator sdk.AccAddress) {
+	deletePrefix(ctx.KVStore(k.storeKey), types.GetMissedWindowBitArrayPrefixKey(operator))
+}
+
+// deleteLiveness deletes the liveness info, the missed windows and the reports of the window
+// in progress of an ipal node
+func (k Keeper) deleteLiveness(ctx sdk.Context, operator sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetLivenessInfoKey(operator))
+	store.Delete(types.GetWindowDowntimeKey(operator))
+	deletePrefix(store, types.GetMissedWindowBitArrayPrefixKey(operator))
+	deletePrefix(store, types.GetWindowUserReportPrefixKey(operator))
+}
+
+func deletePrefix(store sdk.KVStore, prefix []byte) {
+	iter := sdk.KVStorePrefixIterator(store, prefix)
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	iter.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
+
+// livenessWindowStart returns the first height of the liveness window ending at the current block, the height the
+// infractions of the ipal nodes are slashed from: the reports carry no height, a node is slashed for the windows
+// it missed, or the misbehaviour reported, over the whole liveness window
+func (k Keeper) livenessWindowStart(ctx sdk.Context) int64 {
+	return ctx.BlockHeight() - k.GetLivenessWindow(ctx)*k.GetAvailabilityWindow(ctx) + 1
+}
+
+// livenessEnabled returns whether the liveness of the ipal nodes is tracked
+func (k Keeper) livenessEnabled(ctx sdk.Context) bool {
+	return k.GetAvailabilityWindow(ctx) > 0 && k.GetLivenessWindow(ctx) > 0
+}
+
+// IsReporter returns whether addr is one of the reporters of the guardian module
+func (k Keeper) IsReporter(ctx sdk.Context, addr sdk.AccAddress) bool {
+	if k.guardianKeeper == nil {
+		return false
+	}
+	_, found := k.guardianKeeper.GetReporter(ctx, addr)
+	return found
+}
+
+// HandleReport handles the report of an ipal node: the downtime reported by a reporter, or by
+// UserReportThreshold users served by the node, marks the window in progress missed, the
+// misbehaviour reported by a reporter slashes and jails the node
+func (k Keeper) HandleReport(ctx sdk.Context, m types.MsgIPALNodeReport) error {
+	if !k.livenessEnabled(ctx) {
+		return types.ErrLivenessDisabled
+	}
+
+	node, found := k.GetIPALNode(ctx, m.OperatorAddress)
+	if !found {
+		return sdkerrors.Wrapf(types.ErrIPALNodeNotExist, "operator: %s", m.OperatorAddress)
+	}
+
+	if node.Jailed {
+		return sdkerrors.Wrapf(types.ErrIPALNodeJailed, "operator: %s", m.OperatorAddress)
+	}
+
+	isReporter := k.IsReporter(ctx, m.Reporter)
+	switch m.ReportType {
+	case types.ReportMisbehaviour:
+		if !isReporter {
+			return sdkerrors.Wrapf(types.ErrInvalidReporter, "%s is not a reporter, only reporters report misbehaviour", m.Reporter)
+		}
+		return k.slashAndJail(ctx, node, k.GetSlashFractionMisbehaviour(ctx), k.livenessWindowStart(ctx), k.GetMisbehaviourJailDuration(ctx), types.ReportMisbehaviour.String())
+
+	case types.ReportDowntime:
+		store := ctx.KVStore(k.storeKey)
+		if isReporter {
+			store.Set(types.GetWindowDowntimeKey(m.OperatorAddress), []byte{0x01})
+			return nil
+		}
+
+		threshold := k.GetUserReportThreshold(ctx)
+		if threshold == 0 {
+			return sdkerrors.Wrapf(types.ErrInvalidReporter, "%s is not a reporter, the downtime reports of users are disabled", m.Reporter)
+		}
+
+		if !k.isServiceUser(ctx, m.Reporter, node) {
+			return sdkerrors.Wrapf(types.ErrInvalidReporter, "%s is neither a reporter nor a cipal user of %s", m.Reporter, m.OperatorAddress)
+		}
+
+		key := types.GetWindowUserReportKey(m.OperatorAddress, m.Reporter)
+		if store.Has(key) {
+			return sdkerrors.Wrapf(types.ErrDuplicateReport, "%s already reported %s in this window", m.Reporter, m.OperatorAddress)
+		}
+		store.Set(key, []byte{0x01})
+
+		if k.countWindowUserReports(ctx, m.OperatorAddress) >= threshold {
+			store.Set(types.GetWindowDowntimeKey(m.OperatorAddress), []byte{0x01})
+		}
+		return nil
+
+	default:
+		return sdkerrors.Wrapf(types.ErrInvalidReportType, "report type: %s", m.ReportType)
+	}
+}
+
+// isServiceUser returns whether the cipal object of addr points to the operator address or
+// one of the endpoints of node
+func (k Keeper) isServiceUser(ctx sdk.Context, addr sdk.AccAddress, node types.IPALNode) bool {
+	if k.cipalKeeper == nil {
+		return false
+	}
+
+	obj, found := k.cipalKeeper.GetCIPALObject(ctx, addr.String())
+	if !found {
+		return false
+	}
+
+	for _, info := range obj.ServiceInfos {
+		if info.Address == node.OperatorAddress.String() {
+			return true
+		}
+		for _, endpoint := range node.Endpoints {
+			if info.Address == endpoint.Endpoint {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (k Keeper) countWindowUserReports(ctx sdk.Context, operator sdk.AccAddress) (count int64) {
+	iter := sdk.KVStorePrefixIterator(ctx.KVStore(k.storeKey), types.GetWindowUserReportPrefixKey(operator))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		count++
+	}
+	return count
+}
+
+// HandleAvailabilityWindow closes the availability window at the end of its last block: the window
+// is recorded in the missed window bit array of every ipal node not jailed, and the nodes missing
+// more windows than allowed over the liveness window are slashed and jailed
+func (k Keeper) HandleAvailabilityWindow(ctx sdk.Context) {
+	if !k.livenessEnabled(ctx) || ctx.BlockHeight()%k.GetAvailabilityWindow(ctx) != 0 {
+		return
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	livenessWindow := k.GetLivenessWindow(ctx)
+	minAvailable := k.GetMinAvailablePerWindow(ctx).MulInt64(livenessWindow).RoundInt64()
+	maxMissed := livenessWindow - minAvailable
+
+	var downNodes []types.IPALNode
+	for _, node := range k.GetAllIPALNodes(ctx) {
+		addr := node.OperatorAddress
+		info, found := k.GetLivenessInfo(ctx, addr)
+		if !found {
+			info = types.NewLivenessInfo(addr, 0, time.Unix(0, 0).UTC(), 0)
+		}
+
+		// this is a relative index, so it counts windows the node has been tracked for
+		index := info.IndexOffset % livenessWindow
+		info.IndexOffset++
+
+		// update the bit array and the counter of the missed windows
+		previous := k.getMissedWindowBitArray(ctx, addr, index)
+		missed := store.Has(types.GetWindowDowntimeKey(addr))
+		switch {
+		case !previous && missed:
+			k.SetMissedWindowBitArray(ctx, addr, index, true)
+			info.MissedWindowsCounter++
+		case previous && !missed:
+			k.SetMissedWindowBitArray(ctx, addr, index, false)
+			info.MissedWindowsCounter--
+		}
+
+		if missed {
+			ctx.Logger().Info(fmt.Sprintf("ipal node %s missed an availability window, %d/%d in the liveness window",
+				addr, info.MissedWindowsCounter, livenessWindow))
+		}
+
+		k.SetLivenessInfo(ctx, info)
+
+		// the node is given a whole liveness window before being jailed
+		if info.IndexOffset >= livenessWindow && info.MissedWindowsCounter > maxMissed {
+			downNodes = append(downNodes, node)
+		}
+	}
+
+	deletePrefix(store, types.WindowDowntimeKey)
+	deletePrefix(store, types.WindowUserReportKey)
+
+	for _, node := range downNodes {
+		if err := k.slashAndJail(ctx, node, k.GetSlashFractionDowntime(ctx), k.livenessWindowStart(ctx), k.GetDowntimeJailDuration(ctx), types.ReportDowntime.String()); err != nil {
+			ctx.Logger().Error(fmt.Sprintf("failed to slash ipal node %s for downtime, err: %s", node.OperatorAddress, err.Error()))
+		}
+	}
+}
+
+// slashAndJail sends the fraction of the bond of an ipal node, of its delegations and of the bonds unbonded from the node
+// since infractionHeight to the fee collector, jails the node until jailDuration is over and starts its liveness over
+func (k Keeper) slashAndJail(ctx sdk.Context, node types.IPALNode, fraction sdk.Dec, infractionHeight int64, jailDuration time.Duration, reason string) error {
+	nodeDelegations := k.GetNodeDelegations(ctx, node.OperatorAddress)
+	k.settleNodeRewards(ctx, node, nodeDelegations)
+
+	selfSlashed := sdk.NewCoin(node.Bond.Denom, node.Bond.Amount.ToDec().Mul(fraction).TruncateInt())
+	delegations, delegationsAmount := slashDelegations(nodeDelegations, fraction)
+	delegationsSlashed := sdk.NewCoin(node.Bond.Denom, delegationsAmount)
+	unBondingsSlashed := sdk.NewCoin(node.Bond.Denom, k.slashUnBondings(ctx, node.OperatorAddress, infractionHeight, fraction))
+	slashed := selfSlashed.Add(delegationsSlashed).Add(unBondingsSlashed)
+	if slashed.IsPositive() {
+		err := k.supplyKeeper.SendCoinsFromModuleToModule(ctx, types.ModuleName, k.feeCollectorName, sdk.NewCoins(slashed))
+		if err != nil {
+			return err
+		}
+	}
+
+	k.updateDelegations(ctx, delegations)
+
+	jailed := node
+	jailed.Bond = node.Bond.Sub(selfSlashed)
+	jailed.DelegatedBond = node.GetDelegatedBond().Sub(delegationsSlashed)
+	jailed.Jailed = true
+	k.updateIPALNode(ctx, node, jailed)
+
+	jailedUntil := ctx.BlockHeader().Time.Add(jailDuration)
+	k.SetLivenessInfo(ctx, types.NewLivenessInfo(node.OperatorAddress, 0, jailedUntil, 0))
+	k.clearMissedWindowBitArray(ctx, node.OperatorAddress)
+
+	ctx.Logger().Info(fmt.Sprintf("ipal node %s slashed %s and jailed until %s for %s", node.OperatorAddress, slashed, jailedUntil, reason))
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeSlash,
+			sdk.NewAttribute(types.AttributeKeyOperator, node.OperatorAddress.String()),
+			sdk.NewAttribute(types.AttributeKeyAmount, slashed.String()),
+			sdk.NewAttribute(types.AttributeKeyReason, reason),
+			sdk.NewAttribute(types.AttributeKeyJailedUntil, jailedUntil.String()),
+		),
+	)
+
+	return nil
+}
+
+// Unjail brings a jailed ipal node back in the node list once its jail period is over, its bond
+// must still be at least the min bond
+func (k Keeper) Unjail(ctx sdk.Context, operator sdk.AccAddress) error {
+	node, found := k.GetIPALNode(ctx, operator)
+	if !found {
+		return sdkerrors.Wrapf(types.ErrIPALNodeNotExist, "operator: %s", operator)
+	}
+
+	if !node.Jailed {
+		return sdkerrors.Wrapf(types.ErrIPALNodeNotJailed, "operator: %s", operator)
+	}
+
+	info, found := k.GetLivenessInfo(ctx, operator)
+	if found && ctx.BlockHeader().Time.Before(info.JailedUntil) {
+		return sdkerrors.Wrapf(types.ErrJailPeriod, "jailed until %s", info.JailedUntil)
+	}
+
+	minBond := k.GetMinBond(ctx)
+	if node.Bond.IsLT(minBond) {
+		return sdkerrors.Wrapf(types.ErrBondInsufficient, "bond insufficient, min bond: %s, actual bond: %s, claim more bond before unjailing", minBond.String(), node.Bond.String())
+	}
+
+	unjailed := node
+	unjailed.Jailed = false
+	k.updateIPALNode(ctx, node, unjailed)
+	return nil
+}
@@ -0,0 +1,221 @@
+package keeper
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/netcloth/netcloth-chain/app/v0/auth"
+	"github.com/netcloth/netcloth-chain/app/v0/bank"
+	cipaltypes "github.com/netcloth/netcloth-chain/app/v0/cipal/types"
+	"github.com/netcloth/netcloth-chain/app/v0/guardian"
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	"github.com/netcloth/netcloth-chain/app/v0/params"
+	"github.com/netcloth/netcloth-chain/app/v0/supply"
+	"github.com/netcloth/netcloth-chain/codec"
+	"github.com/netcloth/netcloth-chain/store"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// mockCIPALObjects holds the cipal objects of the users, by user address
+type mockCIPALObjects map[string]cipaltypes.CIPALObject
+
+func (m mockCIPALObjects) GetCIPALObject(_ sdk.Context, userAddress string) (cipaltypes.CIPALObject, bool) {
+	obj, found := m[userAddress]
+	return obj, found
+}
+
+func createTestInput(t *testing.T) (sdk.Context, Keeper, guardian.Keeper, supply.Keeper, []sdk.AccAddress) {
+	keyParams := sdk.NewKVStoreKey(params.StoreKey)
+	tkeyParams := sdk.NewTransientStoreKey(params.TStoreKey)
+	keyAcc := sdk.NewKVStoreKey(auth.StoreKey)
+	keySupply := sdk.NewKVStoreKey(supply.StoreKey)
+	keyGuardian := sdk.NewKVStoreKey(guardian.StoreKey)
+	keyIPAL := sdk.NewKVStoreKey(types.StoreKey)
+
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	for _, key := range []sdk.StoreKey{keyParams, keyAcc, keySupply, keyGuardian, keyIPAL} {
+		ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	}
+	ms.MountStoreWithDB(tkeyParams, sdk.StoreTypeTransient, db)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	cdc := codec.New()
+	sdk.RegisterCodec(cdc)
+	auth.RegisterCodec(cdc)
+	supply.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+
+	ctx := sdk.NewContext(ms, abci.Header{Time: time.Unix(0, 0).UTC()}, false, log.NewTMLogger(os.Stdout))
+
+	paramsKeeper := params.NewKeeper(cdc, keyParams, tkeyParams)
+	accountKeeper := auth.NewAccountKeeper(cdc, keyAcc, paramsKeeper.Subspace(auth.DefaultParamspace), auth.ProtoBaseAccount)
+	bankKeeper := bank.NewBaseKeeper(accountKeeper, paramsKeeper.Subspace(bank.DefaultParamspace), nil)
+	maccPerms := map[string][]string{
+		auth.FeeCollectorName: nil,
+		types.ModuleName:      {supply.Staking},
+		types.RewardPoolName:  nil,
+	}
+	supplyKeeper := supply.NewKeeper(cdc, keySupply, accountKeeper, bankKeeper, maccPerms)
+	guardianKeeper := guardian.NewKeeper(cdc, keyGuardian)
+	keeper := NewKeeper(keyIPAL, cdc, supplyKeeper, guardianKeeper, paramsKeeper.Subspace(DefaultParamspace), auth.FeeCollectorName, nil)
+
+	params := types.DefaultParams()
+	params.AvailabilityWindow = 10
+	params.LivenessWindow = 4
+	params.UserReportThreshold = 2
+	keeper.SetParams(ctx, params)
+
+	coins := sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(10*sdk.NativeTokenFraction)))
+	var addrs []sdk.AccAddress
+	for i := 0; i < 4; i++ {
+		addr := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+		_, err := bankKeeper.AddCoins(ctx, addr, coins)
+		require.NoError(t, err)
+		addrs = append(addrs, addr)
+	}
+	supplyKeeper.SetSupply(ctx, supply.NewSupply(sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(40*sdk.NativeTokenFraction)))))
+
+	return ctx, keeper, guardianKeeper, supplyKeeper, addrs
+}
+
+func claimNode(t *testing.T, ctx sdk.Context, k Keeper, operator sdk.AccAddress, bond sdk.Coin) {
+	endpoints := types.Endpoints{types.NewEndpoint(1, "192.168.1.100:10000")}
+	msg := types.NewMsgIPALNodeClaim(operator, operator.String(), "", "", "", endpoints, bond)
+	require.NoError(t, k.DoIPALNodeClaim(ctx, msg))
+}
+
+// endWindow moves ctx to the last block of the availability window following ctx and closes it
+func endWindow(ctx sdk.Context, k Keeper) sdk.Context {
+	window := k.GetAvailabilityWindow(ctx)
+	ctx = ctx.WithBlockHeight((ctx.BlockHeight()/window + 1) * window)
+	k.HandleAvailabilityWindow(ctx)
+	return ctx
+}
+
+func TestLivenessDowntime(t *testing.T) {
+	ctx, k, gk, sk, addrs := createTestInput(t)
+	operator, reporter, user1, user2 := addrs[0], addrs[1], addrs[2], addrs[3]
+	gk.AddReporter(ctx, guardian.NewGuardian("reporter", guardian.Ordinary, reporter, reporter))
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator, bond)
+
+	// the users are served by the node through its operator address and its endpoint, the
+	// outsiders aren't
+	outsider := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	k = k.WithCIPALKeeper(mockCIPALObjects{
+		user1.String():    cipaltypes.NewCIPALObject(user1.String(), operator.String(), 1),
+		user2.String():    cipaltypes.NewCIPALObject(user2.String(), "192.168.1.100:10000", 1),
+		outsider.String(): cipaltypes.NewCIPALObject(outsider.String(), "192.168.1.200:10000", 1),
+	})
+
+	// the downtime reported by outsiders is rejected, whether they are cipal users or not
+	for _, addr := range []sdk.AccAddress{outsider, sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())} {
+		err := k.HandleReport(ctx, types.NewMsgIPALNodeReport(addr, operator, types.ReportDowntime, ""))
+		require.True(t, types.ErrInvalidReporter.Is(err))
+	}
+
+	// the downtime reported by users marks the window missed once the threshold is reached
+	require.NoError(t, k.HandleReport(ctx, types.NewMsgIPALNodeReport(user1, operator, types.ReportDowntime, "")))
+	require.False(t, ctx.KVStore(k.storeKey).Has(types.GetWindowDowntimeKey(operator)))
+	err := k.HandleReport(ctx, types.NewMsgIPALNodeReport(user1, operator, types.ReportDowntime, ""))
+	require.True(t, types.ErrDuplicateReport.Is(err))
+	require.NoError(t, k.HandleReport(ctx, types.NewMsgIPALNodeReport(user2, operator, types.ReportDowntime, "")))
+	err = k.HandleReport(ctx, types.NewMsgIPALNodeReport(user1, operator, types.ReportMisbehaviour, ""))
+	require.True(t, types.ErrInvalidReporter.Is(err))
+	ctx = endWindow(ctx, k)
+
+	// the downtime reported by a reporter marks the window missed at once
+	for i := 0; i < 2; i++ {
+		require.NoError(t, k.HandleReport(ctx, types.NewMsgIPALNodeReport(reporter, operator, types.ReportDowntime, "")))
+		ctx = endWindow(ctx, k)
+	}
+
+	// the node is given a whole liveness window before being jailed
+	info, found := k.GetLivenessInfo(ctx, operator)
+	require.True(t, found)
+	require.Equal(t, int64(3), info.IndexOffset)
+	require.Equal(t, int64(3), info.MissedWindowsCounter)
+	require.Len(t, k.GetAllIPALNodes(ctx), 1)
+
+	ctx = endWindow(ctx, k)
+	node, found := k.GetIPALNode(ctx, operator)
+	require.True(t, found)
+	require.True(t, node.Jailed)
+	slashed := bond.Amount.ToDec().Mul(k.GetSlashFractionDowntime(ctx)).TruncateInt()
+	require.Equal(t, bond.Amount.Sub(slashed), node.Bond.Amount)
+	require.Equal(t, slashed, sk.GetModuleAccount(ctx, auth.FeeCollectorName).GetCoins().AmountOf(sdk.NativeTokenName))
+	require.Empty(t, k.GetAllIPALNodes(ctx))
+
+	info, _ = k.GetLivenessInfo(ctx, operator)
+	require.Equal(t, ctx.BlockHeader().Time.Add(k.GetDowntimeJailDuration(ctx)), info.JailedUntil)
+	require.Equal(t, int64(0), info.MissedWindowsCounter)
+
+	err = k.HandleReport(ctx, types.NewMsgIPALNodeReport(reporter, operator, types.ReportDowntime, ""))
+	require.True(t, types.ErrIPALNodeJailed.Is(err))
+
+	// the node is unjailed after its jail period, with a bond of at least the min bond
+	err = k.Unjail(ctx, operator)
+	require.True(t, types.ErrJailPeriod.Is(err))
+	ctx = ctx.WithBlockTime(info.JailedUntil)
+	err = k.Unjail(ctx, operator)
+	require.True(t, types.ErrBondInsufficient.Is(err))
+
+	claimNode(t, ctx, k, operator, bond)
+	node, _ = k.GetIPALNode(ctx, operator)
+	require.True(t, node.Jailed)
+	require.Empty(t, k.GetAllIPALNodes(ctx))
+
+	require.NoError(t, k.Unjail(ctx, operator))
+	require.Len(t, k.GetAllIPALNodes(ctx), 1)
+	err = k.Unjail(ctx, operator)
+	require.True(t, types.ErrIPALNodeNotJailed.Is(err))
+}
+
+func TestLivenessMisbehaviour(t *testing.T) {
+	ctx, k, gk, _, addrs := createTestInput(t)
+	operator, reporter := addrs[0], addrs[1]
+	gk.AddReporter(ctx, guardian.NewGuardian("reporter", guardian.Ordinary, reporter, reporter))
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator, bond)
+
+	require.NoError(t, k.HandleReport(ctx, types.NewMsgIPALNodeReport(reporter, operator, types.ReportMisbehaviour, "wrong messages")))
+	node, _ := k.GetIPALNode(ctx, operator)
+	require.True(t, node.Jailed)
+	slashed := bond.Amount.ToDec().Mul(k.GetSlashFractionMisbehaviour(ctx)).TruncateInt()
+	require.Equal(t, bond.Amount.Sub(slashed), node.Bond.Amount)
+
+	info, _ := k.GetLivenessInfo(ctx, operator)
+	require.Equal(t, ctx.BlockHeader().Time.Add(k.GetMisbehaviourJailDuration(ctx)), info.JailedUntil)
+
+	// the windows of a jailed node aren't tracked
+	ctx = endWindow(ctx, k)
+	info, _ = k.GetLivenessInfo(ctx, operator)
+	require.Equal(t, int64(0), info.IndexOffset)
+}
+
+func TestLivenessDisabled(t *testing.T) {
+	ctx, k, _, _, addrs := createTestInput(t)
+	claimNode(t, ctx, k, addrs[0], k.GetMinBond(ctx))
+
+	params := k.GetParams(ctx)
+	params.AvailabilityWindow = 0
+	k.SetParams(ctx, params)
+
+	err := k.HandleReport(ctx, types.NewMsgIPALNodeReport(addrs[1], addrs[0], types.ReportDowntime, ""))
+	require.True(t, types.ErrLivenessDisabled.Is(err))
+
+	ctx = ctx.WithBlockHeight(10)
+	k.HandleAvailabilityWindow(ctx)
+	_, found := k.GetLivenessInfo(ctx, addrs[0])
+	require.False(t, found)
+}
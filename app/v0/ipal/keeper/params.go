@@ -34,10 +34,72 @@ func (k Keeper) SetMinBond(ctx sdk.Context, minBond sdk.Coin) {
 	k.paramstore.Set(ctx, types.KeyMinBond, minBond)
 }
 
+// GetAvailabilityWindow return AvailabilityWindow from store, chains started before the param was
+// introduced don't have it and don't track the liveness of the ipal nodes
+func (k Keeper) GetAvailabilityWindow(ctx sdk.Context) (res int64) {
+	k.paramstore.GetIfExists(ctx, types.KeyAvailabilityWindow, &res)
+	return
+}
+
+func (k Keeper) GetLivenessWindow(ctx sdk.Context) (res int64) {
+	k.paramstore.GetIfExists(ctx, types.KeyLivenessWindow, &res)
+	return
+}
+
+func (k Keeper) GetMinAvailablePerWindow(ctx sdk.Context) sdk.Dec {
+	res := sdk.ZeroDec()
+	k.paramstore.GetIfExists(ctx, types.KeyMinAvailablePerWindow, &res)
+	return res
+}
+
+func (k Keeper) GetUserReportThreshold(ctx sdk.Context) (res int64) {
+	k.paramstore.GetIfExists(ctx, types.KeyUserReportThreshold, &res)
+	return
+}
+
+func (k Keeper) GetDowntimeJailDuration(ctx sdk.Context) (res time.Duration) {
+	k.paramstore.GetIfExists(ctx, types.KeyDowntimeJailDuration, &res)
+	return
+}
+
+func (k Keeper) GetMisbehaviourJailDuration(ctx sdk.Context) (res time.Duration) {
+	k.paramstore.GetIfExists(ctx, types.KeyMisbehaviourJailDuration, &res)
+	return
+}
+
+func (k Keeper) GetSlashFractionDowntime(ctx sdk.Context) sdk.Dec {
+	res := sdk.ZeroDec()
+	k.paramstore.GetIfExists(ctx, types.KeySlashFractionDowntime, &res)
+	return res
+}
+
+func (k Keeper) GetSlashFractionMisbehaviour(ctx sdk.Context) sdk.Dec {
+	res := sdk.ZeroDec()
+	k.paramstore.GetIfExists(ctx, types.KeySlashFractionMisbehaviour, &res)
+	return res
+}
+
+// GetRewardFraction return RewardFraction from store, chains started before the param was
+// introduced don't have it and don't reward the ipal nodes
+func (k Keeper) GetRewardFraction(ctx sdk.Context) sdk.Dec {
+	res := sdk.ZeroDec()
+	k.paramstore.GetIfExists(ctx, types.KeyRewardFraction, &res)
+	return res
+}
+
 func (k Keeper) GetParams(ctx sdk.Context) (res types.Params) {
 	return types.NewParams(
 		k.GetUnbondingTime(ctx),
-		k.GetMinBond(ctx))
+		k.GetMinBond(ctx),
+		k.GetAvailabilityWindow(ctx),
+		k.GetLivenessWindow(ctx),
+		k.GetMinAvailablePerWindow(ctx),
+		k.GetUserReportThreshold(ctx),
+		k.GetDowntimeJailDuration(ctx),
+		k.GetMisbehaviourJailDuration(ctx),
+		k.GetSlashFractionDowntime(ctx),
+		k.GetSlashFractionMisbehaviour(ctx),
+		k.GetRewardFraction(ctx))
 }
 
 func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
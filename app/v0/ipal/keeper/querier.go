@@ -1,6 +1,8 @@
 package keeper
 
 import (
+	"time"
+
 	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
 	"github.com/netcloth/netcloth-chain/codec"
 	sdk "github.com/netcloth/netcloth-chain/types"
@@ -19,6 +21,16 @@ func NewQuerier(k Keeper) sdk.Querier {
 			return queryIPALNode(ctx, req, k)
 		case types.QueryIPALNodes:
 			return queryIPALNodes(ctx, req, k)
+		case types.QueryLiveness:
+			return queryLiveness(ctx, req, k)
+		case types.QueryServiceTypes:
+			return queryServiceTypes(ctx, k)
+		case types.QueryRewards:
+			return queryRewards(ctx, req, k)
+		case types.QueryDelegations:
+			return queryDelegations(ctx, req, k)
+		case types.QueryDelegatorDelegations:
+			return queryDelegatorDelegations(ctx, req, k)
 		default:
 			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unknown ipal query path: %s", path[0])
 		}
@@ -86,3 +98,95 @@ func queryIPALNodes(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, e
 	}
 	return bz, nil
 }
+
+func queryLiveness(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	var queryParams types.QueryIPALNodeParams
+
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &queryParams)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+
+	if _, found := k.GetIPALNode(ctx, queryParams.AccAddr); !found {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "not found")
+	}
+
+	info, found := k.GetLivenessInfo(ctx, queryParams.AccAddr)
+	if !found {
+		info = types.NewLivenessInfo(queryParams.AccAddr, 0, time.Unix(0, 0).UTC(), 0)
+	}
+
+	bz, err := codec.MarshalJSONIndent(types.ModuleCdc, info)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return bz, nil
+}
+
+func queryServiceTypes(ctx sdk.Context, k Keeper) ([]byte, error) {
+	serviceTypes := k.GetAllServiceTypes(ctx)
+	if serviceTypes == nil {
+		serviceTypes = types.ServiceTypeInfos{}
+	}
+
+	bz, err := codec.MarshalJSONIndent(types.ModuleCdc, serviceTypes)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return bz, nil
+}
+
+func queryRewards(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	var queryParams types.QueryIPALNodeParams
+
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &queryParams)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+
+	bz, err := codec.MarshalJSONIndent(types.ModuleCdc, k.GetRewards(ctx, queryParams.AccAddr))
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return bz, nil
+}
+
+func queryDelegations(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	var queryParams types.QueryIPALNodeParams
+
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &queryParams)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+
+	delegations := k.GetNodeDelegations(ctx, queryParams.AccAddr)
+	if delegations == nil {
+		delegations = types.Delegations{}
+	}
+
+	bz, err := codec.MarshalJSONIndent(types.ModuleCdc, delegations)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return bz, nil
+}
+
+func queryDelegatorDelegations(ctx sdk.Context, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	var queryParams types.QueryIPALNodeParams
+
+	err := types.ModuleCdc.UnmarshalJSON(req.Data, &queryParams)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+
+	delegations := k.GetDelegatorDelegations(ctx, queryParams.AccAddr)
+	if delegations == nil {
+		delegations = types.Delegations{}
+	}
+
+	bz, err := codec.MarshalJSONIndent(types.ModuleCdc, delegations)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return bz, nil
+}
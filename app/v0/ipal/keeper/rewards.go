@@ -0,0 +1,361 @@
+package keeper
+
+import (
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
+)
+
+// GetOutstandingRewards returns the settled rewards of an ipal node operator, or of a delegator, not withdrawn yet
+func (k Keeper) GetOutstandingRewards(ctx sdk.Context, operator sdk.AccAddress) (rewards sdk.Coins) {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.GetOutstandingRewardsKey(operator))
+	if value == nil {
+		return sdk.Coins{}
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &rewards)
+	return rewards
+}
+
+func (k Keeper) setOutstandingRewards(ctx sdk.Context, operator sdk.AccAddress, rewards sdk.Coins) {
+	store := ctx.KVStore(k.storeKey)
+	if rewards.IsZero() {
+		store.Delete(types.GetOutstandingRewardsKey(operator))
+		return
+	}
+	store.Set(types.GetOutstandingRewardsKey(operator), k.cdc.MustMarshalBinaryLengthPrefixed(rewards))
+}
+
+// IterateOutstandingRewards iterates over the rewards of the ipal nodes not withdrawn yet
+func (k Keeper) IterateOutstandingRewards(ctx sdk.Context, handler func(operator sdk.AccAddress, rewards sdk.Coins) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.OutstandingRewardsKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var rewards sdk.Coins
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &rewards)
+		if handler(sdk.AccAddress(iterator.Key()[len(types.OutstandingRewardsKey):]), rewards) {
+			break
+		}
+	}
+}
+
+// GetTotalOutstandingRewards returns the coins of the reward pool owed to the operators and the delegators, settled
+// or not, the rest of the pool is shared at the next block
+func (k Keeper) GetTotalOutstandingRewards(ctx sdk.Context) (rewards sdk.Coins) {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.TotalOutstandingRewardsKey)
+	if value == nil {
+		return sdk.Coins{}
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &rewards)
+	return rewards
+}
+
+func (k Keeper) setTotalOutstandingRewards(ctx sdk.Context, rewards sdk.Coins) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.TotalOutstandingRewardsKey, k.cdc.MustMarshalBinaryLengthPrefixed(rewards))
+}
+
+// SetOutstandingRewards sets the rewards of an ipal node and updates the total outstanding rewards
+func (k Keeper) SetOutstandingRewards(ctx sdk.Context, operator sdk.AccAddress, rewards sdk.Coins) {
+	total := k.GetTotalOutstandingRewards(ctx).Sub(k.GetOutstandingRewards(ctx, operator)).Add(rewards)
+	k.setTotalOutstandingRewards(ctx, total)
+	k.setOutstandingRewards(ctx, operator, rewards)
+}
+
+// GetWithdrawAddr returns the address receiving the rewards of an ipal node, the operator by default
+func (k Keeper) GetWithdrawAddr(ctx sdk.Context, operator sdk.AccAddress) sdk.AccAddress {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.GetWithdrawAddrKey(operator))
+	if value == nil {
+		return operator
+	}
+	return value
+}
+
+// SetWithdrawAddr sets the address receiving the rewards of an ipal node
+func (k Keeper) SetWithdrawAddr(ctx sdk.Context, operator, withdrawAddr sdk.AccAddress) error {
+	if k.blacklistedAddrs[withdrawAddr.String()] {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "%s is blacklisted from receiving external funds", withdrawAddr)
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	if withdrawAddr.Equals(operator) {
+		store.Delete(types.GetWithdrawAddrKey(operator))
+		return nil
+	}
+	store.Set(types.GetWithdrawAddrKey(operator), withdrawAddr)
+	return nil
+}
+
+// IterateWithdrawAddrs iterates over the withdraw addresses set by the operators
+func (k Keeper) IterateWithdrawAddrs(ctx sdk.Context, handler func(operator, withdrawAddr sdk.AccAddress) (stop bool)) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.WithdrawAddrKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		if handler(sdk.AccAddress(iterator.Key()[len(types.WithdrawAddrKey):]), iterator.Value()) {
+			break
+		}
+	}
+}
+
+// AllocateRewards moves RewardFraction of the coins of the fee collector, the coins minted for the block and
+// the fees of the previous block, to the reward pool and shares the pool coins not owed yet among the nodes
+func (k Keeper) AllocateRewards(ctx sdk.Context) {
+	fraction := k.GetRewardFraction(ctx)
+	if !fraction.IsPositive() {
+		return
+	}
+
+	feeCollector := k.supplyKeeper.GetModuleAccount(ctx, k.feeCollectorName)
+	rewards, _ := sdk.NewDecCoins(feeCollector.GetCoins()).MulDecTruncate(fraction).TruncateDecimal()
+	if !rewards.IsZero() {
+		err := k.supplyKeeper.SendCoinsFromModuleToModule(ctx, k.feeCollectorName, types.RewardPoolName, rewards)
+		if err != nil {
+			panic(err)
+		}
+
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeRewards,
+				sdk.NewAttribute(types.AttributeKeyAmount, rewards.String()),
+			),
+		)
+	}
+
+	k.distributeRewards(ctx)
+}
+
+// distributeRewards adds the pool coins not owed yet to the reward ratio, the rewards per bond unit shared
+// by the nodes which aren't jailed. The rewards of the operators and of the delegators are settled when
+// their bond changes and when they withdraw, so the work doesn't grow with the nodes and the delegations;
+// the truncated remainders are left in the pool
+func (k Keeper) distributeRewards(ctx sdk.Context) {
+	pool := k.supplyKeeper.GetModuleAccount(ctx, types.RewardPoolName).GetCoins()
+	total := k.GetTotalOutstandingRewards(ctx)
+	undistributed, hasNeg := pool.SafeSub(total)
+	if hasNeg || undistributed.IsZero() {
+		return
+	}
+
+	activeBond := k.getActiveBond(ctx).ToDec()
+	if !activeBond.IsPositive() {
+		return
+	}
+
+	ratio := sdk.NewDecCoins(undistributed).QuoDecTruncate(activeBond)
+	distributed, _ := ratio.MulDecTruncate(activeBond).TruncateDecimal()
+	if distributed.IsZero() {
+		return
+	}
+
+	k.setRewardRatio(ctx, k.GetRewardRatio(ctx).Add(ratio))
+	k.setTotalOutstandingRewards(ctx, total.Add(distributed))
+}
+
+// GetRewardRatio returns the rewards per bond unit shared by the nodes which aren't jailed since the first rewards
+func (k Keeper) GetRewardRatio(ctx sdk.Context) (ratio sdk.DecCoins) {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.RewardRatioKey)
+	if value == nil {
+		return sdk.DecCoins{}
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &ratio)
+	return ratio
+}
+
+func (k Keeper) setRewardRatio(ctx sdk.Context, ratio sdk.DecCoins) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.RewardRatioKey, k.cdc.MustMarshalBinaryLengthPrefixed(ratio))
+}
+
+// getActiveBond returns the total bond of the nodes which aren't jailed, summed up from the nodes the first
+// time for the nodes claimed before it was tracked
+func (k Keeper) getActiveBond(ctx sdk.Context) (bond sdk.Int) {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.ActiveBondKey)
+	if value == nil {
+		bond = sdk.ZeroInt()
+		k.IterateIPALNodes(ctx, func(node types.IPALNode) (stop bool) {
+			bond = bond.Add(activeBond(node))
+			return false
+		})
+		return bond
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &bond)
+	return bond
+}
+
+func (k Keeper) setActiveBond(ctx sdk.Context, bond sdk.Int) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.ActiveBondKey, k.cdc.MustMarshalBinaryLengthPrefixed(bond))
+}
+
+// activeBond returns the bond of node sharing the rewards, none while it is jailed
+func activeBond(node types.IPALNode) sdk.Int {
+	if node.Jailed {
+		return sdk.ZeroInt()
+	}
+	return node.TotalBond().Amount
+}
+
+// getNodeRewardRatio returns the reward ratio of a node, zero for the nodes claimed before it was tracked:
+// they share the rewards since the first ones
+func (k Keeper) getNodeRewardRatio(ctx sdk.Context, operator sdk.AccAddress) (ratio types.NodeRewardRatio) {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.GetNodeRewardRatioKey(operator))
+	if value == nil {
+		return ratio
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &ratio)
+	return ratio
+}
+
+func (k Keeper) setNodeRewardRatio(ctx sdk.Context, operator sdk.AccAddress, ratio types.NodeRewardRatio) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetNodeRewardRatioKey(operator), k.cdc.MustMarshalBinaryLengthPrefixed(ratio))
+}
+
+// nodeRewardRatio returns the rewards per bond unit earned by node since it was claimed
+func (k Keeper) nodeRewardRatio(ctx sdk.Context, node types.IPALNode) sdk.DecCoins {
+	ratio := k.getNodeRewardRatio(ctx, node.OperatorAddress)
+	if node.Jailed {
+		return ratio.Ratio
+	}
+	return ratio.Ratio.Add(k.GetRewardRatio(ctx).Sub(ratio.GlobalRatio))
+}
+
+// initNodeRewards adds the bond of a new node to the active bond, the node earns the rewards shared from now on
+func (k Keeper) initNodeRewards(ctx sdk.Context, node types.IPALNode) {
+	k.setActiveBond(ctx, k.getActiveBond(ctx).Add(activeBond(node)))
+	k.setNodeRewardRatio(ctx, node.OperatorAddress, types.NodeRewardRatio{GlobalRatio: k.GetRewardRatio(ctx)})
+}
+
+// updateNodeRewards updates the active bond for a node changing from old to new, and keeps the reward ratio
+// of the node from moving while it is jailed
+func (k Keeper) updateNodeRewards(ctx sdk.Context, old, new types.IPALNode) {
+	if oldBond, newBond := activeBond(old), activeBond(new); !oldBond.Equal(newBond) {
+		k.setActiveBond(ctx, k.getActiveBond(ctx).Sub(oldBond).Add(newBond))
+	}
+
+	if old.Jailed != new.Jailed {
+		k.setNodeRewardRatio(ctx, new.OperatorAddress, types.NodeRewardRatio{
+			Ratio:       k.nodeRewardRatio(ctx, old),
+			GlobalRatio: k.GetRewardRatio(ctx),
+		})
+	}
+}
+
+// deleteNodeRewards removes the bond of a removed node from the active bond and deletes its reward ratios,
+// the rewards of its bonds must be settled
+func (k Keeper) deleteNodeRewards(ctx sdk.Context, node types.IPALNode) {
+	k.setActiveBond(ctx, k.getActiveBond(ctx).Sub(activeBond(node)))
+
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetNodeRewardRatioKey(node.OperatorAddress))
+	deletePrefix(store, types.GetBondRewardRatiosKey(node.OperatorAddress))
+}
+
+// getBondRewardRatio returns the reward ratio of node when the rewards of the bond of holder to the node,
+// the self-bond of the operator or a delegation, were last settled
+func (k Keeper) getBondRewardRatio(ctx sdk.Context, operator, holder sdk.AccAddress) (ratio sdk.DecCoins) {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.GetBondRewardRatioKey(operator, holder))
+	if value == nil {
+		return sdk.DecCoins{}
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &ratio)
+	return ratio
+}
+
+func (k Keeper) setBondRewardRatio(ctx sdk.Context, operator, holder sdk.AccAddress, ratio sdk.DecCoins) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetBondRewardRatioKey(operator, holder), k.cdc.MustMarshalBinaryLengthPrefixed(ratio))
+}
+
+func (k Keeper) deleteBondRewardRatio(ctx sdk.Context, operator, holder sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetBondRewardRatioKey(operator, holder))
+}
+
+// unsettledRewards returns the rewards earned by amount bonded by holder to node since they were last settled,
+// and the reward ratio of the node
+func (k Keeper) unsettledRewards(ctx sdk.Context, node types.IPALNode, holder sdk.AccAddress, amount sdk.Int) (sdk.Coins, sdk.DecCoins) {
+	ratio := k.nodeRewardRatio(ctx, node)
+	earned := ratio.Sub(k.getBondRewardRatio(ctx, node.OperatorAddress, holder))
+	rewards, _ := earned.MulDecTruncate(amount.ToDec()).TruncateDecimal()
+	return rewards, ratio
+}
+
+// settleRewards adds the rewards earned by amount bonded by holder to node since they were last settled to the
+// outstanding rewards of holder. A bond is settled before its amount changes
+func (k Keeper) settleRewards(ctx sdk.Context, node types.IPALNode, holder sdk.AccAddress, amount sdk.Int) {
+	rewards, ratio := k.unsettledRewards(ctx, node, holder, amount)
+	if !rewards.IsZero() {
+		k.setOutstandingRewards(ctx, holder, k.GetOutstandingRewards(ctx, holder).Add(rewards))
+	}
+	k.setBondRewardRatio(ctx, node.OperatorAddress, holder, ratio)
+}
+
+// settleNodeRewards settles the self-bond of the operator of node and the delegations to the node
+func (k Keeper) settleNodeRewards(ctx sdk.Context, node types.IPALNode, delegations types.Delegations) {
+	k.settleRewards(ctx, node, node.OperatorAddress, node.Bond.Amount)
+	for _, delegation := range delegations {
+		k.settleRewards(ctx, node, delegation.DelegatorAddress, delegation.Amount.Amount)
+	}
+}
+
+// iterateBonds iterates over the self-bond of addr if it operates an ipal node and over the delegations of
+// addr, with their nodes
+func (k Keeper) iterateBonds(ctx sdk.Context, addr sdk.AccAddress, handler func(node types.IPALNode, amount sdk.Int)) {
+	if node, found := k.GetIPALNode(ctx, addr); found {
+		handler(node, node.Bond.Amount)
+	}
+
+	for _, delegation := range k.GetDelegatorDelegations(ctx, addr) {
+		if node, found := k.GetIPALNode(ctx, delegation.OperatorAddress); found {
+			handler(node, delegation.Amount.Amount)
+		}
+	}
+}
+
+// GetRewards returns the rewards of an ipal node operator, or of a delegator, WithdrawRewards would send: the
+// outstanding rewards and the rewards earned by its bonds since they were last settled
+func (k Keeper) GetRewards(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins {
+	rewards := k.GetOutstandingRewards(ctx, addr)
+	k.iterateBonds(ctx, addr, func(node types.IPALNode, amount sdk.Int) {
+		unsettled, _ := k.unsettledRewards(ctx, node, addr, amount)
+		rewards = rewards.Add(unsettled)
+	})
+	return rewards
+}
+
+// WithdrawRewards sends the rewards of an ipal node operator, or of a delegator, to its withdraw address
+func (k Keeper) WithdrawRewards(ctx sdk.Context, addr sdk.AccAddress) (sdk.Coins, error) {
+	k.iterateBonds(ctx, addr, func(node types.IPALNode, amount sdk.Int) {
+		k.settleRewards(ctx, node, addr, amount)
+	})
+
+	rewards := k.GetOutstandingRewards(ctx, addr)
+	if rewards.IsZero() {
+		return nil, sdkerrors.Wrapf(types.ErrNoRewards, "address: %s", addr)
+	}
+
+	err := k.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.RewardPoolName, k.GetWithdrawAddr(ctx, addr), rewards)
+	if err != nil {
+		return nil, err
+	}
+
+	k.SetOutstandingRewards(ctx, addr, sdk.Coins{})
+	return rewards, nil
+}
@@ -0,0 +1,119 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/netcloth/netcloth-chain/app/v0/auth"
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestAllocateRewards(t *testing.T) {
+	ctx, k, _, sk, addrs := createTestInput(t)
+	operator1, operator2, withdrawAddr, payer := addrs[0], addrs[1], addrs[2], addrs[3]
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator1, bond)
+	claimNode(t, ctx, k, operator2, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(3)))
+
+	fees := sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(10*sdk.NativeTokenFraction)))
+	require.NoError(t, sk.SendCoinsFromAccountToModule(ctx, payer, auth.FeeCollectorName, fees))
+
+	// the rewards are shared by bond
+	k.AllocateRewards(ctx)
+	pool := fees.AmountOf(sdk.NativeTokenName).ToDec().Mul(k.GetRewardFraction(ctx)).TruncateInt()
+	require.Equal(t, pool, sk.GetModuleAccount(ctx, types.RewardPoolName).GetCoins().AmountOf(sdk.NativeTokenName))
+	require.Equal(t, pool.QuoRaw(4), k.GetRewards(ctx, operator1).AmountOf(sdk.NativeTokenName))
+	require.Equal(t, pool.QuoRaw(4).MulRaw(3), k.GetRewards(ctx, operator2).AmountOf(sdk.NativeTokenName))
+	require.Equal(t, sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, pool)), k.GetTotalOutstandingRewards(ctx))
+
+	// the rewards are sent to the withdraw address
+	require.NoError(t, k.SetWithdrawAddr(ctx, operator1, withdrawAddr))
+	require.Equal(t, withdrawAddr, k.GetWithdrawAddr(ctx, operator1))
+	balance := sk.GetModuleAccount(ctx, types.RewardPoolName).GetCoins()
+	rewards, err := k.WithdrawRewards(ctx, operator1)
+	require.NoError(t, err)
+	require.Equal(t, pool.QuoRaw(4), rewards.AmountOf(sdk.NativeTokenName))
+	require.Equal(t, balance.Sub(rewards), sk.GetModuleAccount(ctx, types.RewardPoolName).GetCoins())
+	require.True(t, k.GetRewards(ctx, operator1).IsZero())
+	require.Equal(t, k.GetRewards(ctx, operator2), k.GetTotalOutstandingRewards(ctx))
+
+	_, err = k.WithdrawRewards(ctx, operator1)
+	require.True(t, types.ErrNoRewards.Is(err))
+
+	// the operator is the withdraw address by default
+	require.NoError(t, k.SetWithdrawAddr(ctx, operator1, operator1))
+	require.Equal(t, operator1, k.GetWithdrawAddr(ctx, operator1))
+
+	// no rewards are allocated when the fraction is zero
+	params := k.GetParams(ctx)
+	params.RewardFraction = sdk.ZeroDec()
+	k.SetParams(ctx, params)
+	k.AllocateRewards(ctx)
+	require.Equal(t, balance.Sub(rewards), sk.GetModuleAccount(ctx, types.RewardPoolName).GetCoins())
+}
+
+func TestRewardsSettlement(t *testing.T) {
+	ctx, k, _, sk, addrs := createTestInput(t)
+	operator1, operator2, delegator, payer := addrs[0], addrs[1], addrs[2], addrs[3]
+
+	bond := k.GetMinBond(ctx)
+	claimNode(t, ctx, k, operator1, bond)
+	claimNode(t, ctx, k, operator2, bond)
+
+	// the whole fee collector is shared, so each allocation shares pool
+	params := k.GetParams(ctx)
+	params.RewardFraction = sdk.OneDec()
+	k.SetParams(ctx, params)
+	fees := sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(12*sdk.NativeTokenFraction/10)))
+	pool := fees.AmountOf(sdk.NativeTokenName)
+	allocate := func() {
+		require.NoError(t, sk.SendCoinsFromAccountToModule(ctx, payer, auth.FeeCollectorName, fees))
+		k.AllocateRewards(ctx)
+	}
+
+	// the rewards are owed to the nodes but settled to none yet
+	allocate()
+	require.Equal(t, pool.QuoRaw(2), k.GetRewards(ctx, operator1).AmountOf(sdk.NativeTokenName))
+	require.Equal(t, pool.QuoRaw(2), k.GetRewards(ctx, operator2).AmountOf(sdk.NativeTokenName))
+	require.True(t, k.GetOutstandingRewards(ctx, operator1).IsZero())
+	require.True(t, k.GetOutstandingRewards(ctx, operator2).IsZero())
+
+	// a delegation earns the rewards shared after it, a jailed node earns none
+	require.NoError(t, k.Delegate(ctx, delegator, operator1, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(2))))
+	node2, _ := k.GetIPALNode(ctx, operator2)
+	require.NoError(t, k.slashAndJail(ctx, node2, sdk.ZeroDec(), ctx.BlockHeight(), time.Hour, types.ReportDowntime.String()))
+	require.Equal(t, pool.QuoRaw(2), k.GetOutstandingRewards(ctx, operator2).AmountOf(sdk.NativeTokenName))
+
+	allocate()
+	require.Equal(t, pool.QuoRaw(2).Add(pool.QuoRaw(3)), k.GetRewards(ctx, operator1).AmountOf(sdk.NativeTokenName))
+	require.Equal(t, pool.QuoRaw(3).MulRaw(2), k.GetRewards(ctx, delegator).AmountOf(sdk.NativeTokenName))
+	require.Equal(t, pool.QuoRaw(2), k.GetRewards(ctx, operator2).AmountOf(sdk.NativeTokenName))
+
+	// an unjailed node earns again
+	ctx = ctx.WithBlockTime(ctx.BlockHeader().Time.Add(time.Hour))
+	require.NoError(t, k.Unjail(ctx, operator2))
+	allocate()
+	require.Equal(t, pool.QuoRaw(2).Add(pool.QuoRaw(4)), k.GetRewards(ctx, operator2).AmountOf(sdk.NativeTokenName))
+	require.Equal(t, pool.QuoRaw(3).MulRaw(2).Add(pool.QuoRaw(2)), k.GetRewards(ctx, delegator).AmountOf(sdk.NativeTokenName))
+
+	// the undelegated bond stops earning, its rewards are kept until withdrawn
+	_, err := k.Undelegate(ctx, delegator, operator1, sdk.NewCoin(bond.Denom, bond.Amount.MulRaw(2)))
+	require.NoError(t, err)
+	allocate()
+	delegatorRewards := pool.QuoRaw(3).MulRaw(2).Add(pool.QuoRaw(2))
+	require.Equal(t, delegatorRewards, k.GetOutstandingRewards(ctx, delegator).AmountOf(sdk.NativeTokenName))
+	require.Equal(t, delegatorRewards, k.GetRewards(ctx, delegator).AmountOf(sdk.NativeTokenName))
+
+	rewards, err := k.WithdrawRewards(ctx, delegator)
+	require.NoError(t, err)
+	require.Equal(t, delegatorRewards, rewards.AmountOf(sdk.NativeTokenName))
+
+	// the owed rewards add up to the rewards of the operators
+	owed := k.GetRewards(ctx, operator1).Add(k.GetRewards(ctx, operator2))
+	require.Equal(t, owed, k.GetTotalOutstandingRewards(ctx))
+	require.Equal(t, owed, sk.GetModuleAccount(ctx, types.RewardPoolName).GetCoins())
+}
@@ -0,0 +1,87 @@
+package keeper
+
+import (
+	"github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
+)
+
+// GetServiceType returns the registered service type of id
+func (k Keeper) GetServiceType(ctx sdk.Context, id uint64) (serviceType types.ServiceTypeInfo, found bool) {
+	store := ctx.KVStore(k.storeKey)
+	value := store.Get(types.GetServiceTypeKey(id))
+	if value == nil {
+		return serviceType, false
+	}
+
+	k.cdc.MustUnmarshalBinaryLengthPrefixed(value, &serviceType)
+	return serviceType, true
+}
+
+func (k Keeper) SetServiceType(ctx sdk.Context, serviceType types.ServiceTypeInfo) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(types.GetServiceTypeKey(serviceType.ID), k.cdc.MustMarshalBinaryLengthPrefixed(serviceType))
+}
+
+func (k Keeper) DeleteServiceType(ctx sdk.Context, id uint64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(types.GetServiceTypeKey(id))
+}
+
+// GetAllServiceTypes lists the registered service types by id
+func (k Keeper) GetAllServiceTypes(ctx sdk.Context) (serviceTypes types.ServiceTypeInfos) {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.ServiceTypeKey)
+	defer iterator.Close()
+
+	for ; iterator.Valid(); iterator.Next() {
+		var serviceType types.ServiceTypeInfo
+		k.cdc.MustUnmarshalBinaryLengthPrefixed(iterator.Value(), &serviceType)
+		serviceTypes = append(serviceTypes, serviceType)
+	}
+	return serviceTypes
+}
+
+// serviceTypeRegistryEnabled returns whether a service type is registered, the types aren't
+// checked until then so the chains started without the registry keep their endpoints
+func (k Keeper) serviceTypeRegistryEnabled(ctx sdk.Context) bool {
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, types.ServiceTypeKey)
+	defer iterator.Close()
+
+	return iterator.Valid()
+}
+
+// ValidateServiceEndpoint checks the service type is registered and the endpoint matches its regex
+func (k Keeper) ValidateServiceEndpoint(ctx sdk.Context, serviceType uint64, endpoint string) error {
+	if !k.serviceTypeRegistryEnabled(ctx) {
+		return nil
+	}
+
+	info, found := k.GetServiceType(ctx, serviceType)
+	if !found {
+		return sdkerrors.Wrapf(types.ErrServiceTypeUnknown, "service type: %d", serviceType)
+	}
+
+	return info.ValidateEndpoint(endpoint)
+}
+
+// ValidateEndpoints checks the service type and the format of the endpoints
+func (k Keeper) ValidateEndpoints(ctx sdk.Context, endpoints types.Endpoints) error {
+	for _, endpoint := range endpoints {
+		if err := k.ValidateServiceEndpoint(ctx, endpoint.Type, endpoint.Endpoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsProfiler returns whether addr is a profiler of the guardian module, allowed to manage the service types
+func (k Keeper) IsProfiler(ctx sdk.Context, addr sdk.AccAddress) bool {
+	if k.guardianKeeper == nil {
+		return false
+	}
+	_, found := k.guardianKeeper.GetProfiler(ctx, addr)
+	return found
+}
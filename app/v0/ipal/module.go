@@ -51,7 +51,11 @@ func (a AppModuleBasic) DefaultGenesis() json.RawMessage {
 // ValidateGenesis performs genesis state validation for the ipal module.
 func (a AppModuleBasic) ValidateGenesis(value json.RawMessage) error {
 	var data types.GenesisState
-	return ModuleCdc.UnmarshalJSON(value, &data)
+	if err := ModuleCdc.UnmarshalJSON(value, &data); err != nil {
+		return err
+	}
+
+	return data.ServiceTypes.Validate()
 }
 
 // RegisterRESTRoutes registers the REST routes for the ipal module.
@@ -59,9 +63,9 @@ func (a AppModuleBasic) RegisterRESTRoutes(ctx context.CLIContext, rtr *mux.Rout
 	rest.RegisterRoutes(ctx, rtr)
 }
 
-// GetTxCmd returns no root tx command for the ipal module.
+// GetTxCmd returns the root tx command for the ipal module.
 func (a AppModuleBasic) GetTxCmd(cdc *codec.Codec) *cobra.Command {
-	return nil
+	return cli.IPALCmd(cdc)
 }
 
 // GetQueryCmd returns the root query command for the ipal module.
@@ -128,7 +132,8 @@ func (am AppModule) NewQuerierHandler() sdk.Querier {
 }
 
 // BeginBlock returns the begin blocker for the ipal module.
-func (am AppModule) BeginBlock(sdk.Context, abci.RequestBeginBlock) {
+func (am AppModule) BeginBlock(ctx sdk.Context, _ abci.RequestBeginBlock) {
+	BeginBlocker(ctx, am.keeper)
 }
 
 // EndBlock returns the end blocker for the ipal module. It returns no validator
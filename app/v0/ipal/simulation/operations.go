@@ -63,7 +63,11 @@ func SimulateMsgCreateIpal(ak keeper.AccountKeeper, k keeper.Keeper) simtypes.Op
 
 		moniker, website, details, extension := simtypes.RandStringOfLength(r, 1000), simtypes.RandStringOfLength(r, 1000), simtypes.RandStringOfLength(r, 1000), simtypes.RandStringOfLength(r, 1000)
 
-		endPoint := types.NewEndpoint(r.Uint64(), "192.168.1.100:666")
+		endpointType := r.Uint64()
+		if serviceTypes := k.GetAllServiceTypes(ctx); len(serviceTypes) > 0 {
+			endpointType = serviceTypes[r.Intn(len(serviceTypes))].ID
+		}
+		endPoint := types.NewEndpoint(endpointType, "192.168.1.100:666")
 		msg := types.NewMsgIPALNodeClaim(acc.Address, moniker, website, details, extension, types.Endpoints{endPoint}, bond)
 
 		tx := helpers.GenTx(
@@ -7,6 +7,17 @@ import (
 // RegisterCodec - register the sdk message type
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgIPALNodeClaim{}, "nch/IPALClaim", nil)
+	cdc.RegisterConcrete(MsgIPALNodeReport{}, "nch/IPALReport", nil)
+	cdc.RegisterConcrete(MsgIPALNodeUnjail{}, "nch/IPALUnjail", nil)
+	cdc.RegisterConcrete(MsgIPALNodeUnclaim{}, "nch/IPALUnclaim", nil)
+	cdc.RegisterConcrete(MsgIPALNodeEditEndpoints{}, "nch/IPALEditEndpoints", nil)
+	cdc.RegisterConcrete(MsgIPALNodeEditDescription{}, "nch/IPALEditDescription", nil)
+	cdc.RegisterConcrete(MsgAddServiceType{}, "nch/IPALAddServiceType", nil)
+	cdc.RegisterConcrete(MsgDeleteServiceType{}, "nch/IPALDeleteServiceType", nil)
+	cdc.RegisterConcrete(MsgWithdrawIPALNodeReward{}, "nch/IPALWithdrawReward", nil)
+	cdc.RegisterConcrete(MsgSetIPALWithdrawAddress{}, "nch/IPALSetWithdrawAddress", nil)
+	cdc.RegisterConcrete(MsgIPALDelegate{}, "nch/IPALDelegate", nil)
+	cdc.RegisterConcrete(MsgIPALUndelegate{}, "nch/IPALUndelegate", nil)
 }
 
 // ModuleCdc generic sealed codec to be used throughout module
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// Delegation is the bond delegated by a delegator to an ipal node
+type Delegation struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address" yaml:"delegator_address"`
+	OperatorAddress  sdk.AccAddress `json:"operator_address" yaml:"operator_address"`
+	Amount           sdk.Coin       `json:"amount" yaml:"amount"`
+}
+
+type Delegations []Delegation
+
+func NewDelegation(delegator, operator sdk.AccAddress, amount sdk.Coin) Delegation {
+	return Delegation{
+		DelegatorAddress: delegator,
+		OperatorAddress:  operator,
+		Amount:           amount,
+	}
+}
+
+func (d Delegation) String() string {
+	out, _ := yaml.Marshal(d)
+	return string(out)
+}
+
+func (ds Delegations) String() (out string) {
+	for _, d := range ds {
+		out += d.String() + "\n"
+	}
+	return strings.TrimSpace(out)
+}
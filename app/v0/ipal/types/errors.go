@@ -12,6 +12,22 @@ var (
 	ErrEndpointsEmpty     = sdkerrors.New(ModuleName, 6, "no endpoints")
 	ErrEndpointsDuplicate = sdkerrors.New(ModuleName, 7, "endpoints duplicate")
 	ErrEndpointsFormat    = sdkerrors.New(ModuleName, 8, "endpoints format error")
+	ErrIPALNodeNotExist   = sdkerrors.New(ModuleName, 9, "ipal node not exist")
+	ErrIPALNodeJailed     = sdkerrors.New(ModuleName, 10, "ipal node jailed")
+	ErrIPALNodeNotJailed  = sdkerrors.New(ModuleName, 11, "ipal node not jailed")
+	ErrJailPeriod         = sdkerrors.New(ModuleName, 12, "ipal node still jailed")
+	ErrInvalidReporter    = sdkerrors.New(ModuleName, 13, "invalid reporter")
+	ErrInvalidReportType  = sdkerrors.New(ModuleName, 14, "invalid report type")
+	ErrDuplicateReport    = sdkerrors.New(ModuleName, 15, "duplicate report")
+	ErrLivenessDisabled   = sdkerrors.New(ModuleName, 16, "ipal liveness disabled")
+	ErrInvalidServiceType = sdkerrors.New(ModuleName, 17, "invalid service type")
+	ErrServiceTypeUnknown = sdkerrors.New(ModuleName, 18, "service type not registered")
+	ErrServiceTypeExists  = sdkerrors.New(ModuleName, 19, "service type already registered")
+	ErrInvalidProfiler    = sdkerrors.New(ModuleName, 20, "not a profiler")
+	ErrNoRewards          = sdkerrors.New(ModuleName, 21, "no rewards to withdraw")
+	ErrInvalidDelegator   = sdkerrors.New(ModuleName, 22, "invalid delegator")
+	ErrNoDelegation       = sdkerrors.New(ModuleName, 23, "no delegation")
+	ErrDelegationTooLow   = sdkerrors.New(ModuleName, 24, "delegation lower than the amount to undelegate")
 )
 
 type EndpointDuplicateErrDetector struct {
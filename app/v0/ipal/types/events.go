@@ -1,5 +1,38 @@
 package types
 
+const (
+	EventTypeReport = "ipal_report"
+	EventTypeSlash  = "ipal_slash"
+	EventTypeUnjail = "ipal_unjail"
+
+	EventTypeUnclaim         = "ipal_unclaim"
+	EventTypeEditEndpoints   = "ipal_edit_endpoints"
+	EventTypeEditDescription = "ipal_edit_description"
+
+	EventTypeAddServiceType    = "ipal_add_service_type"
+	EventTypeDeleteServiceType = "ipal_delete_service_type"
+
+	EventTypeRewards            = "ipal_rewards"
+	EventTypeWithdrawRewards    = "ipal_withdraw_rewards"
+	EventTypeSetWithdrawAddress = "ipal_set_withdraw_address"
+
+	EventTypeDelegate   = "ipal_delegate"
+	EventTypeUndelegate = "ipal_undelegate"
+
+	AttributeKeyOperator        = "operator"
+	AttributeKeyReporter        = "reporter"
+	AttributeKeyReportType      = "report_type"
+	AttributeKeyAmount          = "amount"
+	AttributeKeyReason          = "reason"
+	AttributeKeyJailedUntil     = "jailed_until"
+	AttributeKeyCompletionTime  = "completion_time"
+	AttributeKeyEndpoints       = "endpoints"
+	AttributeKeyMoniker         = "moniker"
+	AttributeKeyServiceType     = "service_type"
+	AttributeKeyWithdrawAddress = "withdraw_address"
+	AttributeKeyDelegator       = "delegator"
+)
+
 var (
 	AttributeValueCategory = ModuleName
 )
@@ -1,6 +1,8 @@
 package types
 
 import (
+	cipaltypes "github.com/netcloth/netcloth-chain/app/v0/cipal/types"
+	guardiantypes "github.com/netcloth/netcloth-chain/app/v0/guardian/types"
 	supplyexported "github.com/netcloth/netcloth-chain/app/v0/supply/exported"
 	sdk "github.com/netcloth/netcloth-chain/types"
 )
@@ -13,4 +15,17 @@ type SupplyKeeper interface {
 
 	SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error
 	SendCoinsFromAccountToModule(ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error
+	SendCoinsFromModuleToModule(ctx sdk.Context, senderModule, recipientModule string, amt sdk.Coins) error
+}
+
+// CIPALKeeper defines the expected cipal keeper holding the service infos of each user
+type CIPALKeeper interface {
+	GetCIPALObject(ctx sdk.Context, userAddress string) (obj cipaltypes.CIPALObject, found bool)
+}
+
+// GuardianKeeper defines the expected guardian keeper holding the accounts allowed to report ipal nodes
+// and the profilers managing the service type registry
+type GuardianKeeper interface {
+	GetProfiler(ctx sdk.Context, addr sdk.AccAddress) (profiler guardiantypes.Guardian, found bool)
+	GetReporter(ctx sdk.Context, addr sdk.AccAddress) (reporter guardiantypes.Guardian, found bool)
 }
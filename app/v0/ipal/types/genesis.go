@@ -1,19 +1,33 @@
 package types
 
 type GenesisState struct {
-	Params    Params    `json:"params" yaml:"params"`
-	IPALNodes IPALNodes `json:"ipal_nodes" yaml:"ipal_nodes"`
+	Params            Params              `json:"params" yaml:"params"`
+	IPALNodes         IPALNodes           `json:"ipal_nodes" yaml:"ipal_nodes"`
+	LivenessInfos     []LivenessInfo      `json:"liveness_infos" yaml:"liveness_infos"`
+	MissedWindows     []NodeMissedWindows `json:"missed_windows" yaml:"missed_windows"`
+	ServiceTypes      ServiceTypeInfos    `json:"service_types" yaml:"service_types"`
+	Rewards           []NodeRewards       `json:"rewards" yaml:"rewards"`
+	WithdrawAddresses []WithdrawAddress   `json:"withdraw_addresses" yaml:"withdraw_addresses"`
+	Delegations       Delegations         `json:"delegations" yaml:"delegations"`
 }
 
 func DefaultGenesisState() GenesisState {
 	return GenesisState{
-		Params: DefaultParams(),
+		Params:       DefaultParams(),
+		ServiceTypes: DefaultServiceTypes(),
 	}
 }
 
-func NewGenesisState(params Params, ipalNodes IPALNodes) GenesisState {
+func NewGenesisState(params Params, ipalNodes IPALNodes, livenessInfos []LivenessInfo, missedWindows []NodeMissedWindows,
+	serviceTypes ServiceTypeInfos, rewards []NodeRewards, withdrawAddresses []WithdrawAddress, delegations Delegations) GenesisState {
 	return GenesisState{
-		Params:    params,
-		IPALNodes: ipalNodes,
+		Params:            params,
+		IPALNodes:         ipalNodes,
+		LivenessInfos:     livenessInfos,
+		MissedWindows:     missedWindows,
+		ServiceTypes:      serviceTypes,
+		Rewards:           rewards,
+		WithdrawAddresses: withdrawAddresses,
+		Delegations:       delegations,
 	}
 }
@@ -15,7 +15,7 @@ type ServiceType uint64
 
 const (
 	Chatting ServiceType = 1
-	Storage
+	Storage  ServiceType = 2
 )
 
 func EndpointsFromString(endpointsStr, endpointDelimiter, endpointTypeDelimiter string) (endpoints Endpoints, e error) {
@@ -62,6 +62,8 @@ type IPALNode struct {
 	Extension       string         `json:"extension" yaml:"extension"`
 	Endpoints       Endpoints      `json:"endpoints" yaml:"endpoints"`
 	Bond            sdk.Coin       `json:"bond" yaml:"bond"`
+	Jailed          bool           `json:"jailed" yaml:"jailed"`                 // jailed for downtime or misbehaviour, left out of the node list
+	DelegatedBond   sdk.Coin       `json:"delegated_bond" yaml:"delegated_bond"` // bond delegated by third parties
 }
 
 type IPALNodes []IPALNode
@@ -82,9 +84,40 @@ func NewIPALNode(operator sdk.AccAddress, moniker, website, details, extension s
 		Extension:       extension,
 		Endpoints:       endpoints,
 		Bond:            amount,
+		DelegatedBond:   sdk.NewCoin(amount.Denom, sdk.ZeroInt()),
 	}
 }
 
+// GetDelegatedBond returns the bond delegated to the node, zero for the nodes stored before the delegations
+func (obj IPALNode) GetDelegatedBond() sdk.Coin {
+	if obj.DelegatedBond.Denom == "" {
+		return sdk.NewCoin(obj.Bond.Denom, sdk.ZeroInt())
+	}
+	return obj.DelegatedBond
+}
+
+// TotalBond returns the self-bond of the operator plus the bond delegated to the node
+func (obj IPALNode) TotalBond() sdk.Coin {
+	return obj.Bond.Add(obj.GetDelegatedBond())
+}
+
+// UpdateDescription returns the node with the description of the msg, but the fields set to DoNotModifyDesc
+func (obj IPALNode) UpdateDescription(m MsgIPALNodeEditDescription) IPALNode {
+	if m.Moniker != DoNotModifyDesc {
+		obj.Moniker = m.Moniker
+	}
+	if m.Website != DoNotModifyDesc {
+		obj.Website = m.Website
+	}
+	if m.Details != DoNotModifyDesc {
+		obj.Details = m.Details
+	}
+	if m.Extension != DoNotModifyDesc {
+		obj.Extension = m.Extension
+	}
+	return obj
+}
+
 func (obj IPALNode) MarshalYAML() (interface{}, error) {
 	bs, err := yaml.Marshal(struct {
 		OperatorAddress sdk.AccAddress
@@ -94,6 +127,8 @@ func (obj IPALNode) MarshalYAML() (interface{}, error) {
 		Details         string
 		Extension       string
 		Bond            sdk.Coin
+		DelegatedBond   sdk.Coin
+		Jailed          bool
 	}{
 		OperatorAddress: obj.OperatorAddress,
 		Moniker:         obj.Moniker,
@@ -102,6 +137,8 @@ func (obj IPALNode) MarshalYAML() (interface{}, error) {
 		Details:         obj.Details,
 		Extension:       obj.Extension,
 		Bond:            obj.Bond,
+		DelegatedBond:   obj.GetDelegatedBond(),
+		Jailed:          obj.Jailed,
 	})
 
 	if err != nil {
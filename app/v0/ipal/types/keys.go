@@ -13,6 +13,9 @@ const (
 	StoreKey     = ModuleName
 	RouterKey    = ModuleName
 	QuerierRoute = ModuleName
+
+	// RewardPoolName is the name of the module account holding the rewards of the ipal nodes
+	RewardPoolName = "ipal_reward_pool"
 )
 
 var (
@@ -20,6 +23,27 @@ var (
 	IPALNodeByBondKey    = []byte{0x11}
 	IPALNodeByMonikerKey = []byte{0x12}
 	UnBondingKey         = []byte{0x13}
+
+	LivenessInfoKey         = []byte{0x14}
+	MissedWindowBitArrayKey = []byte{0x15}
+	WindowDowntimeKey       = []byte{0x16}
+	WindowUserReportKey     = []byte{0x17}
+
+	ServiceTypeKey = []byte{0x18}
+
+	OutstandingRewardsKey      = []byte{0x19}
+	WithdrawAddrKey            = []byte{0x1A}
+	TotalOutstandingRewardsKey = []byte{0x1B}
+
+	DelegationKey       = []byte{0x1C}
+	DelegationByNodeKey = []byte{0x1D}
+
+	RewardRatioKey     = []byte{0x1E}
+	ActiveBondKey      = []byte{0x1F}
+	NodeRewardRatioKey = []byte{0x20}
+	BondRewardRatioKey = []byte{0x21}
+
+	UnBondingByNodeKey = []byte{0x22}
 )
 
 func GetIPALNodeKey(addr sdk.AccAddress) []byte {
@@ -27,7 +51,7 @@ func GetIPALNodeKey(addr sdk.AccAddress) []byte {
 }
 
 func GetIPALNodeByBondKey(obj IPALNode) []byte {
-	bond := obj.Bond.Amount.Int64()
+	bond := obj.TotalBond().Amount.Int64()
 	bondBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(bondBytes, uint64(bond))
 
@@ -54,3 +78,88 @@ func GetUnBondingKey(timestamp time.Time) []byte {
 	v := sdk.FormatTimeBytes(timestamp)
 	return append(UnBondingKey, v...)
 }
+
+// GetUnBondingByNodeKey is the key indexing the unbonding queue time slices holding bonds unbonded from an ipal node
+func GetUnBondingByNodeKey(operator sdk.AccAddress, timestamp time.Time) []byte {
+	return append(GetUnBondingsByNodeKey(operator), sdk.FormatTimeBytes(timestamp)...)
+}
+
+func GetUnBondingsByNodeKey(operator sdk.AccAddress) []byte {
+	return append(UnBondingByNodeKey, operator...)
+}
+
+func GetLivenessInfoKey(addr sdk.AccAddress) []byte {
+	return append(LivenessInfoKey, addr...)
+}
+
+func GetMissedWindowBitArrayPrefixKey(addr sdk.AccAddress) []byte {
+	return append(MissedWindowBitArrayKey, addr...)
+}
+
+func GetMissedWindowBitArrayKey(addr sdk.AccAddress, i int64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(i))
+	return append(GetMissedWindowBitArrayPrefixKey(addr), b...)
+}
+
+// GetWindowDowntimeKey is the key of the mark of an ipal node reported down in the window in progress
+func GetWindowDowntimeKey(addr sdk.AccAddress) []byte {
+	return append(WindowDowntimeKey, addr...)
+}
+
+func GetWindowUserReportPrefixKey(addr sdk.AccAddress) []byte {
+	return append(WindowUserReportKey, addr...)
+}
+
+// GetWindowUserReportKey is the key of the downtime report of an ipal node by a user in the window in progress
+func GetWindowUserReportKey(addr, user sdk.AccAddress) []byte {
+	return append(GetWindowUserReportPrefixKey(addr), user...)
+}
+
+func GetServiceTypeKey(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return append(ServiceTypeKey, b...)
+}
+
+// GetOutstandingRewardsKey is the key of the rewards of an ipal node not withdrawn yet
+func GetOutstandingRewardsKey(addr sdk.AccAddress) []byte {
+	return append(OutstandingRewardsKey, addr...)
+}
+
+func GetWithdrawAddrKey(addr sdk.AccAddress) []byte {
+	return append(WithdrawAddrKey, addr...)
+}
+
+// GetDelegationKey is the key of the bond delegated by a delegator to an ipal node
+func GetDelegationKey(delegator, operator sdk.AccAddress) []byte {
+	return append(GetDelegationsKey(delegator), operator...)
+}
+
+func GetDelegationsKey(delegator sdk.AccAddress) []byte {
+	return append(DelegationKey, delegator...)
+}
+
+// GetDelegationByNodeKey is the key indexing the delegations of an ipal node
+func GetDelegationByNodeKey(operator, delegator sdk.AccAddress) []byte {
+	return append(GetDelegationsByNodeKey(operator), delegator...)
+}
+
+func GetDelegationsByNodeKey(operator sdk.AccAddress) []byte {
+	return append(DelegationByNodeKey, operator...)
+}
+
+// GetNodeRewardRatioKey is the key of the rewards per bond unit earned by an ipal node
+func GetNodeRewardRatioKey(operator sdk.AccAddress) []byte {
+	return append(NodeRewardRatioKey, operator...)
+}
+
+// GetBondRewardRatioKey is the key of the rewards per bond unit earned by an ipal node when the rewards of
+// the self-bond of the operator, or of a delegation, were last settled
+func GetBondRewardRatioKey(operator, holder sdk.AccAddress) []byte {
+	return append(GetBondRewardRatiosKey(operator), holder...)
+}
+
+func GetBondRewardRatiosKey(operator sdk.AccAddress) []byte {
+	return append(BondRewardRatioKey, operator...)
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// ReportType is the kind of evidence a report holds against an ipal node
+type ReportType uint8
+
+const (
+	// ReportDowntime reports an ipal node unreachable at its endpoints, the availability
+	// window in progress is missed when a reporter or UserReportThreshold users report it
+	ReportDowntime ReportType = 1
+	// ReportMisbehaviour reports an ipal node serving its users wrongly, only reporters
+	// submit it and the node is slashed and jailed at once
+	ReportMisbehaviour ReportType = 2
+)
+
+func (t ReportType) IsValid() bool {
+	return t == ReportDowntime || t == ReportMisbehaviour
+}
+
+func (t ReportType) String() string {
+	switch t {
+	case ReportDowntime:
+		return "downtime"
+	case ReportMisbehaviour:
+		return "misbehaviour"
+	default:
+		return fmt.Sprintf("%d", t)
+	}
+}
+
+// ReportTypeFromString parses the name of a report type
+func ReportTypeFromString(s string) (ReportType, error) {
+	switch s {
+	case ReportDowntime.String():
+		return ReportDowntime, nil
+	case ReportMisbehaviour.String():
+		return ReportMisbehaviour, nil
+	default:
+		return 0, fmt.Errorf("invalid report type: %s, should be %s or %s", s, ReportDowntime, ReportMisbehaviour)
+	}
+}
+
+// LivenessInfo is the availability of an ipal node over the last windows
+type LivenessInfo struct {
+	Address              sdk.AccAddress `json:"address" yaml:"address"`                               // operator address of the ipal node
+	IndexOffset          int64          `json:"index_offset" yaml:"index_offset"`                     // windows tracked since the node was claimed or unjailed
+	JailedUntil          time.Time      `json:"jailed_until" yaml:"jailed_until"`                     // timestamp the node cannot be unjailed until
+	MissedWindowsCounter int64          `json:"missed_windows_counter" yaml:"missed_windows_counter"` // missed windows in the missed window bit array
+}
+
+func NewLivenessInfo(addr sdk.AccAddress, indexOffset int64, jailedUntil time.Time, missedWindowsCounter int64) LivenessInfo {
+	return LivenessInfo{
+		Address:              addr,
+		IndexOffset:          indexOffset,
+		JailedUntil:          jailedUntil,
+		MissedWindowsCounter: missedWindowsCounter,
+	}
+}
+
+func (i LivenessInfo) String() string {
+	return fmt.Sprintf(`IPAL Node Liveness Info:
+  Address:                %s
+  Index Offset:           %d
+  Jailed Until:           %v
+  Missed Windows Counter: %d`,
+		i.Address, i.IndexOffset, i.JailedUntil, i.MissedWindowsCounter)
+}
+
+// MissedWindow is a window of the missed window bit array of an ipal node
+type MissedWindow struct {
+	Index  int64 `json:"index" yaml:"index"`
+	Missed bool  `json:"missed" yaml:"missed"`
+}
+
+// NodeMissedWindows are the missed window bit array of an ipal node, exported in the genesis
+type NodeMissedWindows struct {
+	Address       sdk.AccAddress `json:"address" yaml:"address"`
+	MissedWindows []MissedWindow `json:"missed_windows" yaml:"missed_windows"`
+}
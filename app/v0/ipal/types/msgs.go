@@ -11,9 +11,36 @@ import (
 
 var (
 	_ sdk.Msg = MsgIPALNodeClaim{}
+	_ sdk.Msg = MsgIPALNodeReport{}
+	_ sdk.Msg = MsgIPALNodeUnjail{}
+	_ sdk.Msg = MsgIPALNodeUnclaim{}
+	_ sdk.Msg = MsgIPALNodeEditEndpoints{}
+	_ sdk.Msg = MsgIPALNodeEditDescription{}
+	_ sdk.Msg = MsgAddServiceType{}
+	_ sdk.Msg = MsgDeleteServiceType{}
+	_ sdk.Msg = MsgWithdrawIPALNodeReward{}
+	_ sdk.Msg = MsgSetIPALWithdrawAddress{}
 )
 
-const TypeMsgIPALNodeClaim = "ipalNodeClaim"
+const (
+	TypeMsgIPALNodeClaim           = "ipalNodeClaim"
+	TypeMsgIPALNodeReport          = "ipalNodeReport"
+	TypeMsgIPALNodeUnjail          = "ipalNodeUnjail"
+	TypeMsgIPALNodeUnclaim         = "ipalNodeUnclaim"
+	TypeMsgIPALNodeEditEndpoints   = "ipalNodeEditEndpoints"
+	TypeMsgIPALNodeEditDescription = "ipalNodeEditDescription"
+	TypeMsgAddServiceType          = "addServiceType"
+	TypeMsgDeleteServiceType       = "deleteServiceType"
+	TypeMsgWithdrawIPALNodeReward  = "withdrawIPALNodeReward"
+	TypeMsgSetIPALWithdrawAddress  = "setIPALWithdrawAddress"
+	TypeMsgIPALDelegate            = "ipalDelegate"
+	TypeMsgIPALUndelegate          = "ipalUndelegate"
+
+	MaxEvidenceLength = 1024
+
+	// DoNotModifyDesc is the value of the description fields of MsgIPALNodeEditDescription left unchanged
+	DoNotModifyDesc = "[do-not-modify]"
+)
 
 type Endpoint struct {
 	Type     uint64 `json:"type" yaml:"type"`
@@ -108,3 +135,436 @@ func (msg MsgIPALNodeClaim) GetSignBytes() []byte {
 	bz := ModuleCdc.MustMarshalJSON(msg)
 	return sdk.MustSortJSON(bz)
 }
+
+// MsgIPALNodeReport reports the downtime or the misbehaviour of an ipal node, signed by the reporter
+type MsgIPALNodeReport struct {
+	Reporter        sdk.AccAddress `json:"reporter" yaml:"reporter"`                 // reporter or user served by the ipal node
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the reported IPALNode's operator
+	ReportType      ReportType     `json:"report_type" yaml:"report_type"`           // downtime or misbehaviour
+	Evidence        string         `json:"evidence" yaml:"evidence"`                 // optional evidence for the reported fault
+}
+
+func NewMsgIPALNodeReport(reporter, operator sdk.AccAddress, reportType ReportType, evidence string) MsgIPALNodeReport {
+	return MsgIPALNodeReport{
+		Reporter:        reporter,
+		OperatorAddress: operator,
+		ReportType:      reportType,
+		Evidence:        evidence,
+	}
+}
+
+// Implements Msg
+func (msg MsgIPALNodeReport) Route() string { return RouterKey }
+func (msg MsgIPALNodeReport) Type() string  { return TypeMsgIPALNodeReport }
+func (msg MsgIPALNodeReport) ValidateBasic() error {
+	if msg.Reporter.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing reporter address")
+	}
+
+	if msg.OperatorAddress.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing operator address")
+	}
+
+	if msg.Reporter.Equals(msg.OperatorAddress) {
+		return sdkerrors.Wrap(ErrInvalidReporter, "ipal node reports itself")
+	}
+
+	if !msg.ReportType.IsValid() {
+		return sdkerrors.Wrapf(ErrInvalidReportType, "report type: %s", msg.ReportType)
+	}
+
+	if len(msg.Evidence) > MaxEvidenceLength {
+		return sdkerrors.Wrapf(ErrEmptyInputs, "evidence longer than %d", MaxEvidenceLength)
+	}
+
+	return nil
+}
+
+func (msg MsgIPALNodeReport) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Reporter}
+}
+
+func (msg MsgIPALNodeReport) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgIPALNodeUnjail brings a jailed ipal node back once its jail period is over
+type MsgIPALNodeUnjail struct {
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the IPALNode's operator
+}
+
+func NewMsgIPALNodeUnjail(operator sdk.AccAddress) MsgIPALNodeUnjail {
+	return MsgIPALNodeUnjail{
+		OperatorAddress: operator,
+	}
+}
+
+// Implements Msg
+func (msg MsgIPALNodeUnjail) Route() string { return RouterKey }
+func (msg MsgIPALNodeUnjail) Type() string  { return TypeMsgIPALNodeUnjail }
+func (msg MsgIPALNodeUnjail) ValidateBasic() error {
+	if msg.OperatorAddress.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing operator address")
+	}
+
+	return nil
+}
+
+func (msg MsgIPALNodeUnjail) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.OperatorAddress}
+}
+
+func (msg MsgIPALNodeUnjail) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgIPALNodeUnclaim leaves the ipal node registry, the whole bond is unbonded
+type MsgIPALNodeUnclaim struct {
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the IPALNode's operator
+}
+
+func NewMsgIPALNodeUnclaim(operator sdk.AccAddress) MsgIPALNodeUnclaim {
+	return MsgIPALNodeUnclaim{
+		OperatorAddress: operator,
+	}
+}
+
+// Implements Msg
+func (msg MsgIPALNodeUnclaim) Route() string { return RouterKey }
+func (msg MsgIPALNodeUnclaim) Type() string  { return TypeMsgIPALNodeUnclaim }
+func (msg MsgIPALNodeUnclaim) ValidateBasic() error {
+	if msg.OperatorAddress.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing operator address")
+	}
+
+	return nil
+}
+
+func (msg MsgIPALNodeUnclaim) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.OperatorAddress}
+}
+
+func (msg MsgIPALNodeUnclaim) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgIPALNodeEditEndpoints replaces the endpoints of an ipal node
+type MsgIPALNodeEditEndpoints struct {
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the IPALNode's operator
+	Endpoints       Endpoints      `json:"endpoints" yaml:"endpoints"`               // server endpoint for app client
+}
+
+func NewMsgIPALNodeEditEndpoints(operator sdk.AccAddress, endpoints Endpoints) MsgIPALNodeEditEndpoints {
+	return MsgIPALNodeEditEndpoints{
+		OperatorAddress: operator,
+		Endpoints:       endpoints,
+	}
+}
+
+// Implements Msg
+func (msg MsgIPALNodeEditEndpoints) Route() string { return RouterKey }
+func (msg MsgIPALNodeEditEndpoints) Type() string  { return TypeMsgIPALNodeEditEndpoints }
+func (msg MsgIPALNodeEditEndpoints) ValidateBasic() error {
+	if msg.OperatorAddress.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing operator address")
+	}
+
+	if len(msg.Endpoints) == 0 {
+		return ErrEndpointsEmpty
+	}
+
+	if err := EndpointsDupCheck(msg.Endpoints); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (msg MsgIPALNodeEditEndpoints) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.OperatorAddress}
+}
+
+func (msg MsgIPALNodeEditEndpoints) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgIPALNodeEditDescription edits the moniker, website, details and extension of an ipal node,
+// the fields set to DoNotModifyDesc are left unchanged
+type MsgIPALNodeEditDescription struct {
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the IPALNode's operator
+	Moniker         string         `json:"moniker" yaml:"moniker"`                   // name
+	Website         string         `json:"website" yaml:"website"`                   // optional website link
+	Details         string         `json:"details" yaml:"details"`                   // optional details
+	Extension       string         `json:"extension" yaml:"extension"`               // for future extension
+}
+
+func NewMsgIPALNodeEditDescription(operator sdk.AccAddress, moniker, website, details, extension string) MsgIPALNodeEditDescription {
+	return MsgIPALNodeEditDescription{
+		OperatorAddress: operator,
+		Moniker:         moniker,
+		Website:         website,
+		Details:         details,
+		Extension:       extension,
+	}
+}
+
+// Implements Msg
+func (msg MsgIPALNodeEditDescription) Route() string { return RouterKey }
+func (msg MsgIPALNodeEditDescription) Type() string  { return TypeMsgIPALNodeEditDescription }
+func (msg MsgIPALNodeEditDescription) ValidateBasic() error {
+	if msg.OperatorAddress.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing operator address")
+	}
+
+	if msg.Moniker == "" {
+		return sdkerrors.Wrap(ErrEmptyInputs, "moniker empty")
+	}
+
+	return nil
+}
+
+func (msg *MsgIPALNodeEditDescription) TrimSpace() {
+	msg.Moniker = strings.TrimSpace(msg.Moniker)
+	msg.Website = strings.TrimSpace(msg.Website)
+	msg.Details = strings.TrimSpace(msg.Details)
+	msg.Extension = strings.TrimSpace(msg.Extension)
+}
+
+func (msg MsgIPALNodeEditDescription) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.OperatorAddress}
+}
+
+func (msg MsgIPALNodeEditDescription) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgAddServiceType registers a service type, sent by a profiler of the guardian module
+type MsgAddServiceType struct {
+	AddedBy     sdk.AccAddress  `json:"added_by" yaml:"added_by"`
+	ServiceType ServiceTypeInfo `json:"service_type" yaml:"service_type"`
+}
+
+func NewMsgAddServiceType(addedBy sdk.AccAddress, serviceType ServiceTypeInfo) MsgAddServiceType {
+	return MsgAddServiceType{
+		AddedBy:     addedBy,
+		ServiceType: serviceType,
+	}
+}
+
+// Implements Msg
+func (msg MsgAddServiceType) Route() string { return RouterKey }
+func (msg MsgAddServiceType) Type() string  { return TypeMsgAddServiceType }
+func (msg MsgAddServiceType) ValidateBasic() error {
+	if msg.AddedBy.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing added_by address")
+	}
+
+	return msg.ServiceType.Validate()
+}
+
+func (msg MsgAddServiceType) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.AddedBy}
+}
+
+func (msg MsgAddServiceType) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgDeleteServiceType deletes a service type from the registry, sent by a profiler of the guardian module.
+// The endpoints already claimed with the type are kept.
+type MsgDeleteServiceType struct {
+	DeletedBy sdk.AccAddress `json:"deleted_by" yaml:"deleted_by"`
+	ID        uint64         `json:"id" yaml:"id"`
+}
+
+func NewMsgDeleteServiceType(deletedBy sdk.AccAddress, id uint64) MsgDeleteServiceType {
+	return MsgDeleteServiceType{
+		DeletedBy: deletedBy,
+		ID:        id,
+	}
+}
+
+// Implements Msg
+func (msg MsgDeleteServiceType) Route() string { return RouterKey }
+func (msg MsgDeleteServiceType) Type() string  { return TypeMsgDeleteServiceType }
+func (msg MsgDeleteServiceType) ValidateBasic() error {
+	if msg.DeletedBy.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing deleted_by address")
+	}
+
+	if msg.ID == 0 {
+		return sdkerrors.Wrap(ErrInvalidServiceType, "service type id must be positive")
+	}
+
+	return nil
+}
+
+func (msg MsgDeleteServiceType) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.DeletedBy}
+}
+
+func (msg MsgDeleteServiceType) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgWithdrawIPALNodeReward withdraws the rewards of an ipal node operator, or of a delegator, to its withdraw address
+type MsgWithdrawIPALNodeReward struct {
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the IPALNode's operator
+}
+
+func NewMsgWithdrawIPALNodeReward(operator sdk.AccAddress) MsgWithdrawIPALNodeReward {
+	return MsgWithdrawIPALNodeReward{
+		OperatorAddress: operator,
+	}
+}
+
+// Implements Msg
+func (msg MsgWithdrawIPALNodeReward) Route() string { return RouterKey }
+func (msg MsgWithdrawIPALNodeReward) Type() string  { return TypeMsgWithdrawIPALNodeReward }
+func (msg MsgWithdrawIPALNodeReward) ValidateBasic() error {
+	if msg.OperatorAddress.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing operator address")
+	}
+
+	return nil
+}
+
+func (msg MsgWithdrawIPALNodeReward) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.OperatorAddress}
+}
+
+func (msg MsgWithdrawIPALNodeReward) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgSetIPALWithdrawAddress sets the address receiving the rewards of an ipal node operator, or of a delegator,
+// the sender by default
+type MsgSetIPALWithdrawAddress struct {
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the IPALNode's operator
+	WithdrawAddress sdk.AccAddress `json:"withdraw_address" yaml:"withdraw_address"`
+}
+
+func NewMsgSetIPALWithdrawAddress(operator, withdrawAddr sdk.AccAddress) MsgSetIPALWithdrawAddress {
+	return MsgSetIPALWithdrawAddress{
+		OperatorAddress: operator,
+		WithdrawAddress: withdrawAddr,
+	}
+}
+
+// Implements Msg
+func (msg MsgSetIPALWithdrawAddress) Route() string { return RouterKey }
+func (msg MsgSetIPALWithdrawAddress) Type() string  { return TypeMsgSetIPALWithdrawAddress }
+func (msg MsgSetIPALWithdrawAddress) ValidateBasic() error {
+	if msg.OperatorAddress.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing operator address")
+	}
+
+	if msg.WithdrawAddress.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing withdraw address")
+	}
+
+	return nil
+}
+
+func (msg MsgSetIPALWithdrawAddress) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.OperatorAddress}
+}
+
+func (msg MsgSetIPALWithdrawAddress) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgIPALDelegate delegates a bond to an ipal node, adding to the bond ordering the node
+type MsgIPALDelegate struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address" yaml:"delegator_address"`
+	OperatorAddress  sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the IPALNode's operator
+	Amount           sdk.Coin       `json:"amount" yaml:"amount"`
+}
+
+func NewMsgIPALDelegate(delegator, operator sdk.AccAddress, amount sdk.Coin) MsgIPALDelegate {
+	return MsgIPALDelegate{
+		DelegatorAddress: delegator,
+		OperatorAddress:  operator,
+		Amount:           amount,
+	}
+}
+
+// Implements Msg
+func (msg MsgIPALDelegate) Route() string { return RouterKey }
+func (msg MsgIPALDelegate) Type() string  { return TypeMsgIPALDelegate }
+func (msg MsgIPALDelegate) ValidateBasic() error {
+	return validateDelegation(msg.DelegatorAddress, msg.OperatorAddress, msg.Amount)
+}
+
+func (msg MsgIPALDelegate) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.DelegatorAddress}
+}
+
+func (msg MsgIPALDelegate) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// MsgIPALUndelegate moves a delegated bond to the unbonding queue
+type MsgIPALUndelegate struct {
+	DelegatorAddress sdk.AccAddress `json:"delegator_address" yaml:"delegator_address"`
+	OperatorAddress  sdk.AccAddress `json:"operator_address" yaml:"operator_address"` // address of the IPALNode's operator
+	Amount           sdk.Coin       `json:"amount" yaml:"amount"`
+}
+
+func NewMsgIPALUndelegate(delegator, operator sdk.AccAddress, amount sdk.Coin) MsgIPALUndelegate {
+	return MsgIPALUndelegate{
+		DelegatorAddress: delegator,
+		OperatorAddress:  operator,
+		Amount:           amount,
+	}
+}
+
+// Implements Msg
+func (msg MsgIPALUndelegate) Route() string { return RouterKey }
+func (msg MsgIPALUndelegate) Type() string  { return TypeMsgIPALUndelegate }
+func (msg MsgIPALUndelegate) ValidateBasic() error {
+	return validateDelegation(msg.DelegatorAddress, msg.OperatorAddress, msg.Amount)
+}
+
+func (msg MsgIPALUndelegate) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.DelegatorAddress}
+}
+
+func (msg MsgIPALUndelegate) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+func validateDelegation(delegator, operator sdk.AccAddress, amount sdk.Coin) error {
+	if delegator.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing delegator address")
+	}
+
+	if operator.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidAddress, "missing operator address")
+	}
+
+	if delegator.Equals(operator) {
+		return sdkerrors.Wrap(ErrInvalidDelegator, "the operator bonds by claiming the node")
+	}
+
+	if amount.Denom != sdk.NativeTokenName {
+		return sdkerrors.Wrapf(ErrBadDenom, "amount denom must be %s", sdk.NativeTokenName)
+	}
+
+	if !amount.IsPositive() {
+		return sdkerrors.Wrap(ErrEmptyInputs, "amount must > 0")
+	}
+
+	return nil
+}
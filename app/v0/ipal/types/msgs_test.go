@@ -76,3 +76,149 @@ func TestMsgIPALNodeClaimGetSigners(t *testing.T) {
 
 	require.Equal(t, fmt.Sprintf("%v", res), "[696E70757431]")
 }
+
+func TestMsgIPALNodeReportValidation(t *testing.T) {
+	var emptyAddr sdk.AccAddress
+	operator := sdk.AccAddress([]byte("operator"))
+
+	cases := []struct {
+		valid bool
+		tx    MsgIPALNodeReport
+	}{
+		{true, NewMsgIPALNodeReport(addr1, operator, ReportDowntime, "")},
+		{true, NewMsgIPALNodeReport(addr1, operator, ReportMisbehaviour, "evidence")},
+		{false, NewMsgIPALNodeReport(emptyAddr, operator, ReportDowntime, "")},                                    // empty reporter
+		{false, NewMsgIPALNodeReport(addr1, emptyAddr, ReportDowntime, "")},                                       // empty operator
+		{false, NewMsgIPALNodeReport(operator, operator, ReportDowntime, "")},                                     // report itself
+		{false, NewMsgIPALNodeReport(addr1, operator, ReportType(3), "")},                                         // invalid type
+		{false, NewMsgIPALNodeReport(addr1, operator, ReportDowntime, string(make([]byte, MaxEvidenceLength+1)))}, // evidence too long
+	}
+
+	for _, tc := range cases {
+		err := tc.tx.ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+
+	require.NotNil(t, NewMsgIPALNodeUnjail(emptyAddr).ValidateBasic())
+	require.Nil(t, NewMsgIPALNodeUnjail(addr1).ValidateBasic())
+}
+
+func TestMsgIPALNodeReportGetSignBytes(t *testing.T) {
+	var msg = NewMsgIPALNodeReport(addr1, sdk.AccAddress([]byte("operator")), ReportDowntime, "timeout")
+	res := msg.GetSignBytes()
+
+	expected := `{"type":"nch/IPALReport","value":{"evidence":"timeout","operator_address":"nch1dacx2unpw3hhyjeues7","report_type":1,"reporter":"nch1veex7mg3k0xqr"}}`
+	require.Equal(t, expected, string(res))
+}
+
+func TestReportTypeFromString(t *testing.T) {
+	reportType, err := ReportTypeFromString("misbehaviour")
+	require.NoError(t, err)
+	require.Equal(t, ReportMisbehaviour, reportType)
+
+	_, err = ReportTypeFromString("offline")
+	require.Error(t, err)
+}
+
+func TestMsgIPALNodeEditValidation(t *testing.T) {
+	var emptyAddr sdk.AccAddress
+	dupEndpoints, _ := EndpointsFromString("1|http://1.1.1.1,1|http://2.2.2.2", ",", "|")
+
+	require.Nil(t, NewMsgIPALNodeUnclaim(addr1).ValidateBasic())
+	require.NotNil(t, NewMsgIPALNodeUnclaim(emptyAddr).ValidateBasic())
+
+	require.Nil(t, NewMsgIPALNodeEditEndpoints(addr1, endpoints).ValidateBasic())
+	require.NotNil(t, NewMsgIPALNodeEditEndpoints(emptyAddr, endpoints).ValidateBasic())
+	require.NotNil(t, NewMsgIPALNodeEditEndpoints(addr1, Endpoints{}).ValidateBasic())
+	require.NotNil(t, NewMsgIPALNodeEditEndpoints(addr1, dupEndpoints).ValidateBasic())
+
+	require.Nil(t, NewMsgIPALNodeEditDescription(addr1, moniker, website, details, extension).ValidateBasic())
+	require.Nil(t, NewMsgIPALNodeEditDescription(addr1, DoNotModifyDesc, DoNotModifyDesc, DoNotModifyDesc, DoNotModifyDesc).ValidateBasic())
+	require.NotNil(t, NewMsgIPALNodeEditDescription(emptyAddr, moniker, website, details, extension).ValidateBasic())
+	require.NotNil(t, NewMsgIPALNodeEditDescription(addr1, "", website, details, extension).ValidateBasic())
+}
+
+func TestMsgIPALRewardValidation(t *testing.T) {
+	var emptyAddr sdk.AccAddress
+
+	require.Nil(t, NewMsgWithdrawIPALNodeReward(addr1).ValidateBasic())
+	require.NotNil(t, NewMsgWithdrawIPALNodeReward(emptyAddr).ValidateBasic())
+
+	require.Nil(t, NewMsgSetIPALWithdrawAddress(addr1, addr1).ValidateBasic())
+	require.NotNil(t, NewMsgSetIPALWithdrawAddress(emptyAddr, addr1).ValidateBasic())
+	require.NotNil(t, NewMsgSetIPALWithdrawAddress(addr1, emptyAddr).ValidateBasic())
+}
+
+func TestMsgIPALDelegateValidation(t *testing.T) {
+	var emptyAddr sdk.AccAddress
+	addr2 := sdk.AccAddress([]byte("operator"))
+	amount := sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(1))
+
+	require.Nil(t, NewMsgIPALDelegate(addr1, addr2, amount).ValidateBasic())
+	require.NotNil(t, NewMsgIPALDelegate(emptyAddr, addr2, amount).ValidateBasic())
+	require.NotNil(t, NewMsgIPALDelegate(addr1, emptyAddr, amount).ValidateBasic())
+	require.NotNil(t, NewMsgIPALDelegate(addr1, addr1, amount).ValidateBasic())
+	require.NotNil(t, NewMsgIPALDelegate(addr1, addr2, sdk.NewCoin("foo", sdk.NewInt(1))).ValidateBasic())
+	require.NotNil(t, NewMsgIPALDelegate(addr1, addr2, sdk.NewCoin(sdk.NativeTokenName, sdk.ZeroInt())).ValidateBasic())
+
+	require.Nil(t, NewMsgIPALUndelegate(addr1, addr2, amount).ValidateBasic())
+	require.NotNil(t, NewMsgIPALUndelegate(addr1, addr1, amount).ValidateBasic())
+	require.Equal(t, []sdk.AccAddress{addr1}, NewMsgIPALUndelegate(addr1, addr2, amount).GetSigners())
+}
+
+func TestIPALNodeUpdateDescription(t *testing.T) {
+	node := NewIPALNode(addr1, moniker, website, details, extension, endpoints, bond)
+
+	updated := node.UpdateDescription(NewMsgIPALNodeEditDescription(addr1, DoNotModifyDesc, "new website", "", DoNotModifyDesc))
+	require.Equal(t, moniker, updated.Moniker)
+	require.Equal(t, "new website", updated.Website)
+	require.Equal(t, "", updated.Details)
+	require.Equal(t, extension, updated.Extension)
+	require.Equal(t, node.Endpoints, updated.Endpoints)
+}
+
+func TestServiceTypeInfoValidation(t *testing.T) {
+	cases := []struct {
+		valid       bool
+		serviceType ServiceTypeInfo
+	}{
+		{true, NewServiceTypeInfo(3, "relay", "", "", "")},
+		{true, NewServiceTypeInfo(3, "relay", "message relay", "host:port", `^[a-z.]+:[0-9]+$`)},
+		{false, NewServiceTypeInfo(0, "relay", "", "", "")},                                          // zero id
+		{false, NewServiceTypeInfo(3, "", "", "", "")},                                               // empty name
+		{false, NewServiceTypeInfo(3, "relay", "", "", `^(`)},                                        // invalid regex
+		{false, NewServiceTypeInfo(3, string(make([]byte, MaxServiceTypeNameLength+1)), "", "", "")}, // name too long
+	}
+
+	for _, tc := range cases {
+		err := NewMsgAddServiceType(addr1, tc.serviceType).ValidateBasic()
+		if tc.valid {
+			require.Nil(t, err)
+		} else {
+			require.NotNil(t, err)
+		}
+	}
+
+	require.Nil(t, DefaultServiceTypes().Validate())
+	require.NotNil(t, append(DefaultServiceTypes(), DefaultServiceTypes()[0]).Validate())
+
+	require.Nil(t, NewMsgDeleteServiceType(addr1, 1).ValidateBasic())
+	require.NotNil(t, NewMsgDeleteServiceType(addr1, 0).ValidateBasic())
+	require.NotNil(t, NewMsgDeleteServiceType(sdk.AccAddress{}, 1).ValidateBasic())
+}
+
+func TestServiceTypeInfoValidateEndpoint(t *testing.T) {
+	chatting := DefaultServiceTypes()[0]
+	for _, endpoint := range []string{"192.168.1.100:10000", "http://1.1.1.1", "wss://chat.netcloth.org:443/ws", "nch1veex7mg3k0xqr"} {
+		require.Nil(t, chatting.ValidateEndpoint(endpoint), endpoint)
+	}
+	for _, endpoint := range []string{"", "1.1.1.1 2.2.2.2", "host:port", "http://"} {
+		require.NotNil(t, chatting.ValidateEndpoint(endpoint), endpoint)
+	}
+
+	require.Nil(t, NewServiceTypeInfo(3, "any", "", "", "").ValidateEndpoint("anything goes"))
+}
@@ -9,29 +9,74 @@ import (
 )
 
 const (
-	DefaultUnbondingTime = time.Hour * 24 * 7
+	DefaultUnbondingTime            = time.Hour * 24 * 7
+	DefaultAvailabilityWindow       = int64(720)
+	DefaultLivenessWindow           = int64(24)
+	DefaultUserReportThreshold      = int64(5)
+	DefaultDowntimeJailDuration     = time.Hour * 24
+	DefaultMisbehaviourJailDuration = time.Hour * 24 * 7
 )
 
 var (
-	DefaultMinBond = sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(sdk.NativeTokenFraction))
+	DefaultMinBond                   = sdk.NewCoin(sdk.NativeTokenName, sdk.NewInt(sdk.NativeTokenFraction))
+	DefaultMinAvailablePerWindow     = sdk.NewDecWithPrec(5, 1)
+	DefaultSlashFractionDowntime     = sdk.NewDecWithPrec(1, 2)
+	DefaultSlashFractionMisbehaviour = sdk.NewDecWithPrec(5, 2)
+	DefaultRewardFraction            = sdk.NewDecWithPrec(2, 2)
 )
 
 var (
-	KeyUnbondingTime = []byte("UnbondingTime")
-	KeyMinBond       = []byte("MinBond")
+	KeyUnbondingTime             = []byte("UnbondingTime")
+	KeyMinBond                   = []byte("MinBond")
+	KeyAvailabilityWindow        = []byte("AvailabilityWindow")
+	KeyLivenessWindow            = []byte("LivenessWindow")
+	KeyMinAvailablePerWindow     = []byte("MinAvailablePerWindow")
+	KeyUserReportThreshold       = []byte("UserReportThreshold")
+	KeyDowntimeJailDuration      = []byte("DowntimeJailDuration")
+	KeyMisbehaviourJailDuration  = []byte("MisbehaviourJailDuration")
+	KeySlashFractionDowntime     = []byte("SlashFractionDowntime")
+	KeySlashFractionMisbehaviour = []byte("SlashFractionMisbehaviour")
+	KeyRewardFraction            = []byte("RewardFraction")
 )
 
 type Params struct {
 	UnbondingTime time.Duration `json:"unbonding_time" yaml:"unbonding_time"`
 	MinBond       sdk.Coin      `json:"min_bond" yaml:"min_bond"`
+
+	// liveness of the ipal nodes: the availability of a node is tracked per window of
+	// AvailabilityWindow blocks over the last LivenessWindow windows, no liveness is
+	// tracked while either is 0
+	AvailabilityWindow        int64         `json:"availability_window" yaml:"availability_window"`
+	LivenessWindow            int64         `json:"liveness_window" yaml:"liveness_window"`
+	MinAvailablePerWindow     sdk.Dec       `json:"min_available_per_window" yaml:"min_available_per_window"`
+	UserReportThreshold       int64         `json:"user_report_threshold" yaml:"user_report_threshold"`
+	DowntimeJailDuration      time.Duration `json:"downtime_jail_duration" yaml:"downtime_jail_duration"`
+	MisbehaviourJailDuration  time.Duration `json:"misbehaviour_jail_duration" yaml:"misbehaviour_jail_duration"`
+	SlashFractionDowntime     sdk.Dec       `json:"slash_fraction_downtime" yaml:"slash_fraction_downtime"`
+	SlashFractionMisbehaviour sdk.Dec       `json:"slash_fraction_misbehaviour" yaml:"slash_fraction_misbehaviour"`
+
+	// rewards of the ipal nodes: RewardFraction of the coins minted and the fees collected each
+	// block is shared among the active nodes by bond
+	RewardFraction sdk.Dec `json:"reward_fraction" yaml:"reward_fraction"`
 }
 
 var _ params.ParamSet = (*Params)(nil)
 
-func NewParams(unbondingTime time.Duration, minBond sdk.Coin) Params {
+func NewParams(unbondingTime time.Duration, minBond sdk.Coin, availabilityWindow, livenessWindow int64,
+	minAvailablePerWindow sdk.Dec, userReportThreshold int64, downtimeJailDuration, misbehaviourJailDuration time.Duration,
+	slashFractionDowntime, slashFractionMisbehaviour, rewardFraction sdk.Dec) Params {
 	return Params{
-		UnbondingTime: unbondingTime,
-		MinBond:       minBond,
+		UnbondingTime:             unbondingTime,
+		MinBond:                   minBond,
+		AvailabilityWindow:        availabilityWindow,
+		LivenessWindow:            livenessWindow,
+		MinAvailablePerWindow:     minAvailablePerWindow,
+		UserReportThreshold:       userReportThreshold,
+		DowntimeJailDuration:      downtimeJailDuration,
+		MisbehaviourJailDuration:  misbehaviourJailDuration,
+		SlashFractionDowntime:     slashFractionDowntime,
+		SlashFractionMisbehaviour: slashFractionMisbehaviour,
+		RewardFraction:            rewardFraction,
 	}
 }
 
@@ -39,6 +84,15 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 	return params.ParamSetPairs{
 		params.NewParamSetPair(KeyUnbondingTime, &p.UnbondingTime, validateUnbondingTime),
 		params.NewParamSetPair(KeyMinBond, &p.MinBond, validateMinBond),
+		params.NewParamSetPair(KeyAvailabilityWindow, &p.AvailabilityWindow, validateAvailabilityWindow),
+		params.NewParamSetPair(KeyLivenessWindow, &p.LivenessWindow, validateLivenessWindow),
+		params.NewParamSetPair(KeyMinAvailablePerWindow, &p.MinAvailablePerWindow, validateFraction),
+		params.NewParamSetPair(KeyUserReportThreshold, &p.UserReportThreshold, validateUserReportThreshold),
+		params.NewParamSetPair(KeyDowntimeJailDuration, &p.DowntimeJailDuration, validateJailDuration),
+		params.NewParamSetPair(KeyMisbehaviourJailDuration, &p.MisbehaviourJailDuration, validateJailDuration),
+		params.NewParamSetPair(KeySlashFractionDowntime, &p.SlashFractionDowntime, validateFraction),
+		params.NewParamSetPair(KeySlashFractionMisbehaviour, &p.SlashFractionMisbehaviour, validateFraction),
+		params.NewParamSetPair(KeyRewardFraction, &p.RewardFraction, validateFraction),
 	}
 }
 
@@ -46,15 +100,42 @@ func DefaultParams() Params {
 	return NewParams(
 		DefaultUnbondingTime,
 		DefaultMinBond,
+		DefaultAvailabilityWindow,
+		DefaultLivenessWindow,
+		DefaultMinAvailablePerWindow,
+		DefaultUserReportThreshold,
+		DefaultDowntimeJailDuration,
+		DefaultMisbehaviourJailDuration,
+		DefaultSlashFractionDowntime,
+		DefaultSlashFractionMisbehaviour,
+		DefaultRewardFraction,
 	)
 }
 
 func (p Params) String() string {
 	return fmt.Sprintf(`Params:
   Unbonding Time    : %s
-  Min Bond   : %v`,
+  Min Bond   : %v
+  Availability Window         : %d
+  Liveness Window             : %d
+  Min Available Per Window    : %s
+  User Report Threshold       : %d
+  Downtime Jail Duration      : %s
+  Misbehaviour Jail Duration  : %s
+  Slash Fraction Downtime     : %s
+  Slash Fraction Misbehaviour : %s
+  Reward Fraction             : %s`,
 		p.UnbondingTime,
-		p.MinBond)
+		p.MinBond,
+		p.AvailabilityWindow,
+		p.LivenessWindow,
+		p.MinAvailablePerWindow,
+		p.UserReportThreshold,
+		p.DowntimeJailDuration,
+		p.MisbehaviourJailDuration,
+		p.SlashFractionDowntime,
+		p.SlashFractionMisbehaviour,
+		p.RewardFraction)
 }
 
 func validateUnbondingTime(i interface{}) error {
@@ -74,3 +155,71 @@ func validateMinBond(i interface{}) error {
 	// TODO
 	return nil
 }
+
+func validateAvailabilityWindow(i interface{}) error {
+	v, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < 0 {
+		return fmt.Errorf("availability window cannot be negative: %d", v)
+	}
+
+	return nil
+}
+
+func validateLivenessWindow(i interface{}) error {
+	v, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < 0 {
+		return fmt.Errorf("liveness window cannot be negative: %d", v)
+	}
+
+	return nil
+}
+
+func validateUserReportThreshold(i interface{}) error {
+	v, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < 0 {
+		return fmt.Errorf("user report threshold cannot be negative: %d", v)
+	}
+
+	return nil
+}
+
+func validateJailDuration(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v < 0 {
+		return fmt.Errorf("jail duration cannot be negative: %s", v)
+	}
+
+	return nil
+}
+
+func validateFraction(i interface{}) error {
+	v, ok := i.(sdk.Dec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("fraction cannot be negative: %s", v)
+	}
+	if v.GT(sdk.OneDec()) {
+		return fmt.Errorf("fraction too large: %s", v)
+	}
+
+	return nil
+}
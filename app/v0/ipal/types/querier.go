@@ -9,6 +9,12 @@ const (
 	QueryIPALNode     = "node"
 	QueryIPALNodes    = "nodes"
 	QueryParameters   = "params"
+	QueryLiveness     = "liveness"
+	QueryServiceTypes = "service_types"
+	QueryRewards      = "rewards"
+
+	QueryDelegations          = "delegations"
+	QueryDelegatorDelegations = "delegator_delegations"
 )
 
 type QueryIPALNodeParams struct {
@@ -0,0 +1,25 @@
+package types
+
+import (
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// NodeRewards are the rewards of an ipal node not withdrawn yet
+type NodeRewards struct {
+	Address sdk.AccAddress `json:"address" yaml:"address"`
+	Rewards sdk.Coins      `json:"rewards" yaml:"rewards"`
+}
+
+// WithdrawAddress is the address receiving the rewards of an ipal node
+type WithdrawAddress struct {
+	Address         sdk.AccAddress `json:"address" yaml:"address"`
+	WithdrawAddress sdk.AccAddress `json:"withdraw_address" yaml:"withdraw_address"`
+}
+
+// NodeRewardRatio is the rewards per bond unit earned by an ipal node, Ratio, when the reward ratio shared
+// by the nodes which aren't jailed was GlobalRatio: the node earns the increase of the shared ratio
+// while it isn't jailed
+type NodeRewardRatio struct {
+	Ratio       sdk.DecCoins `json:"ratio" yaml:"ratio"`
+	GlobalRatio sdk.DecCoins `json:"global_ratio" yaml:"global_ratio"`
+}
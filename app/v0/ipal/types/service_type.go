@@ -0,0 +1,121 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
+)
+
+const (
+	MaxServiceTypeNameLength        = 64
+	MaxServiceTypeDescriptionLength = 1024
+	MaxServiceTypeURLSchemaLength   = 256
+	MaxEndpointRegexLength          = 256
+
+	// DefaultEndpointRegex accepts a host, optionally preceded by a scheme and followed by a port and a path
+	DefaultEndpointRegex = `^([a-z][a-z0-9+.-]*://)?[A-Za-z0-9.-]+(:[0-9]{1,5})?(/[^\s]*)?$`
+)
+
+// ServiceTypeInfo is an entry of the service type registry, the types of the ipal node endpoints and
+// of the cipal service infos must be registered
+type ServiceTypeInfo struct {
+	ID            uint64 `json:"id" yaml:"id"`
+	Name          string `json:"name" yaml:"name"`
+	Description   string `json:"description" yaml:"description"`
+	URLSchema     string `json:"url_schema" yaml:"url_schema"`         // schema of the endpoint urls, for wallets
+	EndpointRegex string `json:"endpoint_regex" yaml:"endpoint_regex"` // regex the endpoints must match, any endpoint when empty
+}
+
+type ServiceTypeInfos []ServiceTypeInfo
+
+func NewServiceTypeInfo(id uint64, name, description, urlSchema, endpointRegex string) ServiceTypeInfo {
+	return ServiceTypeInfo{
+		ID:            id,
+		Name:          name,
+		Description:   description,
+		URLSchema:     urlSchema,
+		EndpointRegex: endpointRegex,
+	}
+}
+
+// DefaultServiceTypes returns the service types registered in a new genesis
+func DefaultServiceTypes() ServiceTypeInfos {
+	return ServiceTypeInfos{
+		NewServiceTypeInfo(uint64(Chatting), "chatting", "chat message relay", "[scheme://]host[:port][/path]", DefaultEndpointRegex),
+		NewServiceTypeInfo(uint64(Storage), "storage", "file storage", "[scheme://]host[:port][/path]", DefaultEndpointRegex),
+	}
+}
+
+// Validate - quick validity check
+func (s ServiceTypeInfo) Validate() error {
+	if s.ID == 0 {
+		return sdkerrors.Wrap(ErrInvalidServiceType, "service type id must be positive")
+	}
+
+	if s.Name == "" {
+		return sdkerrors.Wrap(ErrEmptyInputs, "service type name empty")
+	}
+
+	if len(s.Name) > MaxServiceTypeNameLength || len(s.Description) > MaxServiceTypeDescriptionLength ||
+		len(s.URLSchema) > MaxServiceTypeURLSchemaLength || len(s.EndpointRegex) > MaxEndpointRegexLength {
+		return sdkerrors.Wrap(ErrInvalidServiceType, "service type field too long")
+	}
+
+	if _, err := regexp.Compile(s.EndpointRegex); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidServiceType, "invalid endpoint regex: %s", err)
+	}
+
+	return nil
+}
+
+// ValidateEndpoint checks the endpoint matches the endpoint regex of the service type
+func (s ServiceTypeInfo) ValidateEndpoint(endpoint string) error {
+	if s.EndpointRegex == "" {
+		return nil
+	}
+
+	matched, err := regexp.MatchString(s.EndpointRegex, endpoint)
+	if err != nil {
+		return sdkerrors.Wrapf(ErrInvalidServiceType, "invalid endpoint regex: %s", err)
+	}
+
+	if !matched {
+		return sdkerrors.Wrapf(ErrEndpointsFormat, "endpoint [%s] doesn't match the %s service type: %s", endpoint, s.Name, s.URLSchema)
+	}
+
+	return nil
+}
+
+func (s ServiceTypeInfo) String() string {
+	return fmt.Sprintf(`ServiceType %d:
+  Name:           %s
+  Description:    %s
+  URL Schema:     %s
+  Endpoint Regex: %s`, s.ID, s.Name, s.Description, s.URLSchema, s.EndpointRegex)
+}
+
+// Validate checks each service type and the uniqueness of their ids
+func (s ServiceTypeInfos) Validate() error {
+	ids := make(map[uint64]bool, len(s))
+	for _, serviceType := range s {
+		if err := serviceType.Validate(); err != nil {
+			return err
+		}
+
+		if ids[serviceType.ID] {
+			return sdkerrors.Wrapf(ErrServiceTypeExists, "duplicate service type %d", serviceType.ID)
+		}
+		ids[serviceType.ID] = true
+	}
+
+	return nil
+}
+
+func (s ServiceTypeInfos) String() (out string) {
+	for _, val := range s {
+		out += val.String() + "\n"
+	}
+	return strings.TrimSpace(out)
+}
@@ -8,17 +8,23 @@ import (
 
 type UnBondings []UnBonding
 
+// UnBonding is the bond of an operator, or of a delegator, unbonded from the ipal node of OperatorAddress
+// at CreationHeight. It is slashed with the node for the infractions committed before it was unbonded
 type UnBonding struct {
-	AccountAddress sdk.AccAddress `json:"account_address" yaml:"account_address"`
-	Amount         sdk.Coin       `json:"amount" yaml:"amount"`
-	EndTime        time.Time      `json:"end_time" yaml:"end_time"`
+	AccountAddress  sdk.AccAddress `json:"account_address" yaml:"account_address"`
+	Amount          sdk.Coin       `json:"amount" yaml:"amount"`
+	EndTime         time.Time      `json:"end_time" yaml:"end_time"`
+	OperatorAddress sdk.AccAddress `json:"operator_address" yaml:"operator_address"`
+	CreationHeight  int64          `json:"creation_height" yaml:"creation_height"`
 }
 
-func NewUnBonding(aa sdk.AccAddress, amt sdk.Coin, endTime time.Time) UnBonding {
+func NewUnBonding(aa, operator sdk.AccAddress, amt sdk.Coin, creationHeight int64, endTime time.Time) UnBonding {
 	return UnBonding{
-		AccountAddress: aa,
-		Amount:         amt,
-		EndTime:        endTime,
+		AccountAddress:  aa,
+		Amount:          amt,
+		EndTime:         endTime,
+		OperatorAddress: operator,
+		CreationHeight:  creationHeight,
 	}
 }
 
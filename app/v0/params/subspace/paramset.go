@@ -1,8 +1,19 @@
 package subspace
 
+import (
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
 type (
 	ValueValidatorFn func(value interface{}) error
 
+	// UpdateValidator is implemented by the parameter values whose changes depend on
+	// the current value and the chain state. Update checks them on top of the value
+	// validation.
+	UpdateValidator interface {
+		ValidateUpdate(ctx sdk.Context, current interface{}) error
+	}
+
 	// Used for associating paramsubspace key and field of param structs
 	ParamSetPair struct {
 		Key         []byte
@@ -188,6 +188,8 @@ func (s Subspace) Update(ctx sdk.Context, key, value []byte) error {
 	}
 
 	ty := attr.ty
+	current := reflect.New(ty).Interface()
+	s.GetIfExists(ctx, key, current)
 	dest := reflect.New(ty).Interface()
 	s.GetIfExists(ctx, key, dest)
 
@@ -202,6 +204,12 @@ func (s Subspace) Update(ctx sdk.Context, key, value []byte) error {
 		return err
 	}
 
+	if uv, ok := destValue.(UpdateValidator); ok {
+		if err := uv.ValidateUpdate(ctx, reflect.Indirect(reflect.ValueOf(current)).Interface()); err != nil {
+			return fmt.Errorf("invalid parameter change: %s", err)
+		}
+	}
+
 	s.Set(ctx, key, dest)
 	return nil
 }
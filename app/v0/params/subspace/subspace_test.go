@@ -138,6 +138,40 @@ func (suite *SubspaceTestSuite) TestUpdate() {
 	suite.Require().Equal(good, v)
 }
 
+// height is a parameter which can't be changed to a height at or below the current one
+type height uint64
+
+func (h height) ValidateUpdate(ctx sdk.Context, current interface{}) error {
+	if uint64(h) <= uint64(ctx.BlockHeight()) || h == current.(height) {
+		return fmt.Errorf("invalid height %d", h)
+	}
+
+	return nil
+}
+
+func (suite *SubspaceTestSuite) TestUpdateValidator() {
+	keyHeight := []byte("Height")
+	ss := NewSubspace(suite.cdc, key, tkey, "updatevalidator").WithKeyTable(
+		NewKeyTable(NewParamSetPair(keyHeight, height(0), func(interface{}) error { return nil })),
+	)
+	ctx := suite.ctx.WithBlockHeight(10)
+
+	ss.Set(ctx, keyHeight, height(20))
+	for _, h := range []height{10, 20} {
+		bz, err := suite.cdc.MarshalJSON(h)
+		suite.Require().NoError(err)
+		suite.Require().Error(ss.Update(ctx, keyHeight, bz))
+	}
+
+	bz, err := suite.cdc.MarshalJSON(height(11))
+	suite.Require().NoError(err)
+	suite.Require().NoError(ss.Update(ctx, keyHeight, bz))
+
+	var v height
+	ss.Get(ctx, keyHeight, &v)
+	suite.Require().Equal(height(11), v)
+}
+
 func (suite *SubspaceTestSuite) TestGetParamSet() {
 	a := params{
 		UnbondingTime: time.Hour * 48,
@@ -65,6 +65,7 @@ var maccPerms = map[string][]string{
 	staking.NotBondedPoolName: {supply.Burner, supply.Staking},
 	gov.ModuleName:            {supply.Burner},
 	ipal.ModuleName:           {supply.Staking},
+	ipal.RewardPoolName:       nil,
 }
 
 // ProtocolV0 is the struct of the original protocol
@@ -106,10 +107,12 @@ type ProtocolV0 struct {
 	config *cfg.InstrumentationConfig
 
 	invCheckPeriod uint
+
+	logIndex *vm.LogIndex
 }
 
 // NewProtocolV0 creates a new instance of ProtocolV0
-func NewProtocolV0(version uint64, log log.Logger, pk sdk.ProtocolKeeper, deliverTx genutil.DeliverTxfn, invCheckPeriod uint, config *cfg.InstrumentationConfig) *ProtocolV0 {
+func NewProtocolV0(version uint64, log log.Logger, pk sdk.ProtocolKeeper, deliverTx genutil.DeliverTxfn, invCheckPeriod uint, config *cfg.InstrumentationConfig, logIndex *vm.LogIndex) *ProtocolV0 {
 	p0 := ProtocolV0{
 		version:        version,
 		logger:         log,
@@ -119,6 +122,7 @@ func NewProtocolV0(version uint64, log log.Logger, pk sdk.ProtocolKeeper, delive
 		config:         config,
 		deliverTx:      deliverTx,
 		invCheckPeriod: invCheckPeriod,
+		logIndex:       logIndex,
 	}
 
 	return &p0
@@ -237,28 +241,51 @@ func (p *ProtocolV0) configKeepers() {
 		p.cdc, protocol.Keys[slashing.StoreKey], &stakingKeeper, slashingSubspace)
 	p.crisisKeeper = crisis.NewKeeper(crisisSubspace, p.invCheckPeriod, p.supplyKeeper, auth.FeeCollectorName)
 
-	p.cipalKeeper = cipal.NewKeeper(
-		protocol.Keys[cipal.StoreKey],
-		p.cdc,
-		cipalSubspace,
-	)
+	p.guardianKeeper = guardian.NewKeeper(p.cdc, protocol.Keys[protocol.GuardianStoreKey])
 
 	p.ipalKeeper = ipal.NewKeeper(
 		protocol.Keys[ipal.StoreKey],
 		p.cdc,
 		p.supplyKeeper,
+		p.guardianKeeper,
 		ipalSubspace,
+		auth.FeeCollectorName,
+		ModuleAccountAddrs(),
+	)
+
+	p.cipalKeeper = cipal.NewKeeper(
+		protocol.Keys[cipal.StoreKey],
+		p.cdc,
+		cipalSubspace,
+		p.ipalKeeper,
 	)
+	p.ipalKeeper = p.ipalKeeper.WithCIPALKeeper(p.cipalKeeper)
 
 	p.vmKeeper = vm.NewKeeper(
 		p.cdc,
 		protocol.Keys[protocol.VMStoreKey],
+		protocol.TKeys[protocol.VMTStoreKey],
 		vmSubspace,
 		p.accountKeeper,
+		p.cipalKeeper,
+		p.ipalKeeper,
+		&stakingKeeper,
+		p.distrKeeper,
+		p.guardianKeeper,
+	).WithLogIndex(p.logIndex)
+
+	// the ante and fee refund handlers are configured after the keepers, the trace
+	// query of the vm module replays txs with the handlers of the protocol at query time
+	p.vmKeeper.SetTxHandlers(
+		func(ctx sdk.Context, tx sdk.Tx, simulate bool) (sdk.Context, error) {
+			return p.anteHandler(ctx, tx, simulate)
+		},
+		p.router,
+		func(ctx sdk.Context, tx sdk.Tx, result sdk.Result) (sdk.Coin, error) {
+			return p.feeRefundHandler(ctx, tx, result)
+		},
 	)
 
-	p.guardianKeeper = guardian.NewKeeper(p.cdc, protocol.Keys[protocol.GuardianStoreKey])
-
 	p.govKeeper = gov.NewKeeper(
 		p.cdc, protocol.Keys[gov.StoreKey], govSubspace, p.supplyKeeper,
 		&stakingKeeper, p.guardianKeeper, p.protocolKeeper,
@@ -280,7 +307,8 @@ func (p *ProtocolV0) configKeepers() {
 		p.cdc,
 		protocol.Keys[protocol.UpgradeStoreKey],
 		p.protocolKeeper,
-		p.stakingKeeper)
+		p.stakingKeeper,
+		p.vmKeeper)
 }
 
 func (p *ProtocolV0) configModuleManager() {
@@ -305,6 +333,7 @@ func (p *ProtocolV0) configModuleManager() {
 
 	moduleManager.SetOrderBeginBlockers(
 		mint.ModuleName,
+		ipal.ModuleName,
 		distr.ModuleName,
 		slashing.ModuleName)
 
@@ -401,7 +430,7 @@ func (p *ProtocolV0) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.
 }
 
 func (p *ProtocolV0) configFeeHandlers() {
-	p.anteHandler = ante.NewAnteHandler(p.accountKeeper, p.supplyKeeper, ante.DefaultSigVerificationGasConsumer)
+	p.anteHandler = ante.NewAnteHandler(p.accountKeeper, p.supplyKeeper, p.vmKeeper, ante.DefaultSigVerificationGasConsumer)
 	p.feeRefundHandler = auth.NewFeeRefundHandler(p.accountKeeper, p.supplyKeeper, p.refundKeeper)
 }
 
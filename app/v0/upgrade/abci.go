@@ -12,6 +12,8 @@ import (
 func EndBlocker(ctx sdk.Context, keeper Keeper) {
 	ctx = ctx.WithLogger(ctx.Logger().With("handler", "EndBlock").With("module", "nch/upgrade"))
 
+	activateEVMFork(ctx, keeper)
+
 	upgradeConfig, ok := keeper.protocolKeeper.GetUpgradeConfig(ctx)
 
 	if ok {
@@ -65,3 +67,26 @@ func EndBlocker(ctx sdk.Context, keeper Keeper) {
 		ctx.Logger().Debug("----upgrade: no upgradeinfo")
 	}
 }
+
+// activateEVMFork switches the vm to the fork its params schedule at the current height,
+// the fork applies from the next block on, like a software upgrade
+func activateEVMFork(ctx sdk.Context, keeper Keeper) {
+	if keeper.vmKeeper == nil {
+		return
+	}
+
+	curHeight := uint64(ctx.BlockHeight())
+	fork := keeper.vmKeeper.GetEVMForks(ctx).At(curHeight)
+	if fork == keeper.vmKeeper.GetEVMFork(ctx) {
+		return
+	}
+
+	keeper.vmKeeper.SetEVMFork(ctx, fork)
+	ctx.Logger().Info("EVM fork is activated", "fork", fork, "height", curHeight)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(
+		upgtypes.EventTypeEVMFork,
+		sdk.NewAttribute(upgtypes.AttributeKeyFork, fork),
+		sdk.NewAttribute(upgtypes.AttributeKeyHeight, strconv.FormatUint(curHeight, 10)),
+	))
+}
@@ -8,6 +8,8 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/netcloth/netcloth-chain/app/v0/staking"
+	upgtypes "github.com/netcloth/netcloth-chain/app/v0/upgrade/types"
+	vmtypes "github.com/netcloth/netcloth-chain/app/v0/vm/types"
 	sdk "github.com/netcloth/netcloth-chain/types"
 )
 
@@ -98,3 +100,34 @@ func TestEndBlockerTallySuccess(t *testing.T) {
 	// log "Software Upgrade is successful"
 	require.Equal(t, uint64(1), keeper.GetCurrentVersion(ctx))
 }
+
+type mockVMKeeper struct {
+	forks vmtypes.EVMForks
+	fork  string
+}
+
+func (k *mockVMKeeper) GetEVMForks(sdk.Context) vmtypes.EVMForks { return k.forks }
+func (k *mockVMKeeper) GetEVMFork(sdk.Context) string            { return k.fork }
+func (k *mockVMKeeper) SetEVMFork(_ sdk.Context, name string)    { k.fork = name }
+
+func TestEndBlockerActivateEVMFork(t *testing.T) {
+	ctx, keeper, _, _ := CreateTestInput(t, 1000)
+	vmKeeper := &mockVMKeeper{
+		forks: vmtypes.EVMForks{{Name: vmtypes.ForkIstanbul, Height: 0}, {Name: vmtypes.ForkBerlin, Height: 10}},
+		fork:  vmtypes.ForkIstanbul,
+	}
+	keeper.vmKeeper = vmKeeper
+
+	EndBlocker(ctx.WithBlockHeight(9), keeper)
+	require.Equal(t, vmtypes.ForkIstanbul, vmKeeper.fork)
+
+	ctx = ctx.WithBlockHeight(10).WithEventManager(sdk.NewEventManager())
+	EndBlocker(ctx, keeper)
+	require.Equal(t, vmtypes.ForkBerlin, vmKeeper.fork)
+	require.Len(t, ctx.EventManager().Events(), 1)
+	require.Equal(t, upgtypes.EventTypeEVMFork, ctx.EventManager().Events()[0].Type)
+
+	ctx = ctx.WithBlockHeight(11).WithEventManager(sdk.NewEventManager())
+	EndBlocker(ctx, keeper)
+	require.Empty(t, ctx.EventManager().Events())
+}
@@ -15,15 +15,17 @@ type Keeper struct {
 	cdc            *codec.Codec
 	protocolKeeper sdk.ProtocolKeeper
 	sk             staking.Keeper
+	vmKeeper       types.VMKeeper
 }
 
 // NewKeeper creates a new upgrade keeper
-func NewKeeper(cdc *codec.Codec, key sdk.StoreKey, protocolKeeper sdk.ProtocolKeeper, sk staking.Keeper) Keeper {
+func NewKeeper(cdc *codec.Codec, key sdk.StoreKey, protocolKeeper sdk.ProtocolKeeper, sk staking.Keeper, vmKeeper types.VMKeeper) Keeper {
 	keeper := Keeper{
 		key,
 		cdc,
 		protocolKeeper,
 		sk,
+		vmKeeper,
 	}
 	return keeper
 }
@@ -139,6 +139,7 @@ func CreateTestInput(t *testing.T, initPower int64) (ctx sdk.Context, keeper Kee
 		keys[types.StoreKey],
 		protocolKeeper,
 		stakingKeeper,
+		nil,
 	)
 
 	supplyKeeper.SetModuleAccount(ctx, feeCollectorAcc)
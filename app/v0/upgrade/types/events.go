@@ -0,0 +1,9 @@
+package types
+
+// upgrade module event types
+const (
+	EventTypeEVMFork = "evm_fork"
+
+	AttributeKeyFork   = "fork"
+	AttributeKeyHeight = "height"
+)
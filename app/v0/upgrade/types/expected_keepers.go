@@ -0,0 +1,13 @@
+package types
+
+import (
+	vmtypes "github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// VMKeeper defines the expected vm keeper the EVM forks are activated with
+type VMKeeper interface {
+	GetEVMForks(ctx sdk.Context) vmtypes.EVMForks
+	GetEVMFork(ctx sdk.Context) string
+	SetEVMFork(ctx sdk.Context, name string)
+}
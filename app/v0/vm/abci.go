@@ -7,6 +7,17 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
+// BeginBlocker records the hash of the previous block in the window of block
+// hashes reachable by the BLOCKHASH opcode
+func BeginBlocker(ctx sdk.Context, keeper keeper.Keeper) {
+	height := ctx.BlockHeight() - 1
+	if height <= 0 {
+		return
+	}
+
+	keeper.SetBlockHash(ctx, height, sdk.BytesToHash(ctx.BlockHeader().LastBlockId.Hash))
+}
+
 func EndBlocker(ctx sdk.Context, keeper keeper.Keeper) []abci.ValidatorUpdate {
 	// Gas costs are handled within msg handler so costs should be ignored
 	ctx = ctx.WithBlockGasMeter(sdk.NewInfiniteGasMeter())
@@ -23,5 +34,8 @@ func EndBlocker(ctx sdk.Context, keeper keeper.Keeper) []abci.ValidatorUpdate {
 	// Clear accounts cache after account data has been committed
 	keeper.StateDB.ClearStateObjects()
 
+	// Index the logs of the block, the node-local log index prunes the blocks out of its retention window
+	keeper.IndexBlockLogs(ctx, uint64(ctx.BlockHeight()))
+
 	return []abci.ValidatorUpdate{}
 }
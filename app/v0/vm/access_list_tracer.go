@@ -0,0 +1,148 @@
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// AccessListTracer is a Tracer recording the accounts and storage slots accessed
+// during an execution, to build the EIP-2930 access list of a MsgContract. The
+// sender, the destination and the precompiled contracts are warm anyway, they are
+// left out of the list.
+type AccessListTracer struct {
+	excl map[string]bool
+
+	list  types.AccessList
+	index map[string]int
+	slots []map[sdk.Hash]bool
+}
+
+// NewAccessListTracer returns a new access list tracer, starting from the entries of list
+func NewAccessListTracer(list types.AccessList, from, to sdk.AccAddress, precompiles []sdk.AccAddress) *AccessListTracer {
+	t := &AccessListTracer{
+		excl:  map[string]bool{from.String(): true, to.String(): true},
+		index: make(map[string]int),
+	}
+	for _, addr := range precompiles {
+		t.excl[addr.String()] = true
+	}
+
+	for _, tuple := range list {
+		if !t.excl[tuple.Address.String()] {
+			t.addAddress(tuple.Address)
+		}
+		for _, key := range tuple.StorageKeys {
+			t.addSlot(tuple.Address, key)
+		}
+	}
+
+	return t
+}
+
+func (t *AccessListTracer) addAddress(addr sdk.AccAddress) {
+	if _, ok := t.index[addr.String()]; ok {
+		return
+	}
+
+	t.index[addr.String()] = len(t.list)
+	t.list = append(t.list, types.AccessTuple{Address: addr, StorageKeys: []sdk.Hash{}})
+	t.slots = append(t.slots, make(map[sdk.Hash]bool))
+}
+
+func (t *AccessListTracer) addSlot(addr sdk.AccAddress, slot sdk.Hash) {
+	t.addAddress(addr)
+
+	i := t.index[addr.String()]
+	if t.slots[i][slot] {
+		return
+	}
+	t.slots[i][slot] = true
+	t.list[i].StorageKeys = append(t.list[i].StorageKeys, slot)
+}
+
+// CaptureStart implements the Tracer interface
+func (t *AccessListTracer) CaptureStart(from sdk.AccAddress, to sdk.AccAddress, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureState implements the Tracer interface to record the accounts and the
+// storage slots accessed by the operation
+func (t *AccessListTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth uint64, err error) error {
+	switch op {
+	case SLOAD, SSTORE:
+		if stack.len() >= 1 {
+			t.addSlot(contract.Address(), sdk.BigToHash(stack.peek()))
+		}
+	case EXTCODECOPY, EXTCODEHASH, EXTCODESIZE, BALANCE, SELFDESTRUCT:
+		if stack.len() >= 1 {
+			if addr := sdk.BigToAddress(stack.peek()); !t.excl[addr.String()] {
+				t.addAddress(addr)
+			}
+		}
+	case DELEGATECALL, CALL, STATICCALL, CALLCODE:
+		if stack.len() >= 5 {
+			if addr := sdk.BigToAddress(stack.Back(1)); !t.excl[addr.String()] {
+				t.addAddress(addr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CaptureFault implements the Tracer interface
+func (t *AccessListTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth uint64, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface
+func (t *AccessListTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	return nil
+}
+
+// CaptureEnter implements the Tracer interface
+func (t *AccessListTracer) CaptureEnter(typ OpCode, from sdk.AccAddress, to sdk.AccAddress, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureExit implements the Tracer interface
+func (t *AccessListTracer) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	return nil
+}
+
+// AccessList returns the access list recorded so far
+func (t *AccessListTracer) AccessList() types.AccessList {
+	list := make(types.AccessList, len(t.list))
+	for i, tuple := range t.list {
+		list[i] = types.AccessTuple{
+			Address:     tuple.Address,
+			StorageKeys: append([]sdk.Hash{}, tuple.StorageKeys...),
+		}
+	}
+
+	return list
+}
+
+// Equal returns whether both tracers recorded the same accounts and storage slots
+func (t *AccessListTracer) Equal(other *AccessListTracer) bool {
+	if len(t.list) != len(other.list) {
+		return false
+	}
+
+	for addr, i := range t.index {
+		j, ok := other.index[addr]
+		if !ok || len(t.slots[i]) != len(other.slots[j]) {
+			return false
+		}
+		for slot := range t.slots[i] {
+			if !other.slots[j][slot] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
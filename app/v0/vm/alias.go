@@ -20,6 +20,7 @@ type (
 	MsgContract   = types.MsgContract
 	CommitStateDB = types.CommitStateDB
 	Log           = types.Log
+	LogIndex      = types.LogIndex
 
 	GenesisState = types.GenesisState
 )
@@ -28,12 +29,17 @@ var (
 	// functions aliases
 	NewKeeper        = keeper.NewKeeper
 	NewCommitStateDB = types.NewCommitStateDB
+	NewLogIndex      = types.NewLogIndex
 
 	CreateAddress  = common.CreateAddress
 	CreateAddress2 = common.CreateAddress2
 
 	ValidateGenesis = types.ValidateGenesis
 
+	NewEthereumTx    = types.NewEthereumTx
+	DecodeEthereumTx = types.DecodeEthereumTx
+	TxDecoder        = types.TxDecoder
+
 	ErrOutOfGas                 = types.ErrOutOfGas
 	ErrCodeStoreOutOfGas        = types.ErrCodeStoreOutOfGas
 	ErrDepth                    = types.ErrDepth
@@ -51,7 +57,13 @@ var (
 	ErrGasUintOverflow          = types.ErrGasUintOverflow
 	ErrNoPayload                = types.ErrNoPayload
 	ErrWrongCtx                 = types.ErrWrongCtx
+	ErrInvalidCode              = types.ErrInvalidCode
+	ErrAccessListNotSupported   = types.ErrAccessListNotSupported
 
 	// variable aliases
 	ModuleCdc = types.ModuleCdc
 )
+
+type (
+	EthereumTx = types.EthereumTx
+)
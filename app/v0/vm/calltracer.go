@@ -0,0 +1,100 @@
+package vm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// CallTracer is a Tracer recording the tree of the calls and creations made
+// during an execution, including internal value transfers between contracts.
+type CallTracer struct {
+	callstack []types.CallFrame
+}
+
+// NewCallTracer returns a new call tracer
+func NewCallTracer() *CallTracer {
+	return &CallTracer{
+		callstack: make([]types.CallFrame, 1),
+	}
+}
+
+func newCallFrame(typ OpCode, from sdk.AccAddress, to sdk.AccAddress, input []byte, gas uint64, value *big.Int) types.CallFrame {
+	frame := types.CallFrame{
+		Type:  typ.String(),
+		From:  from,
+		To:    to,
+		Value: sdk.ZeroInt(),
+		Gas:   gas,
+		Input: append([]byte{}, input...),
+	}
+	if value != nil {
+		frame.Value = sdk.NewIntFromBigInt(value)
+	}
+
+	return frame
+}
+
+// CaptureStart implements the Tracer interface to initialize the root frame
+func (t *CallTracer) CaptureStart(from sdk.AccAddress, to sdk.AccAddress, create bool, input []byte, gas uint64, value *big.Int) error {
+	typ := CALL
+	if create {
+		typ = CREATE
+	}
+	t.callstack[0] = newCallFrame(typ, from, to, input, gas, value)
+	return nil
+}
+
+// CaptureState implements the Tracer interface, opcodes are not recorded
+func (t *CallTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth uint64, err error) error {
+	return nil
+}
+
+// CaptureFault implements the Tracer interface, opcodes are not recorded
+func (t *CallTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth uint64, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface to finalize the root frame
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	t.callstack[0].GasUsed = gasUsed
+	t.callstack[0].Output = append([]byte{}, output...)
+	if err != nil {
+		t.callstack[0].Error = err.Error()
+	}
+	return nil
+}
+
+// CaptureEnter implements the Tracer interface to open the frame of an internal call
+func (t *CallTracer) CaptureEnter(typ OpCode, from sdk.AccAddress, to sdk.AccAddress, input []byte, gas uint64, value *big.Int) error {
+	t.callstack = append(t.callstack, newCallFrame(typ, from, to, input, gas, value))
+	return nil
+}
+
+// CaptureExit implements the Tracer interface to close the frame of an internal call
+// and attach it to its caller
+func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	size := len(t.callstack)
+	if size <= 1 {
+		return nil
+	}
+
+	frame := t.callstack[size-1]
+	t.callstack = t.callstack[:size-1]
+
+	frame.GasUsed = gasUsed
+	frame.Output = append([]byte{}, output...)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+
+	t.callstack[size-2].Calls = append(t.callstack[size-2].Calls, frame)
+	return nil
+}
+
+// Result returns the root frame of the call tree
+func (t *CallTracer) Result() types.CallFrame {
+	return t.callstack[0]
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"gopkg.in/yaml.v2"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/client/utils"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/client/verifier"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	"github.com/netcloth/netcloth-chain/client/context"
+)
+
+// outputCSV is the value of --output printing the decoded logs as CSV
+const outputCSV = "csv"
+
+// decodeLogs decodes the logs with the abi of abiFile, or else with the abi of each
+// contract fetched from the verification service at verifierURL. The logs of the
+// contracts which aren't verified and the ones no event matches are left raw.
+func decodeLogs(logs []*types.Log, abiFile, verifierURL string) ([]utils.DecodedLog, error) {
+	abis := make(map[string]*abi.ABI)
+	if abiFile != "" {
+		abiObj, err := AbiFromFile(abiFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			abis[log.Address.String()] = &abiObj
+		}
+	}
+
+	decoded := make([]utils.DecodedLog, len(logs))
+	for i, log := range logs {
+		abiObj, ok := abis[log.Address.String()]
+		if !ok && verifierURL != "" {
+			if fetched, err := verifier.FetchABI(verifierURL, log.Address); err == nil {
+				abiObj = &fetched
+			}
+			abis[log.Address.String()] = abiObj
+		}
+
+		if abiObj == nil {
+			decoded[i] = utils.DecodedLog{Address: log.Address, TxHash: log.TxHash, BlockNumber: log.BlockNumber, Index: log.Index, Topics: log.Topics, Data: log.Data}
+			continue
+		}
+		decoded[i], _ = utils.DecodeLog(*abiObj, *log)
+	}
+
+	return decoded, nil
+}
+
+// printDecodedLogs prints the decoded logs in the output format, the CSV output
+// has a line per argument, or per log for the raw logs
+func printDecodedLogs(cliCtx context.CLIContext, logs []utils.DecodedLog) error {
+	switch cliCtx.OutputFormat {
+	case outputCSV:
+		w := csv.NewWriter(os.Stdout)
+		if err := w.Write([]string{"tx_hash", "log_index", "address", "event", "name", "type", "indexed", "value"}); err != nil {
+			return err
+		}
+
+		for _, log := range logs {
+			prefix := []string{log.TxHash.Hex(), strconv.FormatUint(log.Index, 10), log.Address.String(), log.Event}
+			if log.Event == "" {
+				topics, _ := json.Marshal(log.Topics)
+				if err := w.Write(append(prefix, "topics", "", "", string(topics))); err != nil {
+					return err
+				}
+				if err := w.Write(append(prefix, "data", "", "", log.Data.String())); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for _, arg := range log.Args {
+				value, ok := arg.Value.(string)
+				if !ok {
+					bz, err := json.Marshal(arg.Value)
+					if err != nil {
+						return err
+					}
+					value = string(bz)
+				}
+				if err := w.Write(append(prefix, arg.Name, arg.Type, strconv.FormatBool(arg.Indexed), value)); err != nil {
+					return err
+				}
+			}
+		}
+
+		w.Flush()
+		return w.Error()
+
+	case "json":
+		var (
+			bz  []byte
+			err error
+		)
+		if cliCtx.Indent {
+			bz, err = json.MarshalIndent(logs, "", "  ")
+		} else {
+			bz, err = json.Marshal(logs)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bz))
+
+	default:
+		bz, err := yaml.Marshal(logs)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bz))
+	}
+
+	return nil
+}
@@ -9,4 +9,18 @@ const (
 	flagAbiFile      = "abi_file"
 	flagShowCode     = "show_code"
 	flagAll          = "all"
+
+	flagDisableMemory  = "disable_memory"
+	flagDisableStack   = "disable_stack"
+	flagDisableStorage = "disable_storage"
+	flagLimit          = "limit"
+	flagTracer         = "tracer"
+	flagOverrides      = "overrides"
+	flagAccessList     = "access_list"
+	flagVerifier       = "verifier"
+
+	flagFromBlock = "from_block"
+	flagToBlock   = "to_block"
+	flagAddress   = "address"
+	flagTopics    = "topics"
 )
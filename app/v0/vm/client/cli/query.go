@@ -6,13 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
-	"github.com/ethereum/go-ethereum/common"
-
+	"github.com/netcloth/netcloth-chain/app/v0/vm/client/utils"
 	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
 	"github.com/netcloth/netcloth-chain/client"
 	"github.com/netcloth/netcloth-chain/client/context"
@@ -39,9 +39,13 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		GetCmdQueryCode(cdc),
 		GetCmdGetStorage(cdc),
 		GetCmdGetLogs(cdc),
+		GetCmdFilterLogs(cdc),
+		GetCmdQueryBloom(cdc),
 		GetCmdQueryCreateFee(cdc),
 		GetCmdQueryCallFee(cdc),
 		GetCmdQueryCall(cdc),
+		GetCmdQueryCreateAccessList(cdc),
+		GetCmdQueryTrace(cdc),
 	)...)
 	return vmQueryCmd
 }
@@ -190,12 +194,17 @@ $ %s query vm code [address]`, version.ClientName)),
 }
 
 func GetCmdGetLogs(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "logs [txhash]",
 		Short: "Querying logs by txHash",
 		Long: strings.TrimSpace(fmt.Sprintf(`Query logs by txHash.
+With --abi_file or --verifier the logs are decoded into their event and its named arguments, with the given abi
+or the one of each verified contract, the logs no event matches are printed raw. The decoded logs are printed
+as text, JSON or, with --output=csv, as CSV with one line per argument.
 Example:
-$ %s query vm logs [txHash]`, version.ClientName)),
+$ %s query vm logs [txHash]
+$ %s query vm logs [txHash] --abi_file=./demo.abi --output=json
+$ %s query vm logs [txHash] --verifier=http://localhost:1317 --output=csv`, version.ClientName, version.ClientName, version.ClientName)),
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
@@ -208,6 +217,93 @@ $ %s query vm logs [txHash]`, version.ClientName)),
 
 			var out types.QueryLogsResult
 			cdc.MustUnmarshalJSON(res, &out)
+
+			abiFile, verifierURL := viper.GetString(flagAbiFile), viper.GetString(flagVerifier)
+			if abiFile == "" && verifierURL == "" && cliCtx.OutputFormat != outputCSV {
+				return cliCtx.PrintOutput(out)
+			}
+
+			logs, err := decodeLogs(out.Logs, abiFile, verifierURL)
+			if err != nil {
+				return err
+			}
+
+			return printDecodedLogs(cliCtx, logs)
+		},
+	}
+
+	cmd.Flags().String(flagAbiFile, "", "abi file of the contracts to decode the logs with")
+	cmd.Flags().String(flagVerifier, "", "rest server url of the verification service to fetch the abi of the verified contracts from, when --abi_file is not set (e.g. http://localhost:1317)")
+
+	return cmd
+}
+
+func GetCmdFilterLogs(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "filter-logs",
+		Short: "Querying logs by contract address, topics and block range",
+		Long: strings.TrimSpace(fmt.Sprintf(`Query the logs emitted by any of the contracts given with --address, within the block range.
+Each --topics flag matches one topic position with a comma separated list of topics, an empty value matches any topic.
+The logs are served by nodes enabling the log index in the [vm] section of their app.toml, which keep the logs of
+the last log-index-retention blocks. A query spans at most 10000 blocks, --to_block defaulting to the latest block.
+Example:
+$ %s query vm filter-logs --address=nch1rk47h83x4nz4745d63dtnpl8uwsramfgz8snr5 --from_block=100 --to_block=200 \
+  --topics=0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef --topics= \
+  --topics=0x0000000000000000000000005376329591cde25497d29de88ec553229ad10a61`, version.ClientName)),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			topics, err := cmd.Flags().GetStringArray(flagTopics)
+			if err != nil {
+				return err
+			}
+
+			filter, err := utils.ParseLogFilter(viper.GetUint64(flagFromBlock), viper.GetUint64(flagToBlock), viper.GetStringSlice(flagAddress), topics)
+			if err != nil {
+				return err
+			}
+
+			out, _, err := utils.QueryFilterLogs(cliCtx, filter)
+			if err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(out)
+		},
+	}
+
+	cmd.Flags().Uint64(flagFromBlock, 0, "First block of the range")
+	cmd.Flags().Uint64(flagToBlock, 0, "Last block of the range, 0 means the latest block")
+	cmd.Flags().StringSlice(flagAddress, nil, "Contract addresses emitting the logs, comma separated")
+	cmd.Flags().StringArray(flagTopics, nil, fmt.Sprintf("Topics of a position, comma separated, repeat the flag for up to %d positions", types.MaxLogTopics))
+
+	return cmd
+}
+
+func GetCmdQueryBloom(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "bloom [height]",
+		Short: "Querying the logs bloom of a block",
+		Long: strings.TrimSpace(fmt.Sprintf(`Query the 2048-bit bloom filter of the contract addresses and topics of the logs emitted in a block.
+A block whose bloom doesn't contain an address or a topic has no log matching it.
+Example:
+$ %s query vm bloom 100`, version.ClientName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			if _, err := strconv.ParseUint(args[0], 10, 64); err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.Query(fmt.Sprintf("custom/vm/%s/%s", types.QueryBloom, args[0]))
+			if err != nil {
+				return err
+			}
+
+			var out types.QueryBloomResult
+			cdc.MustUnmarshalJSON(res, &out)
 			return cliCtx.PrintOutput(out)
 		},
 	}
@@ -324,7 +420,8 @@ func GetCmdQueryCall(cdc *codec.Codec) *cobra.Command {
 		Short: "Querying fee to call contract",
 		Long: strings.TrimSpace(fmt.Sprintf(`call contract for query, don't create a transaction.
 Example:
-$ %s query vm call nch1mfztsv6eq5rhtaz2l6jjp3yup3q80agsqra9qe nch1rk47h83x4nz4745d63dtnpl8uwsramfgz8snr5 balanceOf ./demo.abi --amount=0pnch --args="arg1 arg2"`, version.ClientName)),
+$ %s query vm call nch1mfztsv6eq5rhtaz2l6jjp3yup3q80agsqra9qe nch1rk47h83x4nz4745d63dtnpl8uwsramfgz8snr5 balanceOf ./demo.abi --amount=0pnch --args="arg1 arg2"
+$ %s query vm call nch1mfztsv6eq5rhtaz2l6jjp3yup3q80agsqra9qe nch1rk47h83x4nz4745d63dtnpl8uwsramfgz8snr5 balanceOf ./demo.abi --args="arg1" --overrides=./overrides.json`, version.ClientName, version.ClientName)),
 		Args: cobra.ExactArgs(4),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
@@ -346,7 +443,24 @@ $ %s query vm call nch1mfztsv6eq5rhtaz2l6jjp3yup3q80agsqra9qe nch1rk47h83x4nz474
 			}
 
 			msg := types.NewMsgContractQuery(fromAddr, toAddr, payload, ZeroAmount)
-			data, err := cliCtx.Codec.MarshalJSON(msg)
+			if accessListFile := viper.GetString(flagAccessList); accessListFile != "" {
+				if msg.AccessList, err = AccessListFromFile(cdc, accessListFile); err != nil {
+					return err
+				}
+			}
+
+			var overrides types.StateOverrides
+			if overridesFile := viper.GetString(flagOverrides); overridesFile != "" {
+				if overrides, err = OverridesFromFile(cdc, overridesFile); err != nil {
+					return err
+				}
+			}
+
+			if tracer := viper.GetString(flagTracer); tracer != "" {
+				return traceCall(cliCtx, types.QueryTraceCallParams{Msg: msg, Config: types.TraceConfig{Tracer: tracer}, Overrides: overrides})
+			}
+
+			data, err := cliCtx.Codec.MarshalJSON(types.QuerySimulateParams{Msg: msg, Overrides: overrides})
 			if err != nil {
 				return err
 			}
@@ -358,27 +472,23 @@ $ %s query vm call nch1mfztsv6eq5rhtaz2l6jjp3yup3q80agsqra9qe nch1rk47h83x4nz474
 
 			var out types.SimulationResult
 			cdc.MustUnmarshalJSON(res, &out)
+			if out.Err != nil {
+				return out.Err
+			}
 
 			d, err := hexutil.Decode(out.Res)
 			if err != nil {
 				return cliCtx.PrintOutput(out)
 			}
 
-			var result types.VMQueryResult
-			result.Gas = out.Gas
-			result.Result, err = m.Outputs.UnpackValues(d)
+			outputs, err := utils.DecodeOutputs(m, d)
 			if err != nil {
 				return err
 			}
 
-			for i := 0; i < len(m.Outputs); i++ {
-				if m.Outputs[i].Type.String() == "address" {
-					var addr sdk.AccAddress
-					if ethAddr, ok := result.Result[i].(common.Address); ok {
-						addr = append(addr[:], ethAddr.Bytes()...)
-						result.Result[i] = addr.String()
-					}
-				}
+			result := types.VMQueryResult{Gas: out.Gas, Result: make([]interface{}, len(outputs))}
+			for i, output := range outputs {
+				result.Result[i] = output
 			}
 
 			fmt.Println(result)
@@ -390,6 +500,137 @@ $ %s query vm call nch1mfztsv6eq5rhtaz2l6jjp3yup3q80agsqra9qe nch1rk47h83x4nz474
 
 	cmd.Flags().String(flagArgs, "", "contract method arg list (e.g. --args='arg1 arg2 arg3')(default \"\")")
 	cmd.Flags().String(flagAmount, "0pnch", "amount of coins to send (e.g. --amount=100pnch)")
+	cmd.Flags().String(flagTracer, "", fmt.Sprintf("trace the call with the given tracer instead of decoding its result (%s|%s)", types.StructLoggerTracer, types.CallTracer))
+	cmd.Flags().String(flagOverrides, "", "JSON file of the balances, nonces, codes and storage slots of accounts to override during the call")
+	cmd.Flags().String(flagAccessList, "", "JSON file of the access list of the call, requires the berlin fork")
+
+	return cmd
+}
+
+func GetCmdQueryCreateAccessList(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-access-list [from] [to] [method] [abi_file]",
+		Short: "Build the access list of a contract call",
+		Long: strings.TrimSpace(fmt.Sprintf(`Simulate a contract call and print the accounts and storage slots it accesses, as the access list
+to send with the call, and the gas the call uses with it. The list given with --access_list is extended.
+Example:
+$ %s query vm create-access-list nch1mfztsv6eq5rhtaz2l6jjp3yup3q80agsqra9qe nch1rk47h83x4nz4745d63dtnpl8uwsramfgz8snr5 transfer ./demo.abi --args="arg1 arg2"`, version.ClientName)),
+		Args: cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			fromAddr, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			toAddr, err := sdk.AccAddressFromBech32(args[1])
+			if err != nil {
+				return err
+			}
+
+			argList := viper.GetStringSlice(flagArgs)
+			payload, _, err := GenPayload(args[3], args[2], argList)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgContractQuery(fromAddr, toAddr, payload, ZeroAmount)
+			if accessListFile := viper.GetString(flagAccessList); accessListFile != "" {
+				if msg.AccessList, err = AccessListFromFile(cdc, accessListFile); err != nil {
+					return err
+				}
+			}
+
+			var overrides types.StateOverrides
+			if overridesFile := viper.GetString(flagOverrides); overridesFile != "" {
+				if overrides, err = OverridesFromFile(cdc, overridesFile); err != nil {
+					return err
+				}
+			}
+
+			data, err := cliCtx.Codec.MarshalJSON(types.QuerySimulateParams{Msg: msg, Overrides: overrides})
+			if err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/vm/%s", types.QueryCreateAccessList), data)
+			if err != nil {
+				return err
+			}
+
+			var out types.AccessListResult
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+
+	cmd.Flags().String(flagArgs, "", "contract method arg list (e.g. --args='arg1 arg2 arg3')(default \"\")")
+	cmd.Flags().String(flagOverrides, "", "JSON file of the balances, nonces, codes and storage slots of accounts to override during the call")
+	cmd.Flags().String(flagAccessList, "", "JSON file of the access list to start from")
+
+	return cmd
+}
+
+func traceCall(cliCtx context.CLIContext, params types.QueryTraceCallParams) error {
+	if err := params.Config.Validate(); err != nil {
+		return err
+	}
+
+	data, err := cliCtx.Codec.MarshalJSON(params)
+	if err != nil {
+		return err
+	}
+
+	res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/vm/%s", types.QueryTraceCall), data)
+	if err != nil {
+		return err
+	}
+
+	var out types.TraceResult
+	cliCtx.Codec.MustUnmarshalJSON(res, &out)
+	return cliCtx.PrintOutput(out)
+}
+
+func GetCmdQueryTrace(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trace [txhash]",
+		Short: "Trace the vm execution of a committed tx",
+		Long: strings.TrimSpace(fmt.Sprintf(`Replay a committed tx against the state of its parent block and print the traces of its contract executions,
+one per MsgContract, the opcode level logs of the struct logger by default or the tree of internal calls with --tracer=%s.
+Example:
+$ %s query vm trace 78ba938d6caae4d056bed8c246d3c49cfdcdc2ee1b679cfc8b527ae5ac9f3747 --disable_memory --limit=1000
+$ %s query vm trace 78ba938d6caae4d056bed8c246d3c49cfdcdc2ee1b679cfc8b527ae5ac9f3747 --tracer=%s`, types.CallTracer, version.ClientName, version.ClientName, types.CallTracer)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			config := types.TraceConfig{
+				Tracer:         viper.GetString(flagTracer),
+				DisableMemory:  viper.GetBool(flagDisableMemory),
+				DisableStack:   viper.GetBool(flagDisableStack),
+				DisableStorage: viper.GetBool(flagDisableStorage),
+				Limit:          viper.GetInt(flagLimit),
+			}
+
+			if err := config.Validate(); err != nil {
+				return err
+			}
+
+			out, err := utils.QueryTrace(cliCtx, args[0], config)
+			if err != nil {
+				return err
+			}
+
+			return cliCtx.PrintOutput(out)
+		},
+	}
+
+	cmd.Flags().Bool(flagDisableMemory, false, "Disable memory capture")
+	cmd.Flags().Bool(flagDisableStack, false, "Disable stack capture")
+	cmd.Flags().Bool(flagDisableStorage, false, "Disable storage capture")
+	cmd.Flags().Int(flagLimit, 0, "Maximum number of captured opcodes, 0 means unlimited")
+	cmd.Flags().String(flagTracer, types.StructLoggerTracer, fmt.Sprintf("Tracer to use (%s|%s)", types.StructLoggerTracer, types.CallTracer))
 
 	return cmd
 }
@@ -1,14 +1,15 @@
 package cli
 
 import (
-	"encoding/hex"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/netcloth/netcloth-chain/app/v0/auth"
 	"github.com/netcloth/netcloth-chain/app/v0/auth/client/utils"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/client/verifier"
 	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
 	"github.com/netcloth/netcloth-chain/client"
 	"github.com/netcloth/netcloth-chain/client/context"
@@ -68,6 +69,11 @@ func ContractCreateCmd(cdc *codec.Codec) *cobra.Command {
 			}
 
 			msg := types.NewMsgContract(cliCtx.GetFromAddress(), nil, code, coin)
+			if accessListFile := viper.GetString(flagAccessList); accessListFile != "" {
+				if msg.AccessList, err = AccessListFromFile(cdc, accessListFile); err != nil {
+					return err
+				}
+			}
 			if err := msg.ValidateBasic(); err != nil {
 				return err
 			}
@@ -80,6 +86,7 @@ func ContractCreateCmd(cdc *codec.Codec) *cobra.Command {
 	cmd.Flags().String(flagAbiFile, "", "contract abi file path")
 	cmd.Flags().String(flagArgs, "", "contract method arg list (e.g. --args='arg1 arg2 arg3')")
 	cmd.Flags().String(flagAmount, "0pnch", "amount of coins to send (e.g. 100pnch)")
+	cmd.Flags().String(flagAccessList, "", "JSON file of the access list of the tx, requires the berlin fork")
 
 	cmd.MarkFlagRequired(flagCodeFile)
 
@@ -90,9 +97,10 @@ func ContractCreateCmd(cdc *codec.Codec) *cobra.Command {
 
 func ContractCallCmd(cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "call",
-		Short:   "Create and sign a call contract tx",
-		Example: `nchcli vm call --from=<user key name> --contract_addr=<contract_addr> --method=<method> --abi_file=<abi_file>  --args='arg1 arg2 arg3' --amount=<amount> `,
+		Use:   "call",
+		Short: "Create and sign a call contract tx",
+		Example: `nchcli vm call --from=<user key name> --contract_addr=<contract_addr> --method=<method> --abi_file=<abi_file>  --args='arg1 arg2 arg3' --amount=<amount>
+nchcli vm call --from=<user key name> --contract_addr=<contract_addr> --method=<method> --verifier=http://localhost:1317 --args='arg1 arg2 arg3'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
@@ -107,23 +115,36 @@ func ContractCallCmd(cdc *codec.Codec) *cobra.Command {
 				coin = coinInput
 			}
 
-			abiFile := viper.GetString(flagAbiFile)
-			method := viper.GetString(flagMethod)
-			argList := viper.GetStringSlice(flagArgs)
-			payload, _, err := GenPayload(abiFile, method, argList)
+			contractAddr, err := sdk.AccAddressFromBech32(viper.GetString(flagContractAddr))
 			if err != nil {
 				return err
 			}
 
-			dump := make([]byte, len(payload)*2)
-			hex.Encode(dump, payload)
+			var abiObj abi.ABI
+			if abiFile := viper.GetString(flagAbiFile); abiFile != "" {
+				abiObj, err = AbiFromFile(abiFile)
+			} else if verifierURL := viper.GetString(flagVerifier); verifierURL != "" {
+				abiObj, err = verifier.FetchABI(verifierURL, contractAddr)
+			} else {
+				err = fmt.Errorf("must use --%s or --%s to get the contract abi", flagAbiFile, flagVerifier)
+			}
+			if err != nil {
+				return err
+			}
 
-			contractAddr, err := sdk.AccAddressFromBech32(viper.GetString(flagContractAddr))
+			method := viper.GetString(flagMethod)
+			argList := viper.GetStringSlice(flagArgs)
+			payload, _, err := GenPayloadFromAbi(abiObj, method, argList)
 			if err != nil {
 				return err
 			}
 
 			msg := types.NewMsgContract(cliCtx.GetFromAddress(), contractAddr, payload, coin)
+			if accessListFile := viper.GetString(flagAccessList); accessListFile != "" {
+				if msg.AccessList, err = AccessListFromFile(cdc, accessListFile); err != nil {
+					return err
+				}
+			}
 			if err := msg.ValidateBasic(); err != nil {
 				return err
 			}
@@ -137,10 +158,11 @@ func ContractCallCmd(cdc *codec.Codec) *cobra.Command {
 	cmd.Flags().String(flagMethod, "", "contract method")
 	cmd.Flags().String(flagArgs, "", "contract method arg list")
 	cmd.Flags().String(flagAbiFile, "", "contract abi file path")
+	cmd.Flags().String(flagVerifier, "", "rest server url of the verification service to fetch the abi of a verified contract from, when --abi_file is not set (e.g. http://localhost:1317)")
+	cmd.Flags().String(flagAccessList, "", "JSON file of the access list of the tx, requires the berlin fork")
 
 	cmd.MarkFlagRequired(flagContractAddr)
 	cmd.MarkFlagRequired(flagMethod)
-	cmd.MarkFlagRequired(flagAbiFile)
 
 	cmd = client.PostCommands(cmd)[0]
 
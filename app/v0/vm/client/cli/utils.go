@@ -15,6 +15,8 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 
 	"github.com/netcloth/netcloth-chain/app/v0/vm/common/math"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	"github.com/netcloth/netcloth-chain/codec"
 	"github.com/netcloth/netcloth-chain/hexutil"
 	sdk "github.com/netcloth/netcloth-chain/types"
 )
@@ -48,6 +50,36 @@ func CodeFromFile(codeFile string) ([]byte, error) {
 	return code, nil
 }
 
+// OverridesFromFile reads the state overrides of a simulated call from a JSON file, e.g.
+// [{"address":"nch1...","balance":"1000000000000","nonce":"1","code":"0x6080...","storage":[{"key":"0x...","value":"0x..."}]}]
+func OverridesFromFile(cdc *codec.Codec, overridesFile string) (overrides types.StateOverrides, err error) {
+	bz, err := ioutil.ReadFile(overridesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cdc.UnmarshalJSON(bz, &overrides); err != nil {
+		return nil, err
+	}
+
+	return overrides, overrides.Validate()
+}
+
+// AccessListFromFile reads the access list of a contract call or creation from a JSON file, e.g.
+// [{"address":"nch1...","storage_keys":["0x0000000000000000000000000000000000000000000000000000000000000001"]}]
+func AccessListFromFile(cdc *codec.Codec, accessListFile string) (list types.AccessList, err error) {
+	bz, err := ioutil.ReadFile(accessListFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cdc.UnmarshalJSON(bz, &list); err != nil {
+		return nil, err
+	}
+
+	return list, list.Validate()
+}
+
 func AbiFromFile(abiFile string) (abiObj abi.ABI, err error) {
 	abiFile, err = filepath.Abs(abiFile)
 	if err != nil {
@@ -109,12 +141,17 @@ func stringAddr2hexAddr(stringAddr string) [20]byte {
 func GenPayload(abiFile, method string, args []string) (payload []byte, m abi.Method, err error) {
 	//fmt.Fprintf(os.Stderr, fmt.Sprintf("abiFile = %s, method = %s, args = %v, len=%d\n", abiFile, method, args, len(args)))
 
-	emptyMethod := abi.Method{}
 	abiObj, err := AbiFromFile(abiFile)
 	if err != nil {
-		return nil, emptyMethod, err
+		return nil, abi.Method{}, err
 	}
 
+	return GenPayloadFromAbi(abiObj, method, args)
+}
+
+// GenPayloadFromAbi packs the call of method with args, the constructor args if method is empty
+func GenPayloadFromAbi(abiObj abi.ABI, method string, args []string) (payload []byte, m abi.Method, err error) {
+	emptyMethod := abi.Method{}
 	if len(method) == 0 { //constructor
 		m = abiObj.Constructor
 	} else if v, ok := abiObj.Methods[method]; ok {
@@ -0,0 +1,33 @@
+package ethrpc
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// AccAddressToHex converts an account address to its 0x prefixed hex form
+func AccAddressToHex(addr sdk.AccAddress) common.Address {
+	return common.BytesToAddress(addr.Bytes())
+}
+
+// HexToAccAddress converts a 0x prefixed hex address to an account address
+func HexToAccAddress(addr common.Address) sdk.AccAddress {
+	return sdk.AccAddress(addr.Bytes())
+}
+
+// ParseAddress accepts either a 0x prefixed hex address or a bech32 account address
+func ParseAddress(s string) (sdk.AccAddress, error) {
+	if common.IsHexAddress(s) {
+		return HexToAccAddress(common.HexToAddress(s)), nil
+	}
+
+	addr, err := sdk.AccAddressFromBech32(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %s: neither hex nor bech32", s)
+	}
+
+	return addr, nil
+}
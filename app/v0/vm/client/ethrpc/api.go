@@ -0,0 +1,470 @@
+package ethrpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	authtypes "github.com/netcloth/netcloth-chain/app/v0/auth/types"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/client/utils"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	"github.com/netcloth/netcloth-chain/client/context"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// PublicEthAPI implements the eth_* namespace on top of the vm querier and the
+// Tendermint RPC. Balances and values are denominated in pnch.
+type PublicEthAPI struct {
+	cliCtx context.CLIContext
+}
+
+// NewPublicEthAPI creates a new PublicEthAPI
+func NewPublicEthAPI(cliCtx context.CLIContext) *PublicEthAPI {
+	return &PublicEthAPI{cliCtx: cliCtx}
+}
+
+// BlockNumber returns the latest block height
+func (api *PublicEthAPI) BlockNumber() (hexutil.Uint64, error) {
+	node, err := api.cliCtx.GetNode()
+	if err != nil {
+		return 0, err
+	}
+
+	status, err := node.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	return hexutil.Uint64(status.SyncInfo.LatestBlockHeight), nil
+}
+
+// ChainID returns the EVM chain id, the evm_chain_id vm param, used in EIP-155
+// signatures and EIP-712 domains
+func (api *PublicEthAPI) ChainID() (hexutil.Uint64, error) {
+	res, _, err := api.cliCtx.Query(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryParameters))
+	if err != nil {
+		return 0, err
+	}
+
+	var params types.Params
+	if err := api.cliCtx.Codec.UnmarshalJSON(res, &params); err != nil {
+		return 0, err
+	}
+
+	return hexutil.Uint64(params.EVMChainID), nil
+}
+
+// GetBalance returns the pnch balance of an account at the given height
+func (api *PublicEthAPI) GetBalance(address common.Address, blockNum BlockNumber) (*hexutil.Big, error) {
+	height, err := blockNum.queryHeight()
+	if err != nil {
+		return nil, err
+	}
+	cliCtx := api.cliCtx.WithHeight(height)
+
+	acc, err := authtypes.NewAccountRetriever(cliCtx).GetAccount(HexToAccAddress(address))
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return (*hexutil.Big)(big.NewInt(0)), nil
+		}
+		return nil, err
+	}
+
+	balance := acc.GetCoins().AmountOf(sdk.NativeTokenName)
+	return (*hexutil.Big)(balance.BigInt()), nil
+}
+
+// GetCode returns the contract code stored at the given address
+func (api *PublicEthAPI) GetCode(address common.Address, blockNum BlockNumber) (hexutil.Bytes, error) {
+	height, err := blockNum.queryHeight()
+	if err != nil {
+		return nil, err
+	}
+	cliCtx := api.cliCtx.WithHeight(height)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryCode, HexToAccAddress(address))
+	res, _, err := cliCtx.Query(route)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetStorageAt returns the value of a contract storage slot
+func (api *PublicEthAPI) GetStorageAt(address common.Address, key string, blockNum BlockNumber) (hexutil.Bytes, error) {
+	height, err := blockNum.queryHeight()
+	if err != nil {
+		return nil, err
+	}
+	cliCtx := api.cliCtx.WithHeight(height)
+
+	slot, err := hexutil.DecodeBig(key)
+	if err != nil {
+		return nil, invalidParamsError("invalid storage key %s: %s", key, err)
+	}
+
+	route := fmt.Sprintf("custom/%s/%s/%s/%s", types.QuerierRoute, types.QueryStorage,
+		HexToAccAddress(address), hex.EncodeToString(common.BigToHash(slot).Bytes()))
+	res, _, err := cliCtx.Query(route)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.QueryStorageResult
+	if err := cliCtx.Codec.UnmarshalJSON(res, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Value.Bytes(), nil
+}
+
+// Call executes a contract call against the state at the given height, with the
+// optional state overrides applied, without creating a transaction
+func (api *PublicEthAPI) Call(args CallArgs, blockNum BlockNumber, overrides StateOverride) (hexutil.Bytes, error) {
+	out, err := api.simulate(types.QueryCall, args, blockNum, overrides)
+	if err != nil {
+		return nil, err
+	}
+	if out.Err != nil {
+		return nil, executionError(out.Err)
+	}
+
+	return hexutil.Decode("0x" + out.Res)
+}
+
+// EstimateGas returns the gas limit under which an Ethereum tx sending the given call
+// or creation succeeds, ante handler included, with the optional state overrides applied
+func (api *PublicEthAPI) EstimateGas(args CallArgs, blockNum BlockNumber, overrides StateOverride) (hexutil.Uint64, error) {
+	out, err := api.simulate(types.EstimateGas, args, blockNum, overrides)
+	if err != nil {
+		return 0, err
+	}
+
+	return hexutil.Uint64(out.Gas), nil
+}
+
+func (api *PublicEthAPI) simulate(path string, args CallArgs, blockNum BlockNumber, overrides StateOverride) (out types.SimulationResult, err error) {
+	height, err := blockNum.queryHeight()
+	if err != nil {
+		return out, err
+	}
+	cliCtx := api.cliCtx.WithHeight(height)
+
+	from := sdk.AccAddress(make([]byte, sdk.AddrLen))
+	if args.From != nil {
+		from = HexToAccAddress(*args.From)
+	}
+
+	var to sdk.AccAddress
+	if args.To != nil {
+		to = HexToAccAddress(*args.To)
+	}
+
+	amount := sdk.NewCoin(sdk.NativeTokenName, sdk.ZeroInt())
+	if args.Value != nil {
+		amount = sdk.NewCoin(sdk.NativeTokenName, sdk.NewIntFromBigInt(args.Value.ToInt()))
+	}
+
+	// the gas is estimated for an Ethereum tx, as sent by the clients of the eth rpc
+	params := types.QuerySimulateParams{Msg: types.NewMsgContractQuery(from, to, args.payload(), amount), EthereumTx: true}
+	if params.Overrides, err = overrides.toStateOverrides(); err != nil {
+		return out, err
+	}
+	if err = params.Overrides.Validate(); err != nil {
+		return out, invalidParamsError("%s", err)
+	}
+
+	data, err := cliCtx.Codec.MarshalJSON(params)
+	if err != nil {
+		return out, err
+	}
+
+	res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, path), data)
+	if err != nil {
+		return out, err
+	}
+
+	err = cliCtx.Codec.UnmarshalJSON(res, &out)
+	return out, err
+}
+
+// GetLogs returns the contract logs matching the given filter, looked up through
+// the log indexes of the vm module
+func (api *PublicEthAPI) GetLogs(crit FilterCriteria) ([]*RPCLog, error) {
+	if crit.BlockHash != nil {
+		return nil, invalidParamsError("filtering by blockHash is not supported, use fromBlock/toBlock")
+	}
+
+	latest, err := api.BlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := crit.blockRange(int64(latest))
+	if from > to {
+		return []*RPCLog{}, nil
+	}
+
+	filter := types.NewLogFilter(uint64(from), uint64(to), nil, nil)
+	for _, addr := range crit.Addresses {
+		filter.Addresses = append(filter.Addresses, sdk.AccAddress(addr.Bytes()))
+	}
+	for _, sub := range crit.Topics {
+		topics := make([]sdk.Hash, len(sub))
+		for i, topic := range sub {
+			topics[i] = sdk.BytesToHash(topic.Bytes())
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, invalidParamsError("%s", err)
+	}
+
+	out, _, err := utils.QueryFilterLogs(api.cliCtx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := api.cliCtx.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make(map[uint64]*ctypes.ResultBlock)
+	// position of the logs within their tx, by log index
+	positions := make(map[uint64]int)
+
+	logs := make([]*RPCLog, 0, len(out.Logs))
+	for _, log := range out.Logs {
+		block, ok := blocks[log.BlockNumber]
+		if !ok {
+			height := int64(log.BlockNumber)
+			block, err = node.Block(&height)
+			if err != nil {
+				return nil, err
+			}
+			blocks[log.BlockNumber] = block
+		}
+
+		if _, ok := positions[log.Index]; !ok {
+			txLogs, err := api.queryTxLogs(log.TxHash.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			for i, l := range txLogs {
+				positions[l.Index] = i
+			}
+		}
+
+		rpcLog := newRPCLog(log, positions[log.Index])
+		rpcLog.BlockHash = common.BytesToHash(block.BlockMeta.BlockID.Hash)
+		rpcLog.BlockNumber = hexutil.Uint64(block.Block.Height)
+		for i, tx := range block.Block.Data.Txs {
+			if bytes.Equal(tmhash.Sum(tx), log.TxHash.Bytes()) {
+				rpcLog.TransactionHash = txHash(tx)
+				rpcLog.TransactionIndex = hexutil.Uint(i)
+				break
+			}
+		}
+		logs = append(logs, rpcLog)
+	}
+
+	return logs, nil
+}
+
+// queryTxLogs queries the logs of a committed tx
+func (api *PublicEthAPI) queryTxLogs(txHash []byte) ([]*types.Log, error) {
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryTxLogs, hex.EncodeToString(txHash))
+	res, _, err := api.cliCtx.Query(route)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.QueryLogsResult
+	if err := api.cliCtx.Codec.UnmarshalJSON(res, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Logs, nil
+}
+
+// txLogs queries the logs of a committed tx by its Tendermint hash and fills in the
+// block data and the hash of the tx of the logs
+func (api *PublicEthAPI) txLogs(tmHash []byte, txHash common.Hash, block *ctypes.ResultBlock, txIndex int) ([]*RPCLog, error) {
+	txLogs, err := api.queryTxLogs(tmHash)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]*RPCLog, 0, len(txLogs))
+	for i, log := range txLogs {
+		rpcLog := newRPCLog(log, i)
+		rpcLog.BlockHash = common.BytesToHash(block.BlockMeta.BlockID.Hash)
+		rpcLog.BlockNumber = hexutil.Uint64(block.Block.Height)
+		rpcLog.TransactionHash = txHash
+		rpcLog.TransactionIndex = hexutil.Uint(txIndex)
+		logs = append(logs, rpcLog)
+	}
+
+	return logs, nil
+}
+
+// logsBloom returns the bloom filter of the addresses and topics of the logs
+func logsBloom(logs []*RPCLog) hexutil.Bytes {
+	var bloom types.Bloom
+	for _, log := range logs {
+		bloom.Add(log.Address.Bytes())
+		for _, topic := range log.Topics {
+			bloom.Add(topic.Bytes())
+		}
+	}
+
+	return bloom.Bytes()
+}
+
+// getTx looks a committed tx up by its Ethereum hash, or by its Tendermint hash for
+// the txs which aren't Ethereum txs, and returns nil if the tx is unknown
+func (api *PublicEthAPI) getTx(hash common.Hash) (*ctypes.ResultTx, error) {
+	node, err := api.cliCtx.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("%s.%s='%s'", types.EventTypeEthereumTx, types.AttributeKeyHash, hex.EncodeToString(hash.Bytes()))
+	res, err := node.TxSearch(query, false, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Txs) > 0 {
+		return res.Txs[0], nil
+	}
+
+	resTx, err := node.Tx(hash.Bytes(), false)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return resTx, nil
+}
+
+// GetTransactionReceipt returns the receipt of a committed tx, or nil if the tx is unknown
+func (api *PublicEthAPI) GetTransactionReceipt(hash common.Hash) (*Receipt, error) {
+	node, err := api.cliCtx.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	resTx, err := api.getTx(hash)
+	if err != nil || resTx == nil {
+		return nil, err
+	}
+
+	block, err := node.Block(&resTx.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockResults, err := node.BlockResults(&resTx.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	var cumulativeGasUsed int64
+	for i, res := range blockResults.Results.DeliverTx {
+		if uint32(i) > resTx.Index {
+			break
+		}
+		cumulativeGasUsed += res.GasUsed
+	}
+
+	logs, err := api.txLogs(resTx.Hash, hash, block, int(resTx.Index))
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &Receipt{
+		TransactionHash:   hash,
+		TransactionIndex:  hexutil.Uint64(resTx.Index),
+		BlockHash:         common.BytesToHash(block.BlockMeta.BlockID.Hash),
+		BlockNumber:       hexutil.Uint64(resTx.Height),
+		GasUsed:           hexutil.Uint64(resTx.TxResult.GasUsed),
+		CumulativeGasUsed: hexutil.Uint64(cumulativeGasUsed),
+		Logs:              logs,
+		LogsBloom:         logsBloom(logs),
+	}
+
+	if resTx.TxResult.IsOK() {
+		receipt.Status = 1
+	}
+
+	if tx, err := types.TxDecoder(api.cliCtx.Codec)(resTx.Tx); err == nil {
+		for _, msg := range tx.GetMsgs() {
+			if m, ok := msg.(types.MsgContract); ok {
+				receipt.From = AccAddressToHex(m.From)
+				if !m.To.Empty() {
+					to := AccAddressToHex(m.To)
+					receipt.To = &to
+				}
+				break
+			}
+		}
+
+		if receipt.From == (common.Address{}) && len(tx.GetMsgs()) > 0 && len(tx.GetMsgs()[0].GetSigners()) > 0 {
+			receipt.From = AccAddressToHex(tx.GetMsgs()[0].GetSigners()[0])
+		}
+	}
+
+	for _, event := range resTx.TxResult.Events {
+		if event.Type != types.EventTypeNewContract {
+			continue
+		}
+
+		for _, attr := range event.Attributes {
+			if string(attr.Key) != types.AttributeKeyAddress {
+				continue
+			}
+
+			if addr, err := sdk.AccAddressFromBech32(string(attr.Value)); err == nil {
+				contractAddr := AccAddressToHex(addr)
+				receipt.ContractAddress = &contractAddr
+			}
+		}
+	}
+
+	return receipt, nil
+}
+
+// SendRawTransaction broadcasts a signed, encoded tx and returns its hash, the
+// Ethereum hash of an Ethereum tx
+func (api *PublicEthAPI) SendRawTransaction(data hexutil.Bytes) (common.Hash, error) {
+	res, err := api.cliCtx.BroadcastTxSync(data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if res.Code != 0 {
+		return common.Hash{}, &rpcError{Code: errCodeServer, Message: res.RawLog}
+	}
+
+	return txHash(data), nil
+}
+
+// txHash returns the hash clients know a tx by, the keccak256 hash Ethereum clients
+// compute for an Ethereum tx and the Tendermint hash for the other txs
+func txHash(txBytes []byte) common.Hash {
+	if hash, ok := types.EthereumTxHash(txBytes); ok {
+		return common.BytesToHash(hash.Bytes())
+	}
+	return common.BytesToHash(tmhash.Sum(txBytes))
+}
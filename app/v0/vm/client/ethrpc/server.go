@@ -0,0 +1,221 @@
+package ethrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+
+	"github.com/netcloth/netcloth-chain/client/context"
+)
+
+// methodHandler decodes the positional params of a request and invokes the API
+type methodHandler func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error)
+
+var methods = map[string]methodHandler{
+	"eth_blockNumber": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		return api.BlockNumber()
+	},
+	"eth_chainId": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		return api.ChainID()
+	},
+	"eth_getBalance": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		var (
+			address  common.Address
+			blockNum = LatestBlockNumber
+		)
+		if err := parseParams(params, 1, &address, &blockNum); err != nil {
+			return nil, err
+		}
+		return api.GetBalance(address, blockNum)
+	},
+	"eth_getCode": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		var (
+			address  common.Address
+			blockNum = LatestBlockNumber
+		)
+		if err := parseParams(params, 1, &address, &blockNum); err != nil {
+			return nil, err
+		}
+		return api.GetCode(address, blockNum)
+	},
+	"eth_getStorageAt": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		var (
+			address  common.Address
+			key      string
+			blockNum = LatestBlockNumber
+		)
+		if err := parseParams(params, 2, &address, &key, &blockNum); err != nil {
+			return nil, err
+		}
+		return api.GetStorageAt(address, key, blockNum)
+	},
+	"eth_call": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		var (
+			args      CallArgs
+			blockNum  = LatestBlockNumber
+			overrides StateOverride
+		)
+		if err := parseParams(params, 1, &args, &blockNum, &overrides); err != nil {
+			return nil, err
+		}
+		return api.Call(args, blockNum, overrides)
+	},
+	"eth_estimateGas": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		var (
+			args      CallArgs
+			blockNum  = LatestBlockNumber
+			overrides StateOverride
+		)
+		if err := parseParams(params, 1, &args, &blockNum, &overrides); err != nil {
+			return nil, err
+		}
+		return api.EstimateGas(args, blockNum, overrides)
+	},
+	"eth_getLogs": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		var crit FilterCriteria
+		if err := parseParams(params, 1, &crit); err != nil {
+			return nil, err
+		}
+		return api.GetLogs(crit)
+	},
+	"eth_getTransactionReceipt": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		var hash common.Hash
+		if err := parseParams(params, 1, &hash); err != nil {
+			return nil, err
+		}
+		return api.GetTransactionReceipt(hash)
+	},
+	"eth_sendRawTransaction": func(api *PublicEthAPI, params []json.RawMessage) (interface{}, error) {
+		var data hexutil.Bytes
+		if err := parseParams(params, 1, &data); err != nil {
+			return nil, err
+		}
+		return api.SendRawTransaction(data)
+	},
+}
+
+// parseParams decodes positional params into args, of which the first required ones
+// must be present. Absent optional params keep the value args point to.
+func parseParams(params []json.RawMessage, required int, args ...interface{}) error {
+	if len(params) < required {
+		return invalidParamsError("missing value for required argument %d", len(params))
+	}
+	if len(params) > len(args) {
+		return invalidParamsError("too many arguments, want at most %d", len(args))
+	}
+
+	for i, param := range params {
+		if string(param) == "null" {
+			if i < required {
+				return invalidParamsError("missing value for required argument %d", i)
+			}
+			continue
+		}
+
+		if err := json.Unmarshal(param, args[i]); err != nil {
+			return invalidParamsError("invalid argument %d: %s", i, err)
+		}
+	}
+
+	return nil
+}
+
+// RegisterRoutes registers the Ethereum JSON-RPC handler on the root path of r
+func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
+	r.HandleFunc("/", serveJSONRPC(NewPublicEthAPI(cliCtx))).Methods("POST")
+}
+
+func serveJSONRPC(api *PublicEthAPI) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, newErrorResponse(nil, &rpcError{Code: errCodeParse, Message: err.Error()}))
+			return
+		}
+
+		body = bytes.TrimSpace(body)
+		if len(body) > 0 && body[0] == '[' {
+			var reqs []rpcRequest
+			if err := json.Unmarshal(body, &reqs); err != nil {
+				writeJSON(w, newErrorResponse(nil, &rpcError{Code: errCodeParse, Message: err.Error()}))
+				return
+			}
+
+			resps := make([]rpcResponse, len(reqs))
+			for i, req := range reqs {
+				resps[i] = handleRequest(api, req)
+			}
+			writeJSON(w, resps)
+			return
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeJSON(w, newErrorResponse(nil, &rpcError{Code: errCodeParse, Message: err.Error()}))
+			return
+		}
+
+		writeJSON(w, handleRequest(api, req))
+	}
+}
+
+func handleRequest(api *PublicEthAPI, req rpcRequest) rpcResponse {
+	if req.JSONRPC != jsonRPCVersion || req.Method == "" {
+		return newErrorResponse(req.ID, &rpcError{Code: errCodeInvalidRequest, Message: "invalid request"})
+	}
+
+	handler, ok := methods[req.Method]
+	if !ok {
+		return newErrorResponse(req.ID, &rpcError{
+			Code:    errCodeMethodNotFound,
+			Message: fmt.Sprintf("the method %s does not exist/is not available", req.Method),
+		})
+	}
+
+	var params []json.RawMessage
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newErrorResponse(req.ID, invalidParamsError("non-array args"))
+		}
+	}
+
+	result, err := handler(api, params)
+	if err != nil {
+		if rpcErr, ok := err.(*rpcError); ok {
+			return newErrorResponse(req.ID, rpcErr)
+		}
+		return newErrorResponse(req.ID, &rpcError{Code: errCodeServer, Message: err.Error()})
+	}
+
+	bz, err := json.Marshal(result)
+	if err != nil {
+		return newErrorResponse(req.ID, &rpcError{Code: errCodeServer, Message: err.Error()})
+	}
+
+	return rpcResponse{JSONRPC: jsonRPCVersion, ID: req.ID, Result: bz}
+}
+
+func newErrorResponse(id json.RawMessage, err *rpcError) rpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return rpcResponse{JSONRPC: jsonRPCVersion, ID: id, Error: err}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(bz)
+}
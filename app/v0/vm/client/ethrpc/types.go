@@ -0,0 +1,373 @@
+package ethrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	nchhexutil "github.com/netcloth/netcloth-chain/hexutil"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+const (
+	jsonRPCVersion = "2.0"
+
+	// JSON-RPC 2.0 error codes
+	errCodeParse          = -32700
+	errCodeInvalidRequest = -32600
+	errCodeMethodNotFound = -32601
+	errCodeInvalidParams  = -32602
+	errCodeServer         = -32000
+
+	// error code of a reverted eth_call, as returned by geth
+	errCodeExecutionReverted = 3
+)
+
+// rpcRequest is a JSON-RPC 2.0 request object
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC 2.0 response object
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC 2.0 error object
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func invalidParamsError(format string, args ...interface{}) *rpcError {
+	return &rpcError{Code: errCodeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// executionError converts a failed vm execution, a revert carries its reason in the
+// message and its raw data in the data of the error
+func executionError(err *types.ExecutionError) *rpcError {
+	if !err.Reverted() {
+		return &rpcError{Code: errCodeServer, Message: err.Message}
+	}
+
+	msg := "execution reverted"
+	if err.Reason != "" {
+		msg += ": " + err.Reason
+	}
+	return &rpcError{Code: errCodeExecutionReverted, Message: msg, Data: err.Data}
+}
+
+// CallArgs represents the arguments of eth_call and eth_estimateGas
+type CallArgs struct {
+	From     *common.Address `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      *hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Data     *hexutil.Bytes  `json:"data"`
+	Input    *hexutil.Bytes  `json:"input"`
+}
+
+// payload returns the call data, preferring input over data as go-ethereum does
+func (args CallArgs) payload() []byte {
+	if args.Input != nil {
+		return *args.Input
+	}
+	if args.Data != nil {
+		return *args.Data
+	}
+	return nil
+}
+
+// OverrideAccount represents the state override of an account of eth_call and
+// eth_estimateGas. Replacing the whole storage of a contract with state isn't
+// supported, only the slots of stateDiff can be overridden.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64              `json:"nonce"`
+	Code      *hexutil.Bytes               `json:"code"`
+	Balance   *hexutil.Big                 `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride represents the state overrides of eth_call and eth_estimateGas by account
+type StateOverride map[common.Address]OverrideAccount
+
+// toStateOverrides converts the overrides to the ones of the vm queries, ordered by
+// address and storage key
+func (o StateOverride) toStateOverrides() (types.StateOverrides, error) {
+	addrs := make([]common.Address, 0, len(o))
+	for addr := range o {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i].Bytes(), addrs[j].Bytes()) < 0 })
+
+	overrides := make(types.StateOverrides, 0, len(addrs))
+	for _, addr := range addrs {
+		account := o[addr]
+		if account.State != nil {
+			return nil, invalidParamsError("state override of %s: replacing the whole storage is not supported, use stateDiff", addr.Hex())
+		}
+
+		override := types.AccountOverride{Address: HexToAccAddress(addr)}
+		if account.Nonce != nil {
+			nonce := uint64(*account.Nonce)
+			override.Nonce = &nonce
+		}
+		if account.Code != nil {
+			code := nchhexutil.Bytes(*account.Code)
+			override.Code = &code
+		}
+		if account.Balance != nil {
+			balance := sdk.NewIntFromBigInt(account.Balance.ToInt())
+			override.Balance = &balance
+		}
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				override.Storage = append(override.Storage, types.StorageOverride{Key: sdk.Hash(key), Value: sdk.Hash(value)})
+			}
+			sort.Slice(override.Storage, func(i, j int) bool {
+				return bytes.Compare(override.Storage[i].Key.Bytes(), override.Storage[j].Key.Bytes()) < 0
+			})
+		}
+
+		overrides = append(overrides, override)
+	}
+
+	return overrides, nil
+}
+
+// FilterCriteria represents the filter object of eth_getLogs
+type FilterCriteria struct {
+	BlockHash *common.Hash
+	FromBlock BlockNumber
+	ToBlock   BlockNumber
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+// UnmarshalJSON decodes the filter object, accepting a single address or a list of
+// addresses, and for each topic position either null, a single topic or a list of topics.
+func (fc *FilterCriteria) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		BlockHash *common.Hash    `json:"blockHash"`
+		FromBlock *BlockNumber    `json:"fromBlock"`
+		ToBlock   *BlockNumber    `json:"toBlock"`
+		Addresses json.RawMessage `json:"address"`
+		Topics    []interface{}   `json:"topics"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.BlockHash != nil && (raw.FromBlock != nil || raw.ToBlock != nil) {
+		return fmt.Errorf("cannot specify both blockHash and fromBlock/toBlock")
+	}
+	fc.BlockHash = raw.BlockHash
+
+	fc.FromBlock, fc.ToBlock = LatestBlockNumber, LatestBlockNumber
+	if raw.FromBlock != nil {
+		fc.FromBlock = *raw.FromBlock
+	}
+	if raw.ToBlock != nil {
+		fc.ToBlock = *raw.ToBlock
+	}
+
+	if len(raw.Addresses) > 0 && string(raw.Addresses) != "null" {
+		var single common.Address
+		if err := json.Unmarshal(raw.Addresses, &single); err == nil {
+			fc.Addresses = []common.Address{single}
+		} else if err := json.Unmarshal(raw.Addresses, &fc.Addresses); err != nil {
+			return fmt.Errorf("invalid address in filter: %s", err)
+		}
+	}
+
+	fc.Topics = make([][]common.Hash, len(raw.Topics))
+	for i, t := range raw.Topics {
+		switch topic := t.(type) {
+		case nil:
+			// wildcard
+		case string:
+			h, err := decodeTopic(topic)
+			if err != nil {
+				return err
+			}
+			fc.Topics[i] = []common.Hash{h}
+		case []interface{}:
+			for _, rawTopic := range topic {
+				if rawTopic == nil {
+					// a null entry in an OR-set matches everything
+					fc.Topics[i] = nil
+					break
+				}
+				s, ok := rawTopic.(string)
+				if !ok {
+					return fmt.Errorf("invalid topic(s)")
+				}
+				h, err := decodeTopic(s)
+				if err != nil {
+					return err
+				}
+				fc.Topics[i] = append(fc.Topics[i], h)
+			}
+		default:
+			return fmt.Errorf("invalid topic(s)")
+		}
+	}
+
+	return nil
+}
+
+// blockRange resolves the heights of the blocks to search given the latest height,
+// the range starting at the first block and ending at the latest block at most. The
+// range is empty when from is after to.
+func (fc FilterCriteria) blockRange(latest int64) (from, to int64) {
+	from, to = fc.FromBlock.Int64(latest), fc.ToBlock.Int64(latest)
+	if from < EarliestBlockNumber.Int64(latest) {
+		from = EarliestBlockNumber.Int64(latest)
+	}
+	if to > latest {
+		to = latest
+	}
+	return from, to
+}
+
+func decodeTopic(s string) (common.Hash, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("hex string has length %d, want %d for topic", len(b)*2, common.HashLength*2)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// RPCLog is a contract log in the Ethereum JSON-RPC format
+type RPCLog struct {
+	Address          common.Address `json:"address"`
+	Topics           []common.Hash  `json:"topics"`
+	Data             hexutil.Bytes  `json:"data"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionHash  common.Hash    `json:"transactionHash"`
+	TransactionIndex hexutil.Uint   `json:"transactionIndex"`
+	BlockHash        common.Hash    `json:"blockHash"`
+	LogIndex         hexutil.Uint   `json:"logIndex"`
+	Removed          bool           `json:"removed"`
+}
+
+// newRPCLog converts a vm log into the Ethereum JSON-RPC format. The log index is
+// the position of the log within the tx
+func newRPCLog(log *types.Log, index int) *RPCLog {
+	topics := make([]common.Hash, len(log.Topics))
+	for i, t := range log.Topics {
+		topics[i] = common.BytesToHash(t.Bytes())
+	}
+
+	return &RPCLog{
+		Address:          AccAddressToHex(log.Address),
+		Topics:           topics,
+		Data:             hexutil.Bytes(log.Data),
+		BlockNumber:      hexutil.Uint64(log.BlockNumber),
+		TransactionHash:  common.BytesToHash(log.TxHash.Bytes()),
+		TransactionIndex: hexutil.Uint(log.TxIndex),
+		BlockHash:        common.BytesToHash(log.BlockHash.Bytes()),
+		LogIndex:         hexutil.Uint(index),
+		Removed:          log.Removed,
+	}
+}
+
+// Receipt is a tx receipt in the Ethereum JSON-RPC format
+type Receipt struct {
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64  `json:"transactionIndex"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       hexutil.Uint64  `json:"blockNumber"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []*RPCLog       `json:"logs"`
+	LogsBloom         hexutil.Bytes   `json:"logsBloom"`
+	Status            hexutil.Uint64  `json:"status"`
+}
+
+// BlockNumber is a block height parameter, either a hex quantity or one of
+// "earliest", "latest" and "pending"
+type BlockNumber int64
+
+const (
+	// PendingBlockNumber queries the latest committed state, there is no pending state
+	PendingBlockNumber = BlockNumber(-2)
+	// LatestBlockNumber queries the latest committed state
+	LatestBlockNumber = BlockNumber(-1)
+	// EarliestBlockNumber queries the first block, the chain starts at height 1
+	EarliestBlockNumber = BlockNumber(1)
+)
+
+// UnmarshalJSON parses a block number parameter
+func (bn *BlockNumber) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	switch strings.TrimSpace(s) {
+	case "latest", "":
+		*bn = LatestBlockNumber
+		return nil
+	case "pending":
+		*bn = PendingBlockNumber
+		return nil
+	case "earliest":
+		*bn = EarliestBlockNumber
+		return nil
+	}
+
+	n, err := hexutil.DecodeUint64(s)
+	if err != nil {
+		return err
+	}
+	if n > uint64(1<<63-1) {
+		return fmt.Errorf("block number larger than int64")
+	}
+
+	*bn = BlockNumber(n)
+	return nil
+}
+
+// Int64 returns the height of the block number, latest and pending resolving to the
+// given latest height
+func (bn BlockNumber) Int64(latest int64) int64 {
+	if bn < 0 {
+		return latest
+	}
+	return int64(bn)
+}
+
+// queryHeight returns the height to query the state at, 0 meaning the latest height.
+// The chain starts at height 1, no state is stored at height 0.
+func (bn BlockNumber) queryHeight() (int64, error) {
+	if bn < 0 {
+		return 0, nil
+	}
+	if bn == 0 {
+		return 0, invalidParamsError("no state is stored at block 0, the first block is 1")
+	}
+	return int64(bn), nil
+}
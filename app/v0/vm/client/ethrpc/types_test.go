@@ -0,0 +1,181 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestBlockNumberUnmarshal(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected BlockNumber
+		valid    bool
+	}{
+		{`"latest"`, LatestBlockNumber, true},
+		{`"pending"`, PendingBlockNumber, true},
+		{`"earliest"`, EarliestBlockNumber, true},
+		{`"0x0"`, BlockNumber(0), true},
+		{`"0x10"`, BlockNumber(16), true},
+		{`"16"`, 0, false},
+		{`16`, 0, false},
+	}
+
+	for i, tc := range cases {
+		var bn BlockNumber
+		err := json.Unmarshal([]byte(tc.input), &bn)
+		if !tc.valid {
+			require.Error(t, err, "case %d", i)
+			continue
+		}
+		require.NoError(t, err, "case %d", i)
+		require.Equal(t, tc.expected, bn, "case %d", i)
+	}
+}
+
+func TestBlockNumberHeight(t *testing.T) {
+	require.Equal(t, int64(10), LatestBlockNumber.Int64(10))
+	require.Equal(t, int64(10), PendingBlockNumber.Int64(10))
+	require.Equal(t, int64(0), BlockNumber(0).Int64(10))
+	require.Equal(t, int64(4), BlockNumber(4).Int64(10))
+
+	height, err := LatestBlockNumber.queryHeight()
+	require.NoError(t, err)
+	require.Equal(t, int64(0), height)
+	height, err = BlockNumber(4).queryHeight()
+	require.NoError(t, err)
+	require.Equal(t, int64(4), height)
+	_, err = BlockNumber(0).queryHeight()
+	require.Error(t, err)
+}
+
+func TestFilterCriteriaBlockRange(t *testing.T) {
+	var fc FilterCriteria
+	require.NoError(t, json.Unmarshal([]byte(`{"fromBlock":"0x0"}`), &fc))
+	from, to := fc.blockRange(10)
+	require.Equal(t, int64(1), from)
+	require.Equal(t, int64(10), to)
+
+	// there is no block 0, the range is empty
+	require.NoError(t, json.Unmarshal([]byte(`{"fromBlock":"0x0","toBlock":"0x0"}`), &fc))
+	from, to = fc.blockRange(10)
+	require.True(t, from > to)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"toBlock":"0x20"}`), &fc))
+	from, to = fc.blockRange(10)
+	require.Equal(t, int64(10), from)
+	require.Equal(t, int64(10), to)
+}
+
+func TestFilterCriteriaUnmarshal(t *testing.T) {
+	addr := "0x0102030405060708090a0b0c0d0e0f1011121314"
+	topic0 := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	topic1 := "0x000000000000000000000000000000000000000000000000000000000000abcd"
+
+	var fc FilterCriteria
+	input := `{"fromBlock":"0x1","address":"` + addr + `","topics":["` + topic0 + `",null,["` + topic0 + `","` + topic1 + `"]]}`
+	require.NoError(t, json.Unmarshal([]byte(input), &fc))
+	require.Equal(t, BlockNumber(1), fc.FromBlock)
+	require.Equal(t, LatestBlockNumber, fc.ToBlock)
+	require.Equal(t, []common.Address{common.HexToAddress(addr)}, fc.Addresses)
+	require.Len(t, fc.Topics, 3)
+	require.Equal(t, []common.Hash{common.HexToHash(topic0)}, fc.Topics[0])
+	require.Nil(t, fc.Topics[1])
+	require.Equal(t, []common.Hash{common.HexToHash(topic0), common.HexToHash(topic1)}, fc.Topics[2])
+
+	input = `{"address":["` + addr + `","` + addr + `"]}`
+	require.NoError(t, json.Unmarshal([]byte(input), &fc))
+	require.Len(t, fc.Addresses, 2)
+
+	input = `{"blockHash":"` + topic0 + `","fromBlock":"0x1"}`
+	require.Error(t, json.Unmarshal([]byte(input), &fc))
+
+	input = `{"topics":["0x1234"]}`
+	require.Error(t, json.Unmarshal([]byte(input), &fc))
+}
+
+func TestStateOverrideConversion(t *testing.T) {
+	addr1 := "0x0102030405060708090a0b0c0d0e0f1011121314"
+	addr2 := "0x0000000000000000000000000000000000000001"
+	slot0 := "0x0000000000000000000000000000000000000000000000000000000000000000"
+	slot1 := "0x0000000000000000000000000000000000000000000000000000000000000001"
+
+	var o StateOverride
+	input := `{"` + addr1 + `":{"balance":"0x64","nonce":"0x2","code":"0x6000","stateDiff":{"` + slot1 + `":"` + slot0 + `","` + slot0 + `":"` + slot1 + `"}},"` + addr2 + `":{"nonce":"0x1"}}`
+	require.NoError(t, json.Unmarshal([]byte(input), &o))
+
+	overrides, err := o.toStateOverrides()
+	require.NoError(t, err)
+	require.Len(t, overrides, 2)
+
+	require.Equal(t, HexToAccAddress(common.HexToAddress(addr2)), overrides[0].Address)
+	require.Equal(t, uint64(1), *overrides[0].Nonce)
+	require.Nil(t, overrides[0].Balance)
+	require.Nil(t, overrides[0].Code)
+
+	account := overrides[1]
+	require.Equal(t, HexToAccAddress(common.HexToAddress(addr1)), account.Address)
+	require.Equal(t, sdk.NewInt(100), *account.Balance)
+	require.Equal(t, uint64(2), *account.Nonce)
+	require.Equal(t, []byte{0x60, 0x00}, []byte(*account.Code))
+	require.Len(t, account.Storage, 2)
+	require.Equal(t, sdk.Hash(common.HexToHash(slot0)), account.Storage[0].Key)
+	require.Equal(t, sdk.Hash(common.HexToHash(slot1)), account.Storage[0].Value)
+
+	input = `{"` + addr1 + `":{"state":{"` + slot0 + `":"` + slot1 + `"}}}`
+	require.NoError(t, json.Unmarshal([]byte(input), &o))
+	_, err = o.toStateOverrides()
+	require.Error(t, err)
+}
+
+func TestParseAddress(t *testing.T) {
+	accAddr := sdk.AccAddress([]byte("addr1_______________"))
+
+	addr, err := ParseAddress(AccAddressToHex(accAddr).Hex())
+	require.NoError(t, err)
+	require.Equal(t, accAddr, addr)
+
+	addr, err = ParseAddress(accAddr.String())
+	require.NoError(t, err)
+	require.Equal(t, accAddr, addr)
+
+	_, err = ParseAddress("0x1234")
+	require.Error(t, err)
+}
+
+func TestHandleRequest(t *testing.T) {
+	api := &PublicEthAPI{}
+
+	res := handleRequest(api, rpcRequest{JSONRPC: "1.0", Method: "eth_blockNumber"})
+	require.Equal(t, errCodeInvalidRequest, res.Error.Code)
+
+	res = handleRequest(api, rpcRequest{JSONRPC: jsonRPCVersion, Method: "eth_unknown"})
+	require.Equal(t, errCodeMethodNotFound, res.Error.Code)
+
+	res = handleRequest(api, rpcRequest{JSONRPC: jsonRPCVersion, Method: "eth_getCode", Params: json.RawMessage(`[]`)})
+	require.Equal(t, errCodeInvalidParams, res.Error.Code)
+
+	res = handleRequest(api, rpcRequest{JSONRPC: jsonRPCVersion, Method: "eth_getTransactionReceipt", Params: json.RawMessage(`["0x12"]`)})
+	require.Equal(t, errCodeInvalidParams, res.Error.Code)
+}
+
+func TestTxHash(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := ethtypes.SignTx(ethtypes.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), []byte("payload")), ethtypes.HomesteadSigner{}, key)
+	require.NoError(t, err)
+	bz, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+
+	require.Equal(t, tx.Hash(), txHash(bz))
+	require.Equal(t, common.BytesToHash(tmhash.Sum([]byte("stdtx"))), txHash([]byte("stdtx")))
+}
@@ -1,11 +1,18 @@
 package rest
 
 import (
+	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/gorilla/mux"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/client/utils"
 	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
 	"github.com/netcloth/netcloth-chain/types/rest"
 
@@ -23,6 +30,11 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router) {
 		estimateGasFn(cliCtx),
 	).Methods("POST")
 
+	r.HandleFunc(
+		fmt.Sprintf("/vm/%s", types.QueryCreateAccessList),
+		createAccessListFn(cliCtx),
+	).Methods("POST")
+
 	r.HandleFunc(
 		fmt.Sprintf("/vm/%s/{addr}", types.QueryCode),
 		getCodeFn(cliCtx),
@@ -33,6 +45,26 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router) {
 		getLogFn(cliCtx),
 	).Methods("GET")
 
+	r.HandleFunc(
+		fmt.Sprintf("/vm/%s", types.QueryFilterLogs),
+		filterLogsFn(cliCtx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		fmt.Sprintf("/vm/%s/{height}", types.QueryBloom),
+		getBloomFn(cliCtx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		fmt.Sprintf("/vm/%s/{txId}", types.QueryTrace),
+		getTraceFn(cliCtx),
+	).Methods("GET")
+
+	r.HandleFunc(
+		fmt.Sprintf("/vm/%s", types.QueryTraceCall),
+		traceCallFn(cliCtx),
+	).Methods("POST")
+
 	// Get the current staking parameter values
 	r.HandleFunc(
 		"/vm/parameters",
@@ -62,19 +94,32 @@ func queryStorage(cliCtx context.CLIContext) http.HandlerFunc {
 	}
 }
 
+// estimateGas accepts a MsgContractQuery, or QuerySimulateParams to override the state
 func estimateGas(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
 
-		var params types.MsgContractQuery
-		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &params) {
+		params, err := types.UnmarshalSimulateParams(cliCtx.Codec, body)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
-		if params.From == nil || params.Payload == nil {
+		if params.Msg.From == nil || params.Msg.Payload == nil {
 			rest.WriteErrorResponse(w, http.StatusBadRequest, "bad request")
 			return
 		}
 
+		method, err := parseDecodeRequest(body)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
 		if !ok {
 			return
@@ -91,6 +136,106 @@ func estimateGas(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
+		if method != nil {
+			var out utils.DecodedSimulationResult
+			if err := cliCtx.Codec.UnmarshalJSON(res, &out.SimulationResult); err != nil {
+				rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			if res, err = decodeSimulationResult(*method, out); err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// decodeRequest is the optional ABI of the called contract and the name of the called
+// method, sent along the params of a simulation to decode its return data
+type decodeRequest struct {
+	ABI    json.RawMessage `json:"abi"`
+	Method string          `json:"method"`
+}
+
+// parseDecodeRequest returns the method given by the request body to decode the
+// return data of the simulation with, nil without method
+func parseDecodeRequest(body []byte) (*abi.Method, error) {
+	var req decodeRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Method == "" {
+		return nil, nil
+	}
+
+	abiObj, err := abi.JSON(bytes.NewReader(req.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("invalid abi: %v", err)
+	}
+
+	method, ok := abiObj.Methods[req.Method]
+	if !ok {
+		return nil, fmt.Errorf("method %s not exist", req.Method)
+	}
+
+	return &method, nil
+}
+
+// decodeSimulationResult decodes the return data of the simulation result out into
+// the outputs of method
+func decodeSimulationResult(method abi.Method, out utils.DecodedSimulationResult) ([]byte, error) {
+	if out.Err == nil {
+		data, err := hex.DecodeString(out.Res)
+		if err != nil {
+			return nil, err
+		}
+
+		if out.Outputs, err = utils.DecodeOutputs(method, data); err != nil {
+			return nil, fmt.Errorf("failed to decode the outputs of %s: %v", method.Name, err)
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+func createAccessList(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		params, err := types.UnmarshalSimulateParams(cliCtx.Codec, body)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if params.Msg.From == nil || params.Msg.Payload == nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "bad request")
+			return
+		}
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		d, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		route := fmt.Sprintf("custom/vm/%s", types.QueryCreateAccessList)
+		res, height, err := cliCtx.QueryWithData(route, d)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		cliCtx = cliCtx.WithHeight(height)
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
@@ -141,6 +286,162 @@ func getLog(cliCtx context.CLIContext) http.HandlerFunc {
 	}
 }
 
+func getBloom(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		height := vars["height"]
+
+		if _, err := strconv.ParseUint(height, 10, 64); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		res, queryHeight, err := cliCtx.Query(fmt.Sprintf("custom/vm/%s/%s", types.QueryBloom, height))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(queryHeight)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// filterLogs serves the logs matching the address, topic0 to topic3 and from_block/to_block
+// query parameters, addresses and the topics of a position are comma separated
+func filterLogs(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+
+		var fromBlock, toBlock uint64
+		var err error
+		if v := query.Get("from_block"); v != "" {
+			if fromBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if v := query.Get("to_block"); v != "" {
+			if toBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		var addresses []string
+		if v := query.Get("address"); v != "" {
+			addresses = strings.Split(v, ",")
+		}
+
+		var topics []string
+		for i := 0; i < types.MaxLogTopics; i++ {
+			topics = append(topics, query.Get(fmt.Sprintf("topic%d", i)))
+		}
+
+		filter, err := utils.ParseLogFilter(fromBlock, toBlock, addresses, topics)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		out, height, err := utils.QueryFilterLogs(cliCtx, filter)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, out)
+	}
+}
+
+func getTrace(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		txID := vars["txId"]
+
+		query := r.URL.Query()
+		config := types.TraceConfig{
+			Tracer:         query.Get("tracer"),
+			DisableMemory:  query.Get("disable_memory") == "true",
+			DisableStack:   query.Get("disable_stack") == "true",
+			DisableStorage: query.Get("disable_storage") == "true",
+		}
+
+		if limit := query.Get("limit"); limit != "" {
+			l, err := strconv.Atoi(limit)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			config.Limit = l
+		}
+
+		if err := config.Validate(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		out, err := utils.QueryTrace(cliCtx, txID, config)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, out)
+	}
+}
+
+func traceCall(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params types.QueryTraceCallParams
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &params) {
+			return
+		}
+
+		if params.Msg.From == nil || params.Msg.Payload == nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "bad request")
+			return
+		}
+
+		if err := params.Config.Validate(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		d, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		route := fmt.Sprintf("custom/vm/%s", types.QueryTraceCall)
+		res, height, err := cliCtx.QueryWithData(route, d)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
 func getParams(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
@@ -166,6 +467,10 @@ func estimateGasFn(cliCtx context.CLIContext) http.HandlerFunc {
 	return estimateGas(cliCtx)
 }
 
+func createAccessListFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return createAccessList(cliCtx)
+}
+
 func getCodeFn(cliCtx context.CLIContext) http.HandlerFunc {
 	return getCode(cliCtx)
 }
@@ -174,6 +479,22 @@ func getLogFn(cliCtx context.CLIContext) http.HandlerFunc {
 	return getLog(cliCtx)
 }
 
+func filterLogsFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return filterLogs(cliCtx)
+}
+
+func getBloomFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return getBloom(cliCtx)
+}
+
+func getTraceFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return getTrace(cliCtx)
+}
+
+func traceCallFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return traceCall(cliCtx)
+}
+
 // HTTP request handler to query the staking params values
 func paramsHandlerFn(cliCtx context.CLIContext) http.HandlerFunc {
 	return getParams(cliCtx)
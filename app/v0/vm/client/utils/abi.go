@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	"github.com/netcloth/netcloth-chain/hexutil"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// DecodedArg is a named and typed argument of an event or a method, its value is
+// rendered by FormatAbiValue
+type DecodedArg struct {
+	Name    string      `json:"name" yaml:"name"`
+	Type    string      `json:"type" yaml:"type"`
+	Indexed bool        `json:"indexed,omitempty" yaml:"indexed,omitempty"`
+	Value   interface{} `json:"value" yaml:"value"`
+}
+
+// DecodedLog is a contract log decoded with the ABI of the contract. The topics and
+// the data of the log are kept when no event of the ABI matches it.
+type DecodedLog struct {
+	Address     sdk.AccAddress `json:"address" yaml:"address"`
+	TxHash      sdk.Hash       `json:"transactionHash" yaml:"transactionHash"`
+	BlockNumber uint64         `json:"blockNumber" yaml:"blockNumber"`
+	Index       uint64         `json:"logIndex" yaml:"logIndex"`
+	Event       string         `json:"event,omitempty" yaml:"event,omitempty"`
+	Signature   string         `json:"signature,omitempty" yaml:"signature,omitempty"`
+	Args        []DecodedArg   `json:"args,omitempty" yaml:"args,omitempty"`
+	Topics      []sdk.Hash     `json:"topics,omitempty" yaml:"topics,omitempty"`
+	Data        hexutil.Bytes  `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// DecodedSimulationResult is the result of a simulated call with the return data
+// decoded into the outputs of the called method
+type DecodedSimulationResult struct {
+	types.SimulationResult
+	Outputs []DecodedArg `json:"outputs"`
+}
+
+// DecodeLog decodes a log with the events of abiObj: the indexed arguments come from
+// the topics following the event id and the other ones from the data. The indexed
+// strings, bytes, arrays and tuples are only known by the hash in their topic.
+func DecodeLog(abiObj abi.ABI, log types.Log) (DecodedLog, error) {
+	decoded := DecodedLog{
+		Address:     log.Address,
+		TxHash:      log.TxHash,
+		BlockNumber: log.BlockNumber,
+		Index:       log.Index,
+		Topics:      log.Topics,
+		Data:        log.Data,
+	}
+	if len(log.Topics) == 0 {
+		return decoded, fmt.Errorf("anonymous log")
+	}
+
+	event, err := abiObj.EventByID(common.Hash(log.Topics[0]))
+	if err != nil {
+		return decoded, err
+	}
+
+	values, err := event.Inputs.UnpackValues(log.Data)
+	if err != nil {
+		return decoded, fmt.Errorf("failed to decode the data of %s: %v", event.Sig, err)
+	}
+
+	topics := log.Topics[1:]
+	args := make([]DecodedArg, 0, len(event.Inputs))
+	for _, input := range event.Inputs {
+		arg := DecodedArg{Name: input.Name, Type: input.Type.String(), Indexed: input.Indexed}
+		if !input.Indexed {
+			arg.Value = FormatAbiValue(input.Type, values[0])
+			values = values[1:]
+			args = append(args, arg)
+			continue
+		}
+
+		if len(topics) == 0 {
+			return decoded, fmt.Errorf("missing topic of %s of %s", input.Name, event.Sig)
+		}
+		value, err := decodeTopic(input, topics[0])
+		if err != nil {
+			return decoded, err
+		}
+		arg.Value = value
+		topics = topics[1:]
+		args = append(args, arg)
+	}
+
+	decoded.Event = event.Name
+	decoded.Signature = event.Sig
+	decoded.Args = args
+	decoded.Topics = nil
+	decoded.Data = nil
+	return decoded, nil
+}
+
+func decodeTopic(input abi.Argument, topic sdk.Hash) (interface{}, error) {
+	switch input.Type.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return topic.Hex(), nil
+	}
+
+	out := make(map[string]interface{}, 1)
+	if err := abi.ParseTopicsIntoMap(out, abi.Arguments{input}, []common.Hash{common.Hash(topic)}); err != nil {
+		return nil, err
+	}
+
+	return FormatAbiValue(input.Type, out[input.Name]), nil
+}
+
+// DecodeOutputs decodes the return data of a call of method into its named and typed outputs
+func DecodeOutputs(method abi.Method, data []byte) ([]DecodedArg, error) {
+	values, err := method.Outputs.UnpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]DecodedArg, len(values))
+	for i, output := range method.Outputs {
+		args[i] = DecodedArg{Name: output.Name, Type: output.Type.String(), Value: FormatAbiValue(output.Type, values[i])}
+	}
+
+	return args, nil
+}
+
+// FormatAbiValue renders a value unpacked by the abi package in a form printable as
+// JSON and YAML: integers as decimal strings, addresses as bech32 addresses, bytes as
+// hex strings, arrays as lists and tuples as maps of their fields
+func FormatAbiValue(t abi.Type, value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		if i, ok := value.(*big.Int); ok {
+			return i.String()
+		}
+		return fmt.Sprint(value)
+	case abi.BoolTy, abi.StringTy:
+		return value
+	case abi.AddressTy:
+		if addr, ok := value.(common.Address); ok {
+			return sdk.AccAddress(addr.Bytes()).String()
+		}
+		return fmt.Sprint(value)
+	case abi.BytesTy:
+		return hexutil.Encode(v.Bytes())
+	case abi.FixedBytesTy, abi.FunctionTy, abi.HashTy:
+		bz := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(bz), v)
+		return hexutil.Encode(bz)
+	case abi.SliceTy, abi.ArrayTy:
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = FormatAbiValue(*t.Elem, v.Index(i).Interface())
+		}
+		return list
+	case abi.TupleTy:
+		fields := make(map[string]interface{}, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			fields[t.TupleRawNames[i]] = FormatAbiValue(*elem, v.Field(i).Interface())
+		}
+		return fields
+	default:
+		return fmt.Sprint(value)
+	}
+}
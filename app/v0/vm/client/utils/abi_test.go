@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+const testABI = `[
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+	{"type":"event","name":"Note","inputs":[{"name":"tag","type":"string","indexed":true},{"name":"data","type":"bytes","indexed":false},{"name":"list","type":"uint8[]","indexed":false}]},
+	{"type":"function","name":"info","inputs":[],"outputs":[{"name":"owner","type":"address"},{"name":"delta","type":"int256"},{"name":"holders","type":"address[]"},{"name":"data","type":"bytes"}]}
+]`
+
+func TestDecodeLog(t *testing.T) {
+	abiObj, err := abi.JSON(strings.NewReader(testABI))
+	require.NoError(t, err)
+
+	from := sdk.AccAddress(common.HexToAddress("0x1111111111111111111111111111111111111111").Bytes())
+	to := sdk.AccAddress(common.HexToAddress("0x2222222222222222222222222222222222222222").Bytes())
+	data, err := abiObj.Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(100))
+	require.NoError(t, err)
+
+	log := types.Log{
+		Address: to,
+		Topics: []sdk.Hash{
+			sdk.Hash(abiObj.Events["Transfer"].ID),
+			sdk.BytesToHash(from),
+			sdk.BytesToHash(to),
+		},
+		Data:  data,
+		Index: 3,
+	}
+	decoded, err := DecodeLog(abiObj, log)
+	require.NoError(t, err)
+	require.Equal(t, "Transfer", decoded.Event)
+	require.Equal(t, "Transfer(address,address,uint256)", decoded.Signature)
+	require.Equal(t, uint64(3), decoded.Index)
+	require.Nil(t, decoded.Topics)
+	require.Equal(t, []DecodedArg{
+		{Name: "from", Type: "address", Indexed: true, Value: from.String()},
+		{Name: "to", Type: "address", Indexed: true, Value: to.String()},
+		{Name: "value", Type: "uint256", Value: "100"},
+	}, decoded.Args)
+
+	data, err = abiObj.Events["Note"].Inputs.NonIndexed().Pack([]byte{0xca, 0xfe}, []uint8{1, 2})
+	require.NoError(t, err)
+	tag := sdk.BytesToHash(crypto.Keccak256([]byte("tag")))
+	log.Topics = []sdk.Hash{sdk.Hash(abiObj.Events["Note"].ID), tag}
+	log.Data = data
+	decoded, err = DecodeLog(abiObj, log)
+	require.NoError(t, err)
+	require.Equal(t, []DecodedArg{
+		{Name: "tag", Type: "string", Indexed: true, Value: tag.Hex()},
+		{Name: "data", Type: "bytes", Value: "cafe"},
+		{Name: "list", Type: "uint8[]", Value: []interface{}{"1", "2"}},
+	}, decoded.Args)
+
+	// unknown event
+	log.Topics = []sdk.Hash{sdk.BytesToHash([]byte("unknown"))}
+	decoded, err = DecodeLog(abiObj, log)
+	require.Error(t, err)
+	require.Equal(t, log.Topics, decoded.Topics)
+	require.Equal(t, "", decoded.Event)
+}
+
+func TestDecodeOutputs(t *testing.T) {
+	abiObj, err := abi.JSON(strings.NewReader(testABI))
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	holder := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	method := abiObj.Methods["info"]
+	data, err := method.Outputs.Pack(owner, big.NewInt(-7), []common.Address{owner, holder}, []byte{0xca, 0xfe})
+	require.NoError(t, err)
+
+	outputs, err := DecodeOutputs(method, data)
+	require.NoError(t, err)
+	require.Equal(t, []DecodedArg{
+		{Name: "owner", Type: "address", Value: sdk.AccAddress(owner.Bytes()).String()},
+		{Name: "delta", Type: "int256", Value: "-7"},
+		{Name: "holders", Type: "address[]", Value: []interface{}{sdk.AccAddress(owner.Bytes()).String(), sdk.AccAddress(holder.Bytes()).String()}},
+		{Name: "data", Type: "bytes", Value: "cafe"},
+	}, outputs)
+
+	_, err = DecodeOutputs(method, data[:32])
+	require.Error(t, err)
+}
+
+func TestFormatAbiValue(t *testing.T) {
+	tupleType, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "owner", Type: "address"},
+		{Name: "balance", Type: "int256"},
+		{Name: "id", Type: "bytes32"},
+	})
+	require.NoError(t, err)
+
+	addr := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	value := reflectTuple(tupleType, addr, big.NewInt(-5), [32]byte{1})
+	require.Equal(t, map[string]interface{}{
+		"owner":   sdk.AccAddress(addr.Bytes()).String(),
+		"balance": "-5",
+		"id":      "0100000000000000000000000000000000000000000000000000000000000000",
+	}, FormatAbiValue(tupleType, value))
+
+	arrayType, err := abi.NewType("address[2]", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{sdk.AccAddress(addr.Bytes()).String(), sdk.AccAddress(addr.Bytes()).String()},
+		FormatAbiValue(arrayType, [2]common.Address{addr, addr}))
+
+	boolType, err := abi.NewType("bool", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, true, FormatAbiValue(boolType, true))
+}
+
+// reflectTuple builds a value of the struct the abi package unpacks a tuple into
+func reflectTuple(t abi.Type, fields ...interface{}) interface{} {
+	data, err := abi.Arguments{{Type: t}}.Pack(struct {
+		Owner   common.Address
+		Balance *big.Int
+		Id      [32]byte
+	}{fields[0].(common.Address), fields[1].(*big.Int), fields[2].([32]byte)})
+	if err != nil {
+		panic(err)
+	}
+
+	values, err := abi.Arguments{{Type: t}}.UnpackValues(data)
+	if err != nil {
+		panic(err)
+	}
+	return values[0]
+}
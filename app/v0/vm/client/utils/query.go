@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	"github.com/netcloth/netcloth-chain/client/context"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// QueryTrace traces the MsgContracts of a committed tx, given by its hash in hex
+// format. It loads the block of the tx and queries the vm module at the height of
+// the parent block, sending along the txs which precede the traced one.
+func QueryTrace(cliCtx context.CLIContext, hashHexStr string, config types.TraceConfig) (types.TraceResults, error) {
+	var out types.TraceResults
+
+	hash, err := hex.DecodeString(strings.TrimPrefix(hashHexStr, "0x"))
+	if err != nil {
+		return out, err
+	}
+
+	node, err := cliCtx.GetNode()
+	if err != nil {
+		return out, err
+	}
+
+	resTx, err := node.Tx(hash, false)
+	if err != nil {
+		return out, err
+	}
+
+	resBlock, err := node.Block(&resTx.Height)
+	if err != nil {
+		return out, err
+	}
+
+	block := resBlock.Block
+	params := types.QueryTraceParams{
+		Height:          block.Height,
+		Time:            block.Time,
+		ProposerAddress: block.ProposerAddress,
+		LastBlockHash:   block.LastBlockID.Hash,
+		Tx:              resTx.Tx,
+		Config:          config,
+	}
+
+	for i := uint32(0); i < resTx.Index; i++ {
+		params.PrecedingTxs = append(params.PrecedingTxs, block.Data.Txs[i])
+	}
+
+	bz, err := cliCtx.Codec.MarshalJSON(params)
+	if err != nil {
+		return out, err
+	}
+
+	res, _, err := cliCtx.WithHeight(block.Height-1).QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryTrace), bz)
+	if err != nil {
+		return out, err
+	}
+
+	err = cliCtx.Codec.UnmarshalJSON(res, &out)
+	return out, err
+}
+
+// ParseLogFilter builds a log filter from bech32 contract addresses and topic positions,
+// each position being a comma separated list of hex topics, empty for any topic
+func ParseLogFilter(fromBlock, toBlock uint64, addresses []string, topics []string) (types.LogFilter, error) {
+	filter := types.NewLogFilter(fromBlock, toBlock, nil, nil)
+
+	for _, a := range addresses {
+		addr, err := sdk.AccAddressFromBech32(a)
+		if err != nil {
+			return filter, err
+		}
+		filter.Addresses = append(filter.Addresses, addr)
+	}
+
+	for _, position := range topics {
+		var sub []sdk.Hash
+		for _, t := range strings.Split(position, ",") {
+			t = strings.TrimSpace(t)
+			if t == "" {
+				continue
+			}
+
+			bz, err := hex.DecodeString(strings.TrimPrefix(t, "0x"))
+			if err != nil {
+				return filter, err
+			}
+			if len(bz) != sdk.HashLength {
+				return filter, fmt.Errorf("invalid topic %s, expected %d bytes", t, sdk.HashLength)
+			}
+			sub = append(sub, sdk.BytesToHash(bz))
+		}
+		filter.Topics = append(filter.Topics, sub)
+	}
+
+	return filter, filter.Validate()
+}
+
+// QueryFilterLogs queries the logs matching the filter through the log indexes of the vm module
+func QueryFilterLogs(cliCtx context.CLIContext, filter types.LogFilter) (types.QueryLogsResult, int64, error) {
+	var out types.QueryLogsResult
+
+	bz, err := cliCtx.Codec.MarshalJSON(filter)
+	if err != nil {
+		return out, 0, err
+	}
+
+	res, height, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryFilterLogs), bz)
+	if err != nil {
+		return out, 0, err
+	}
+
+	err = cliCtx.Codec.UnmarshalJSON(res, &out)
+	return out, height, err
+}
@@ -0,0 +1,148 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+var (
+	// ErrNotVerified is returned for the contracts missing from the registry
+	ErrNotVerified = errors.New("contract not verified")
+
+	// ErrAlreadyVerified is returned for a new verification of a verified contract
+	// whose code doesn't match the deployed code up to the metadata hash
+	ErrAlreadyVerified = errors.New("contract already verified")
+)
+
+// Registry stores the verified contracts in a local LevelDB, opened for each
+// operation like the keybase is
+type Registry struct {
+	name string
+	dir  string
+	mtx  *sync.Mutex
+}
+
+// NewRegistry creates a registry stored in dir
+func NewRegistry(name, dir string) Registry {
+	if err := cmn.EnsureDir(dir, 0700); err != nil {
+		panic(fmt.Sprintf("failed to create the verified contracts directory: %s", err))
+	}
+
+	return Registry{name: name, dir: dir, mtx: &sync.Mutex{}}
+}
+
+// Get returns the verified contract at addr
+func (r Registry) Get(addr sdk.AccAddress) (contract VerifiedContract, err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	db, err := sdk.NewLevelDB(r.name, r.dir)
+	if err != nil {
+		return contract, err
+	}
+	defer db.Close()
+
+	bz := db.Get(addr.Bytes())
+	if bz == nil {
+		return contract, ErrNotVerified
+	}
+
+	err = json.Unmarshal(bz, &contract)
+	return contract, err
+}
+
+// Set stores a verified contract. The first verification of an address is kept:
+// it is only replaced if replace is set, else ErrAlreadyVerified is returned.
+func (r Registry) Set(contract VerifiedContract, replace bool) error {
+	bz, err := json.Marshal(contract)
+	if err != nil {
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	db, err := sdk.NewLevelDB(r.name, r.dir)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if !replace && db.Has(contract.Address.Bytes()) {
+		return ErrAlreadyVerified
+	}
+
+	db.SetSync(contract.Address.Bytes(), bz)
+	return nil
+}
+
+// Verify compiles the sources of the request and compares the runtime code of the
+// contract with the code deployed at its address, queried at height. The metadata
+// hash appended by solc is ignored, it changes with the comments and the paths of
+// the sources. The verified contract is stored in the registry. A verified contract
+// is only verified again with the sources of the metadata hash of its code: other
+// sources compiling to the same code can't replace the published ones.
+func (r Registry) Verify(compiler Compiler, req VerifyRequest, deployedCode []byte, height int64) (VerifiedContract, error) {
+	if err := req.ValidateBasic(); err != nil {
+		return VerifiedContract{}, err
+	}
+
+	if len(deployedCode) == 0 {
+		return VerifiedContract{}, fmt.Errorf("no code deployed at %s", req.Address)
+	}
+
+	abi, code, err := compiler.Compile(req)
+	if err != nil {
+		return VerifiedContract{}, err
+	}
+
+	if !bytes.Equal(StripMetadata(code), StripMetadata(deployedCode)) {
+		return VerifiedContract{}, fmt.Errorf("the runtime code of %s doesn't match the code deployed at %s", req.ContractName, req.Address)
+	}
+
+	contract := VerifiedContract{
+		Address:         req.Address,
+		ContractName:    req.ContractName,
+		CompilerVersion: req.CompilerVersion,
+		Settings:        req.Settings,
+		Sources:         req.Sources,
+		ABI:             abi,
+		Height:          height,
+	}
+
+	return contract, r.Set(contract, bytes.Equal(code, deployedCode))
+}
+
+// StripMetadata removes the CBOR encoded metadata solc appends to the runtime code,
+// its length is given by the last two bytes of the code. The code is returned as is
+// when it doesn't end with a CBOR map of that length.
+func StripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+
+	size := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - size
+	if size == 0 || start < 0 || code[start]&0xe0 != 0xa0 {
+		return code
+	}
+
+	return code[:start]
+}
+
+func decodeHex(s string) ([]byte, error) {
+	if strings.Contains(s, "__") {
+		return nil, errors.New("linked libraries are not supported")
+	}
+
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
@@ -0,0 +1,127 @@
+package verifier
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// runtime code of a contract and its metadata: a CBOR map holding the ipfs hash and
+// the solc version, followed by its length
+const (
+	runtimeCode = "6080604052600080fd00"
+	metadata    = "a2646970667358221220" + "0101010101010101010101010101010101010101010101010101010101010101" + "64736f6c634300080a0033"
+)
+
+type mockCompiler struct {
+	code []byte
+	err  error
+}
+
+func (c mockCompiler) Compile(req VerifyRequest) (json.RawMessage, []byte, error) {
+	return json.RawMessage(`[]`), c.code, c.err
+}
+
+func TestStripMetadata(t *testing.T) {
+	code := sdk.FromHex(runtimeCode)
+	require.Equal(t, code, StripMetadata(sdk.FromHex(runtimeCode+metadata)))
+	require.Equal(t, code, StripMetadata(code))
+	require.Equal(t, []byte{0x33}, StripMetadata([]byte{0x33}))
+}
+
+func TestRegistryVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verifier")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	registry := NewRegistry(registryName, dir)
+	addr := sdk.AccAddress([]byte("contract_address_000"))
+	req := VerifyRequest{
+		Address:         addr,
+		ContractName:    "Token",
+		CompilerVersion: "0.8.10+commit.fc410830",
+		Sources:         map[string]string{"Token.sol": "contract Token {}"},
+	}
+
+	_, err = registry.Get(addr)
+	require.Equal(t, ErrNotVerified, err)
+
+	// the metadata hash differs with the one of the deployed code
+	otherMetadata := metadata[:20] + "02" + metadata[22:]
+	compiler := mockCompiler{code: sdk.FromHex(runtimeCode + otherMetadata)}
+	contract, err := registry.Verify(compiler, req, sdk.FromHex(runtimeCode+metadata), 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), contract.Height)
+
+	stored, err := registry.Get(addr)
+	require.NoError(t, err)
+	require.Equal(t, contract, stored)
+
+	// other sources compiling to the same code don't replace the verified ones
+	published := req.Sources
+	req.Sources = map[string]string{"Token.sol": "// other comments\ncontract Token {}"}
+	_, err = registry.Verify(compiler, req, sdk.FromHex(runtimeCode+metadata), 11)
+	require.Equal(t, ErrAlreadyVerified, err)
+	stored, err = registry.Get(addr)
+	require.NoError(t, err)
+	require.Equal(t, published, stored.Sources)
+
+	// the sources matching the metadata hash of the deployed code do
+	contract, err = registry.Verify(mockCompiler{code: sdk.FromHex(runtimeCode + metadata)}, req, sdk.FromHex(runtimeCode+metadata), 11)
+	require.NoError(t, err)
+	stored, err = registry.Get(addr)
+	require.NoError(t, err)
+	require.Equal(t, contract, stored)
+
+	_, err = registry.Verify(mockCompiler{code: sdk.FromHex("6000" + metadata)}, req, sdk.FromHex(runtimeCode+metadata), 10)
+	require.Error(t, err)
+	_, err = registry.Verify(mockCompiler{err: errors.New("compilation failed")}, req, sdk.FromHex(runtimeCode), 10)
+	require.Error(t, err)
+	_, err = registry.Verify(compiler, req, nil, 10)
+	require.Error(t, err)
+	req.Sources = nil
+	_, err = registry.Verify(compiler, req, sdk.FromHex(runtimeCode), 10)
+	require.Error(t, err)
+}
+
+func TestParseSolcOutput(t *testing.T) {
+	out := []byte(`{
+		"errors": [{"severity": "warning", "formattedMessage": "unused variable"}],
+		"contracts": {
+			"Token.sol": {"Token": {"abi": [], "evm": {"deployedBytecode": {"object": "` + runtimeCode + `"}}}},
+			"Lib.sol": {"Token": {"abi": [], "evm": {"deployedBytecode": {"object": "00"}}}}
+		}
+	}`)
+
+	_, _, err := parseSolcOutput(out, "Token")
+	require.Error(t, err)
+	_, code, err := parseSolcOutput(out, "Token.sol:Token")
+	require.NoError(t, err)
+	require.Equal(t, sdk.FromHex(runtimeCode), code)
+	_, _, err = parseSolcOutput(out, "Missing")
+	require.Error(t, err)
+
+	_, _, err = parseSolcOutput([]byte(`{"errors": [{"severity": "error", "formattedMessage": "syntax error"}]}`), "Token")
+	require.Error(t, err)
+}
+
+func TestNewSolcInput(t *testing.T) {
+	bz, err := newSolcInput(VerifyRequest{
+		Sources:  map[string]string{"Token.sol": "contract Token {}"},
+		Settings: json.RawMessage(`{"optimizer": {"enabled": true, "runs": 200}}`),
+	})
+	require.NoError(t, err)
+
+	var input map[string]interface{}
+	require.NoError(t, json.Unmarshal(bz, &input))
+	settings := input["settings"].(map[string]interface{})
+	require.Contains(t, settings, "optimizer")
+	require.Contains(t, settings, "outputSelection")
+	require.Equal(t, "contract Token {}", input["sources"].(map[string]interface{})["Token.sol"].(map[string]interface{})["content"])
+}
@@ -0,0 +1,155 @@
+package verifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	"github.com/netcloth/netcloth-chain/client/context"
+	"github.com/netcloth/netcloth-chain/client/flags"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	"github.com/netcloth/netcloth-chain/types/rest"
+)
+
+const (
+	registryName = "verified_contracts"
+
+	// maxVerifyRequestSize bounds the size of the sources submitted for verification
+	maxVerifyRequestSize = 10 << 20
+)
+
+// RegisterRoutes registers the routes of the verification service, the verified
+// contracts are stored in the home directory of the rest server and the sources are
+// compiled by the solc binary given by --solc
+func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router) {
+	registry := NewRegistry(registryName, filepath.Join(viper.GetString(flags.FlagHome), registryName))
+	compiler := Solc{Path: viper.GetString(flags.FlagSolc)}
+
+	r.HandleFunc("/vm/verify", verifyHandlerFn(cliCtx, registry, compiler)).Methods("POST")
+	r.HandleFunc("/vm/verified/{addr}", verifiedContractHandlerFn(cliCtx, registry)).Methods("GET")
+	r.HandleFunc("/vm/verified/{addr}/abi", verifiedABIHandlerFn(cliCtx, registry)).Methods("GET")
+}
+
+func verifyHandlerFn(cliCtx context.CLIContext, registry Registry, compiler Compiler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxVerifyRequestSize))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var req VerifyRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := req.ValidateBasic(); err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		code, height, err := cliCtx.Query(fmt.Sprintf("custom/vm/%s/%s", types.QueryCode, req.Address))
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		contract, err := registry.Verify(compiler, req, code, height)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		bz, err := json.Marshal(contract)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx.WithHeight(height), bz)
+	}
+}
+
+func verifiedContractHandlerFn(cliCtx context.CLIContext, registry Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		contract, ok := getVerifiedContract(w, r, registry)
+		if !ok {
+			return
+		}
+
+		bz, err := json.Marshal(contract)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		rest.PostProcessResponseBare(w, cliCtx, bz)
+	}
+}
+
+// verifiedABIHandlerFn returns the bare ABI of a verified contract, as found in an abi file
+func verifiedABIHandlerFn(cliCtx context.CLIContext, registry Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		contract, ok := getVerifiedContract(w, r, registry)
+		if !ok {
+			return
+		}
+
+		rest.PostProcessResponseBare(w, cliCtx, []byte(contract.ABI))
+	}
+}
+
+func getVerifiedContract(w http.ResponseWriter, r *http.Request, registry Registry) (VerifiedContract, bool) {
+	addr, err := sdk.AccAddressFromBech32(mux.Vars(r)["addr"])
+	if err != nil {
+		rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return VerifiedContract{}, false
+	}
+
+	contract, err := registry.Get(addr)
+	if err == ErrNotVerified {
+		rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		return VerifiedContract{}, false
+	}
+	if err != nil {
+		rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return VerifiedContract{}, false
+	}
+
+	return contract, true
+}
+
+// FetchABI gets the ABI of a verified contract from the verification service of
+// the rest server at url
+func FetchABI(url string, addr sdk.AccAddress) (abi.ABI, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/vm/verified/%s/abi", strings.TrimSuffix(url, "/"), addr))
+	if err != nil {
+		return abi.ABI{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return abi.ABI{}, fmt.Errorf("failed to fetch the abi of %s: %s", addr, body)
+	}
+
+	return abi.JSON(strings.NewReader(string(body)))
+}
@@ -0,0 +1,162 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Compiler compiles the sources of a verification request
+type Compiler interface {
+	// Compile returns the ABI and the runtime code of the named contract
+	Compile(req VerifyRequest) (abi json.RawMessage, runtimeCode []byte, err error)
+}
+
+var _ Compiler = Solc{}
+
+// Solc is a Compiler running a locally installed solc binary with its standard JSON
+// interface, the binary must be of the version requested
+type Solc struct {
+	Path string
+}
+
+type solcSource struct {
+	Content string `json:"content"`
+}
+
+type solcInput struct {
+	Language string                 `json:"language"`
+	Sources  map[string]solcSource  `json:"sources"`
+	Settings map[string]interface{} `json:"settings"`
+}
+
+type solcOutput struct {
+	Errors []struct {
+		Severity         string `json:"severity"`
+		FormattedMessage string `json:"formattedMessage"`
+	} `json:"errors"`
+	Contracts map[string]map[string]struct {
+		ABI json.RawMessage `json:"abi"`
+		EVM struct {
+			DeployedBytecode struct {
+				Object string `json:"object"`
+			} `json:"deployedBytecode"`
+		} `json:"evm"`
+	} `json:"contracts"`
+}
+
+// Version returns the version string printed by the binary
+func (s Solc) Version() (string, error) {
+	out, err := exec.Command(s.Path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s: %v", s.Path, err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.HasPrefix(line, "Version:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Version:")), nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown version of %s: %s", s.Path, out)
+}
+
+// Compile implements the Compiler interface
+func (s Solc) Compile(req VerifyRequest) (json.RawMessage, []byte, error) {
+	version, err := s.Version()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !strings.HasPrefix(version, strings.TrimPrefix(req.CompilerVersion, "v")) {
+		return nil, nil, fmt.Errorf("compiler version %s requested, solc is %s", req.CompilerVersion, version)
+	}
+
+	input, err := newSolcInput(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cmd := exec.Command(s.Path, "--standard-json")
+	cmd.Stdin = bytes.NewReader(input)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to run %s: %v", s.Path, err)
+	}
+
+	return parseSolcOutput(out, req.ContractName)
+}
+
+// newSolcInput returns the standard JSON input of the request, selecting the ABI
+// and the runtime code of every contract as output
+func newSolcInput(req VerifyRequest) ([]byte, error) {
+	input := solcInput{
+		Language: "Solidity",
+		Sources:  make(map[string]solcSource, len(req.Sources)),
+		Settings: make(map[string]interface{}),
+	}
+	for name, content := range req.Sources {
+		input.Sources[name] = solcSource{Content: content}
+	}
+
+	if len(req.Settings) > 0 {
+		if err := json.Unmarshal(req.Settings, &input.Settings); err != nil {
+			return nil, fmt.Errorf("invalid compiler settings: %v", err)
+		}
+	}
+	input.Settings["outputSelection"] = map[string]map[string][]string{
+		"*": {"*": {"abi", "evm.deployedBytecode.object"}},
+	}
+
+	return json.Marshal(input)
+}
+
+// parseSolcOutput returns the ABI and the runtime code of the named contract
+// from the standard JSON output of solc
+func parseSolcOutput(bz []byte, contractName string) (json.RawMessage, []byte, error) {
+	var out solcOutput
+	if err := json.Unmarshal(bz, &out); err != nil {
+		return nil, nil, fmt.Errorf("invalid solc output: %v", err)
+	}
+
+	for _, e := range out.Errors {
+		if e.Severity == "error" {
+			return nil, nil, fmt.Errorf("compilation failed: %s", e.FormattedMessage)
+		}
+	}
+
+	file, name := "", contractName
+	if i := strings.LastIndex(contractName, ":"); i >= 0 {
+		file, name = contractName[:i], contractName[i+1:]
+	}
+
+	found := false
+	var abi json.RawMessage
+	var code []byte
+	for f, contracts := range out.Contracts {
+		if file != "" && f != file {
+			continue
+		}
+
+		contract, ok := contracts[name]
+		if !ok {
+			continue
+		}
+		if found {
+			return nil, nil, fmt.Errorf("contract %s is defined in several sources, use <file>:<name>", name)
+		}
+
+		var err error
+		if code, err = decodeHex(contract.EVM.DeployedBytecode.Object); err != nil {
+			return nil, nil, fmt.Errorf("invalid runtime code of %s: %v", contractName, err)
+		}
+		abi, found = contract.ABI, true
+	}
+
+	if !found {
+		return nil, nil, fmt.Errorf("contract %s not found in the compiled sources", contractName)
+	}
+
+	return abi, code, nil
+}
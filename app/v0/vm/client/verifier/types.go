@@ -0,0 +1,50 @@
+package verifier
+
+import (
+	"encoding/json"
+	"errors"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// VerifyRequest is the Solidity metadata of a deployed contract: the compiler version,
+// the settings of the standard JSON input of solc and the sources by file name. The
+// contract name is either the name of the contract or "<file>:<name>" when the name
+// isn't unique among the sources.
+type VerifyRequest struct {
+	Address         sdk.AccAddress    `json:"address"`
+	ContractName    string            `json:"contract_name"`
+	CompilerVersion string            `json:"compiler_version"`
+	Settings        json.RawMessage   `json:"settings,omitempty"`
+	Sources         map[string]string `json:"sources"`
+}
+
+// ValidateBasic checks that the request names a contract and carries sources
+func (req VerifyRequest) ValidateBasic() error {
+	if req.Address.Empty() {
+		return errors.New("missing contract address")
+	}
+	if req.ContractName == "" {
+		return errors.New("missing contract name")
+	}
+	if req.CompilerVersion == "" {
+		return errors.New("missing compiler version")
+	}
+	if len(req.Sources) == 0 {
+		return errors.New("missing sources")
+	}
+
+	return nil
+}
+
+// VerifiedContract is a contract whose sources compile to the runtime code deployed
+// at its address, Height is the height the deployed code was queried at
+type VerifiedContract struct {
+	Address         sdk.AccAddress    `json:"address"`
+	ContractName    string            `json:"contract_name"`
+	CompilerVersion string            `json:"compiler_version"`
+	Settings        json.RawMessage   `json:"settings,omitempty"`
+	Sources         map[string]string `json:"sources"`
+	ABI             json.RawMessage   `json:"abi"`
+	Height          int64             `json:"height"`
+}
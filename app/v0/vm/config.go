@@ -6,5 +6,5 @@ package vm
 // that any network, identified by its genesis block, can have its own
 // set of configuration options.
 type ChainConfig struct {
-	ChainID string `json:"chainId"` // chainId identifies the current chain and is used for replay protection
+	ChainID uint64 `json:"chainId"` // chainId identifies the current chain and is used for replay protection
 }
@@ -43,10 +43,15 @@ var PrecompiledContracts = map[string]PrecompiledContract{
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
-	if contract.UseGas(gas) {
-		return p.Run(input)
+	if !contract.UseGas(gas) {
+		return nil, ErrOutOfGas
 	}
-	return nil, ErrOutOfGas
+
+	ret, err = p.Run(input)
+	if o, ok := p.(outputGasContract); ok && err == nil && !contract.UseGas(o.OutputGas(ret)) {
+		return nil, ErrOutOfGas
+	}
+	return ret, err
 }
 
 // ECRECOVER implemented as a native contract.
@@ -0,0 +1,513 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	ipaltypes "github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	stakingtypes "github.com/netcloth/netcloth-chain/app/v0/staking/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// Addresses of the NetCloth native contracts, reserved right after the Istanbul precompiles
+var (
+	CIPALContractAddress   = sdk.BytesToAddress([]byte{0x01, 0x00})
+	IPALContractAddress    = sdk.BytesToAddress([]byte{0x01, 0x01})
+	StakingContractAddress = sdk.BytesToAddress([]byte{0x01, 0x02})
+)
+
+// CIPALContractABI is the ABI of the read-only CIPAL native contract:
+//
+//	interface CIPAL {
+//	    struct ServiceInfo { uint64 serviceType; string serviceAddress; }
+//	    struct CIPALObject { string userAddress; ServiceInfo[] serviceInfos; }
+//	    function getCIPAL(address user) external view returns (bool found, CIPALObject memory cipal);
+//	    function getServiceAddress(address user, uint64 serviceType) external view returns (bool found, string memory serviceAddress);
+//	}
+const CIPALContractABI = `[
+	{"type":"function","name":"getCIPAL","stateMutability":"view",
+	 "inputs":[{"name":"user","type":"address"}],
+	 "outputs":[{"name":"found","type":"bool"},{"name":"cipal","type":"tuple","components":[
+		{"name":"userAddress","type":"string"},
+		{"name":"serviceInfos","type":"tuple[]","components":[{"name":"serviceType","type":"uint64"},{"name":"serviceAddress","type":"string"}]}]}]},
+	{"type":"function","name":"getServiceAddress","stateMutability":"view",
+	 "inputs":[{"name":"user","type":"address"},{"name":"serviceType","type":"uint64"}],
+	 "outputs":[{"name":"found","type":"bool"},{"name":"serviceAddress","type":"string"}]}
+]`
+
+// IPALContractABI is the ABI of the read-only IPAL native contract, bonds are in pnch:
+//
+//	interface IPAL {
+//	    struct Endpoint { uint64 endpointType; string endpoint; }
+//	    struct IPALNode { address operatorAddress; string moniker; string website; string details; string extension; Endpoint[] endpoints; uint256 bond; }
+//	    function getIPALNode(address operator) external view returns (bool found, IPALNode memory node);
+//	    function getIPALNodes() external view returns (IPALNode[] memory nodes);
+//	}
+const IPALContractABI = `[
+	{"type":"function","name":"getIPALNode","stateMutability":"view",
+	 "inputs":[{"name":"operator","type":"address"}],
+	 "outputs":[{"name":"found","type":"bool"},{"name":"node","type":"tuple","components":` + ipalNodeABIComponents + `}]},
+	{"type":"function","name":"getIPALNodes","stateMutability":"view",
+	 "inputs":[],
+	 "outputs":[{"name":"nodes","type":"tuple[]","components":` + ipalNodeABIComponents + `}]}
+]`
+
+const ipalNodeABIComponents = `[
+		{"name":"operatorAddress","type":"address"},
+		{"name":"moniker","type":"string"},
+		{"name":"website","type":"string"},
+		{"name":"details","type":"string"},
+		{"name":"extension","type":"string"},
+		{"name":"endpoints","type":"tuple[]","components":[{"name":"endpointType","type":"uint64"},{"name":"endpoint","type":"string"}]},
+		{"name":"bond","type":"uint256"}]`
+
+// StakingContractABI is the ABI of the staking native contract acting on behalf of its
+// caller, amounts are in pnch and shares are scaled by 1e18:
+//
+//	interface Staking {
+//	    function delegate(address validator, uint256 amount) external returns (uint256 shares);
+//	    function undelegate(address validator, uint256 amount) external returns (uint256 completionTime);
+//	    function redelegate(address validatorSrc, address validatorDst, uint256 amount) external returns (uint256 completionTime);
+//	    function withdrawRewards(address validator) external returns (uint256 amount);
+//	    function getDelegation(address delegator, address validator) external view returns (uint256 shares, uint256 balance);
+//	}
+const StakingContractABI = `[
+	{"type":"function","name":"delegate","stateMutability":"nonpayable",
+	 "inputs":[{"name":"validator","type":"address"},{"name":"amount","type":"uint256"}],
+	 "outputs":[{"name":"shares","type":"uint256"}]},
+	{"type":"function","name":"undelegate","stateMutability":"nonpayable",
+	 "inputs":[{"name":"validator","type":"address"},{"name":"amount","type":"uint256"}],
+	 "outputs":[{"name":"completionTime","type":"uint256"}]},
+	{"type":"function","name":"redelegate","stateMutability":"nonpayable",
+	 "inputs":[{"name":"validatorSrc","type":"address"},{"name":"validatorDst","type":"address"},{"name":"amount","type":"uint256"}],
+	 "outputs":[{"name":"completionTime","type":"uint256"}]},
+	{"type":"function","name":"withdrawRewards","stateMutability":"nonpayable",
+	 "inputs":[{"name":"validator","type":"address"}],
+	 "outputs":[{"name":"amount","type":"uint256"}]},
+	{"type":"function","name":"getDelegation","stateMutability":"view",
+	 "inputs":[{"name":"delegator","type":"address"},{"name":"validator","type":"address"}],
+	 "outputs":[{"name":"shares","type":"uint256"},{"name":"balance","type":"uint256"}]}
+]`
+
+var (
+	cipalContractABI   = mustParseABI(CIPALContractABI)
+	ipalContractABI    = mustParseABI(IPALContractABI)
+	stakingContractABI = mustParseABI(StakingContractABI)
+
+	errNativeContractInput       = errors.New("invalid native contract input")
+	errNativeContractUnavailable = errors.New("native contract unavailable")
+	errNativeContractCaller      = errors.New("native contract called through a delegate call or with value")
+	errNoDelegation              = errors.New("no delegation found")
+)
+
+// nativeContract is a NetCloth precompiled contract, bound to the EVM running it
+type nativeContract struct {
+	address     sdk.AccAddress
+	newContract func(evm *EVM) PrecompiledContract
+}
+
+// netclothContracts are the NetCloth precompiled contracts reading the state of the
+// NetCloth modules or acting on them on behalf of their caller. They are part of the
+// berlin and later fork rules, under the istanbul rules their addresses are plain
+// accounts.
+var netclothContracts = []nativeContract{
+	{CIPALContractAddress, func(evm *EVM) PrecompiledContract { return &cipalContract{evm: evm} }},
+	{IPALContractAddress, func(evm *EVM) PrecompiledContract { return &ipalContract{evm: evm} }},
+	{StakingContractAddress, func(evm *EVM) PrecompiledContract { return &stakingContract{evm: evm} }},
+}
+
+// outputGasContract is implemented by the precompiled contracts charging gas for the
+// data they return, once they have run
+type outputGasContract interface {
+	OutputGas(output []byte) uint64
+}
+
+// statefulContract is implemented by the precompiled contracts acting on behalf of
+// their caller, they are given the contract of the call before they run
+type statefulContract interface {
+	SetCall(contract *Contract, readOnly bool)
+}
+
+func mustParseABI(def string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// nativeMethod decodes the method of a call to a native contract
+func nativeMethod(contractABI abi.ABI, input []byte) (*abi.Method, error) {
+	if len(input) < 4 {
+		return nil, errNativeContractInput
+	}
+
+	method, err := contractABI.MethodById(input[:4])
+	if err != nil {
+		return nil, errNativeContractInput
+	}
+
+	return method, nil
+}
+
+// nativeCall decodes the method and the arguments of a call to a native contract
+func nativeCall(contractABI abi.ABI, input []byte) (*abi.Method, []interface{}, error) {
+	method, err := nativeMethod(contractABI, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %s", errNativeContractInput, err)
+	}
+
+	return method, args, nil
+}
+
+// nativeOutputGas is the gas charged for the data returned by a native contract
+func nativeOutputGas(output []byte) uint64 {
+	return uint64(len(output)+31) / 32 * NativeContractPerWordGas
+}
+
+type abiServiceInfo struct {
+	ServiceType    uint64
+	ServiceAddress string
+}
+
+type abiCIPALObject struct {
+	UserAddress  string
+	ServiceInfos []abiServiceInfo
+}
+
+// CIPAL lookups implemented as a native contract.
+type cipalContract struct {
+	evm *EVM
+}
+
+func (c *cipalContract) RequiredGas(input []byte) uint64 {
+	return NativeContractQueryGas
+}
+
+func (c *cipalContract) OutputGas(output []byte) uint64 {
+	return nativeOutputGas(output)
+}
+
+func (c *cipalContract) Run(input []byte) ([]byte, error) {
+	if c.evm.CIPALKeeper == nil {
+		return nil, errNativeContractUnavailable
+	}
+
+	method, args, err := nativeCall(cipalContractABI, input)
+	if err != nil {
+		return nil, err
+	}
+
+	user := sdk.AccAddress(args[0].(common.Address).Bytes())
+	obj, found := c.evm.CIPALKeeper.GetCIPALObject(c.evm.StateDB.Context(), user.String())
+
+	switch method.Name {
+	case "getCIPAL":
+		cipal := abiCIPALObject{UserAddress: obj.UserAddress, ServiceInfos: []abiServiceInfo{}}
+		for _, info := range obj.ServiceInfos {
+			cipal.ServiceInfos = append(cipal.ServiceInfos, abiServiceInfo{ServiceType: info.Type, ServiceAddress: info.Address})
+		}
+		return method.Outputs.Pack(found, cipal)
+
+	default: // getServiceAddress
+		serviceType := args[1].(uint64)
+		for _, info := range obj.ServiceInfos {
+			if info.Type == serviceType {
+				return method.Outputs.Pack(true, info.Address)
+			}
+		}
+		return method.Outputs.Pack(false, "")
+	}
+}
+
+type abiEndpoint struct {
+	EndpointType uint64
+	Endpoint     string
+}
+
+type abiIPALNode struct {
+	OperatorAddress common.Address
+	Moniker         string
+	Website         string
+	Details         string
+	Extension       string
+	Endpoints       []abiEndpoint
+	Bond            *big.Int
+}
+
+func newABIIPALNode(node ipaltypes.IPALNode, found bool) abiIPALNode {
+	n := abiIPALNode{
+		OperatorAddress: common.BytesToAddress(node.OperatorAddress),
+		Moniker:         node.Moniker,
+		Website:         node.Website,
+		Details:         node.Details,
+		Extension:       node.Extension,
+		Endpoints:       []abiEndpoint{},
+		Bond:            big.NewInt(0),
+	}
+	for _, endpoint := range node.Endpoints {
+		n.Endpoints = append(n.Endpoints, abiEndpoint{EndpointType: endpoint.Type, Endpoint: endpoint.Endpoint})
+	}
+	if found {
+		n.Bond = node.Bond.Amount.BigInt()
+	}
+
+	return n
+}
+
+// IPAL node lookups implemented as a native contract.
+type ipalContract struct {
+	evm *EVM
+}
+
+func (c *ipalContract) RequiredGas(input []byte) uint64 {
+	return NativeContractQueryGas
+}
+
+func (c *ipalContract) OutputGas(output []byte) uint64 {
+	return nativeOutputGas(output)
+}
+
+func (c *ipalContract) Run(input []byte) ([]byte, error) {
+	if c.evm.IPALKeeper == nil {
+		return nil, errNativeContractUnavailable
+	}
+
+	method, args, err := nativeCall(ipalContractABI, input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := c.evm.StateDB.Context()
+	switch method.Name {
+	case "getIPALNode":
+		operator := sdk.AccAddress(args[0].(common.Address).Bytes())
+		node, found := c.evm.IPALKeeper.GetIPALNode(ctx, operator)
+		return method.Outputs.Pack(found, newABIIPALNode(node, found))
+
+	default: // getIPALNodes
+		nodes := []abiIPALNode{}
+		for _, node := range c.evm.IPALKeeper.GetAllIPALNodes(ctx) {
+			nodes = append(nodes, newABIIPALNode(node, true))
+		}
+		return method.Outputs.Pack(nodes)
+	}
+}
+
+// sharesMultiplier scales the shares of a delegation to an integer
+var sharesMultiplier = sdk.NewIntWithDecimal(1, sdk.Precision)
+
+// Delegations of the caller implemented as a native contract. The changes to the
+// staking and distribution modules are journaled by the state db, reverting the call
+// discards them.
+type stakingContract struct {
+	evm      *EVM
+	contract *Contract
+	readOnly bool
+}
+
+func (c *stakingContract) SetCall(contract *Contract, readOnly bool) {
+	c.contract = contract
+	c.readOnly = readOnly
+}
+
+func (c *stakingContract) RequiredGas(input []byte) uint64 {
+	method, err := nativeMethod(stakingContractABI, input)
+	if err != nil {
+		return NativeContractQueryGas
+	}
+
+	switch method.Name {
+	case "getDelegation":
+		return NativeContractQueryGas
+	case "withdrawRewards":
+		return StakingContractRewardGas
+	default:
+		return StakingContractTxGas
+	}
+}
+
+func (c *stakingContract) Run(input []byte) ([]byte, error) {
+	if c.evm.StakingKeeper == nil || c.evm.DistributionKeeper == nil || c.contract == nil {
+		return nil, errNativeContractUnavailable
+	}
+
+	method, args, err := nativeCall(stakingContractABI, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if method.Name == "getDelegation" {
+		return c.getDelegation(method, args)
+	}
+
+	if c.readOnly {
+		return nil, ErrWriteProtection
+	}
+
+	// the caller is the delegator, it can't be borrowed by a delegate call and the
+	// contract has no use for the value sent to it
+	if !c.contract.Address().Equals(*c.contract.CodeAddr) || c.contract.Value().Sign() != 0 {
+		return nil, errNativeContractCaller
+	}
+
+	var ret []byte
+	delegator := c.contract.Caller()
+	err = c.evm.StateDB.RunNative(func(ctx sdk.Context) (err error) {
+		switch method.Name {
+		case "delegate":
+			ret, err = c.delegate(ctx, method, delegator, args)
+		case "undelegate":
+			ret, err = c.undelegate(ctx, method, delegator, args)
+		case "redelegate":
+			ret, err = c.redelegate(ctx, method, delegator, args)
+		default: // withdrawRewards
+			ret, err = c.withdrawRewards(ctx, method, delegator, args)
+		}
+		return
+	})
+
+	return ret, err
+}
+
+func (c *stakingContract) delegate(ctx sdk.Context, method *abi.Method, delegator sdk.AccAddress, args []interface{}) ([]byte, error) {
+	valAddr := abiValAddress(args[0])
+	amount, err := abiAmount(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	validator, found := c.evm.StakingKeeper.GetValidator(ctx, valAddr)
+	if !found {
+		return nil, stakingtypes.ErrNoValidatorFound
+	}
+
+	shares, err := c.evm.StakingKeeper.Delegate(ctx, delegator, amount, sdk.Unbonded, validator, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			stakingtypes.EventTypeDelegate,
+			sdk.NewAttribute(stakingtypes.AttributeKeyValidator, valAddr.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAmount, amount.String()),
+		),
+	)
+
+	return method.Outputs.Pack(shares.MulInt(sharesMultiplier).TruncateInt().BigInt())
+}
+
+func (c *stakingContract) undelegate(ctx sdk.Context, method *abi.Method, delegator sdk.AccAddress, args []interface{}) ([]byte, error) {
+	valAddr := abiValAddress(args[0])
+	amount, err := abiAmount(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := c.evm.StakingKeeper.ValidateUnbondAmount(ctx, delegator, valAddr, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	completionTime, err := c.evm.StakingKeeper.Undelegate(ctx, delegator, valAddr, shares)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			stakingtypes.EventTypeUnbond,
+			sdk.NewAttribute(stakingtypes.AttributeKeyValidator, valAddr.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAmount, amount.String()),
+			sdk.NewAttribute(stakingtypes.AttributeKeyCompletionTime, completionTime.Format(time.RFC3339)),
+		),
+	)
+
+	return method.Outputs.Pack(big.NewInt(completionTime.Unix()))
+}
+
+func (c *stakingContract) redelegate(ctx sdk.Context, method *abi.Method, delegator sdk.AccAddress, args []interface{}) ([]byte, error) {
+	valSrcAddr := abiValAddress(args[0])
+	valDstAddr := abiValAddress(args[1])
+	amount, err := abiAmount(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := c.evm.StakingKeeper.ValidateUnbondAmount(ctx, delegator, valSrcAddr, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	completionTime, err := c.evm.StakingKeeper.BeginRedelegation(ctx, delegator, valSrcAddr, valDstAddr, shares)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			stakingtypes.EventTypeRedelegate,
+			sdk.NewAttribute(stakingtypes.AttributeKeySrcValidator, valSrcAddr.String()),
+			sdk.NewAttribute(stakingtypes.AttributeKeyDstValidator, valDstAddr.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAmount, amount.String()),
+			sdk.NewAttribute(stakingtypes.AttributeKeyCompletionTime, completionTime.Format(time.RFC3339)),
+		),
+	)
+
+	return method.Outputs.Pack(big.NewInt(completionTime.Unix()))
+}
+
+func (c *stakingContract) withdrawRewards(ctx sdk.Context, method *abi.Method, delegator sdk.AccAddress, args []interface{}) ([]byte, error) {
+	rewards, err := c.evm.DistributionKeeper.WithdrawDelegationRewards(ctx, delegator, abiValAddress(args[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	return method.Outputs.Pack(rewards.AmountOf(c.evm.StakingKeeper.BondDenom(ctx)).BigInt())
+}
+
+func (c *stakingContract) getDelegation(method *abi.Method, args []interface{}) ([]byte, error) {
+	ctx := c.evm.StateDB.Context()
+	delegator := sdk.AccAddress(args[0].(common.Address).Bytes())
+	valAddr := abiValAddress(args[1])
+
+	delegation, found := c.evm.StakingKeeper.GetDelegation(ctx, delegator, valAddr)
+	if !found {
+		return method.Outputs.Pack(big.NewInt(0), big.NewInt(0))
+	}
+
+	validator, found := c.evm.StakingKeeper.GetValidator(ctx, valAddr)
+	if !found {
+		return nil, errNoDelegation
+	}
+
+	shares := delegation.GetShares()
+	balance := validator.TokensFromShares(shares).TruncateInt()
+	return method.Outputs.Pack(shares.MulInt(sharesMultiplier).TruncateInt().BigInt(), balance.BigInt())
+}
+
+func abiValAddress(arg interface{}) sdk.ValAddress {
+	return sdk.ValAddress(arg.(common.Address).Bytes())
+}
+
+// abiAmount converts a uint256 amount to a positive sdk.Int, which holds at most 255 bits
+func abiAmount(arg interface{}) (sdk.Int, error) {
+	amount := arg.(*big.Int)
+	if amount.Sign() <= 0 || amount.BitLen() > 255 {
+		return sdk.Int{}, errNativeContractInput
+	}
+
+	return sdk.NewIntFromBigInt(amount), nil
+}
@@ -0,0 +1,167 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	cipaltypes "github.com/netcloth/netcloth-chain/app/v0/cipal/types"
+	ipaltypes "github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+type mockCIPALKeeper map[string]cipaltypes.CIPALObject
+
+func (k mockCIPALKeeper) GetCIPALObject(_ sdk.Context, userAddress string) (cipaltypes.CIPALObject, bool) {
+	obj, found := k[userAddress]
+	return obj, found
+}
+
+type mockIPALKeeper ipaltypes.IPALNodes
+
+func (k mockIPALKeeper) GetIPALNode(_ sdk.Context, operator sdk.AccAddress) (ipaltypes.IPALNode, bool) {
+	for _, node := range k {
+		if node.OperatorAddress.Equals(operator) {
+			return node, true
+		}
+	}
+	return ipaltypes.IPALNode{}, false
+}
+
+func (k mockIPALKeeper) GetAllIPALNodes(_ sdk.Context) ipaltypes.IPALNodes {
+	return ipaltypes.IPALNodes(k)
+}
+
+func runNativeContract(t *testing.T, evm *EVM, addr sdk.AccAddress, input []byte) ([]byte, error) {
+	p := evm.precompile(addr)
+	require.NotNil(t, p)
+
+	contract := NewContract(AccountRef(sdk.HexToAddress("1337")), nil, new(big.Int), 1000000)
+	return RunPrecompiledContract(p, input, contract)
+}
+
+func TestCIPALContract(t *testing.T) {
+	user := sdk.AccAddress(common.BytesToAddress([]byte("user")).Bytes())
+	evm := newForkEVM(types.ForkBerlin)
+	evm.CIPALKeeper = mockCIPALKeeper{
+		user.String(): cipaltypes.NewCIPALObject(user.String(), "nch1serviceaddress", 1),
+	}
+
+	input, err := cipalContractABI.Pack("getServiceAddress", common.BytesToAddress(user), uint64(1))
+	require.NoError(t, err)
+	ret, err := runNativeContract(t, evm, CIPALContractAddress, input)
+	require.NoError(t, err)
+	out, err := cipalContractABI.Methods["getServiceAddress"].Outputs.UnpackValues(ret)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{true, "nch1serviceaddress"}, out)
+
+	input, err = cipalContractABI.Pack("getServiceAddress", common.BytesToAddress(user), uint64(2))
+	require.NoError(t, err)
+	ret, err = runNativeContract(t, evm, CIPALContractAddress, input)
+	require.NoError(t, err)
+	out, err = cipalContractABI.Methods["getServiceAddress"].Outputs.UnpackValues(ret)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{false, ""}, out)
+
+	input, err = cipalContractABI.Pack("getCIPAL", common.BytesToAddress(user))
+	require.NoError(t, err)
+	ret, err = runNativeContract(t, evm, CIPALContractAddress, input)
+	require.NoError(t, err)
+	out, err = cipalContractABI.Methods["getCIPAL"].Outputs.UnpackValues(ret)
+	require.NoError(t, err)
+	require.Equal(t, true, out[0])
+
+	_, err = runNativeContract(t, evm, CIPALContractAddress, []byte{0x01, 0x02})
+	require.Error(t, err)
+}
+
+func TestIPALContract(t *testing.T) {
+	operator := sdk.AccAddress(common.BytesToAddress([]byte("operator")).Bytes())
+	evm := newForkEVM(types.ForkBerlin)
+	evm.IPALKeeper = mockIPALKeeper{
+		ipaltypes.NewIPALNode(operator, "node", "https://netcloth.org", "details", "",
+			ipaltypes.Endpoints{ipaltypes.NewEndpoint(1, "192.168.1.1:1000")}, sdk.NewInt64Coin(sdk.NativeTokenName, 100)),
+	}
+
+	input, err := ipalContractABI.Pack("getIPALNodes")
+	require.NoError(t, err)
+	ret, err := runNativeContract(t, evm, IPALContractAddress, input)
+	require.NoError(t, err)
+	out, err := ipalContractABI.Methods["getIPALNodes"].Outputs.UnpackValues(ret)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+
+	input, err = ipalContractABI.Pack("getIPALNode", common.BytesToAddress([]byte("other")))
+	require.NoError(t, err)
+	ret, err = runNativeContract(t, evm, IPALContractAddress, input)
+	require.NoError(t, err)
+	out, err = ipalContractABI.Methods["getIPALNode"].Outputs.UnpackValues(ret)
+	require.NoError(t, err)
+	require.Equal(t, false, out[0])
+}
+
+func TestNativeContractUnavailable(t *testing.T) {
+	input, err := ipalContractABI.Pack("getIPALNodes")
+	require.NoError(t, err)
+	_, err = runNativeContract(t, newForkEVM(types.ForkBerlin), IPALContractAddress, input)
+	require.Equal(t, errNativeContractUnavailable, err)
+}
+
+func TestNativeContractsForkRules(t *testing.T) {
+	for _, addr := range []sdk.AccAddress{CIPALContractAddress, IPALContractAddress, StakingContractAddress} {
+		require.Nil(t, newEVM().precompile(addr))
+		require.NotContains(t, GetForkRules(types.ForkIstanbul).PrecompiledAddresses(), addr)
+
+		for _, fork := range []string{types.ForkBerlin, types.ForkLondon} {
+			require.NotNil(t, newForkEVM(fork).precompile(addr))
+			require.Contains(t, GetForkRules(fork).PrecompiledAddresses(), addr)
+		}
+	}
+}
+
+type mockStakingKeeper struct{ types.StakingKeeper }
+
+type mockDistributionKeeper struct{ types.DistributionKeeper }
+
+func TestStakingContractCaller(t *testing.T) {
+	evm := newForkEVM(types.ForkBerlin)
+	evm.StakingKeeper = mockStakingKeeper{}
+	evm.DistributionKeeper = mockDistributionKeeper{}
+
+	input, err := stakingContractABI.Pack("withdrawRewards", common.BytesToAddress([]byte("validator")))
+	require.NoError(t, err)
+
+	caller := AccountRef(sdk.HexToAddress("1337"))
+	run := func(self ContractRef, value *big.Int, readOnly bool) error {
+		p := evm.precompile(StakingContractAddress)
+		contract := NewContract(caller, self, value, 1000000)
+		contract.SetCallCode(&StakingContractAddress, sdk.Hash{}, nil)
+		p.(statefulContract).SetCall(contract, readOnly)
+		_, err := RunPrecompiledContract(p, input, contract)
+		return err
+	}
+
+	require.Equal(t, ErrWriteProtection, run(AccountRef(StakingContractAddress), new(big.Int), true))
+	require.Equal(t, errNativeContractCaller, run(caller, new(big.Int), false))
+	require.Equal(t, errNativeContractCaller, run(AccountRef(StakingContractAddress), big.NewInt(1), false))
+}
+
+func TestStakingContractAmountOverflow(t *testing.T) {
+	evm := newForkEVM(types.ForkBerlin)
+	evm.StakingKeeper = mockStakingKeeper{}
+	evm.DistributionKeeper = mockDistributionKeeper{}
+
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	for _, amount := range []*big.Int{maxUint256, new(big.Int).Lsh(big.NewInt(1), 255), new(big.Int)} {
+		input, err := stakingContractABI.Pack("delegate", common.BytesToAddress([]byte("validator")), amount)
+		require.NoError(t, err)
+
+		require.NotPanics(t, func() {
+			_, _, err = evm.Call(AccountRef(sdk.HexToAddress("1337")), StakingContractAddress, input, 1000000, new(big.Int))
+		})
+		require.Equal(t, errNativeContractInput, err)
+	}
+}
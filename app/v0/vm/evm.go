@@ -1,12 +1,14 @@
 package vm
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"time"
 
 	"github.com/tendermint/tendermint/crypto"
 
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
 	sdk "github.com/netcloth/netcloth-chain/types"
 )
 
@@ -17,15 +19,20 @@ type (
 	TransferFunc func(sdk.AccAddress, sdk.AccAddress, *big.Int)
 	// GetHashFunc returns the nth block hash in the blockchain
 	// and is used by the BLOCKHASH EVM op code.
-	//GetHashFunc func(sdk.Context) types.Hash
-	GetHashFunc func() sdk.Hash
+	GetHashFunc func(uint64) sdk.Hash
 )
 
-func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, error) {
+func run(evm *EVM, contract *Contract, input []byte, readOnly bool) (ret []byte, err error) {
+	defer func() {
+		evm.recordFailure(contract.Address(), ret, err)
+	}()
+
 	if contract.CodeAddr != nil {
-		precompiles := PrecompiledContracts
-		if p := precompiles[contract.CodeAddr.String()]; p != nil {
+		if p := evm.precompile(*contract.CodeAddr); p != nil {
 			fmt.Println("RunPrecompiledContract ...")
+			if s, ok := p.(statefulContract); ok {
+				s.SetCall(contract, readOnly)
+			}
 			return RunPrecompiledContract(p, input, contract)
 		}
 	}
@@ -78,6 +85,12 @@ type Context struct {
 	GasLimit    uint64
 	BlockNumber *big.Int
 	Time        *big.Int
+
+	// Keepers used by the NetCloth native contracts, the contracts fail when unset
+	CIPALKeeper        types.CIPALKeeper
+	IPALKeeper         types.IPALKeeper
+	StakingKeeper      types.StakingKeeper
+	DistributionKeeper types.DistributionKeeper
 }
 
 type EVM struct {
@@ -105,12 +118,22 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+
+	// failure is the last failed call frame, see recordFailure
+	failure *failure
+}
+
+// failure records the contract and the returned data of a failed call frame
+type failure struct {
+	contract sdk.AccAddress
+	ret      []byte
 }
 
-func NewEVM(ctx Context, statedb *CommitStateDB, vmConfig Config) *EVM {
+func NewEVM(ctx Context, statedb *CommitStateDB, chainConfig ChainConfig, vmConfig Config) *EVM {
 	evm := &EVM{
 		Context:      ctx,
 		StateDB:      statedb,
+		chainConfig:  chainConfig,
 		vmConfig:     vmConfig,
 		interpreters: make([]Interpreter, 0, 1),
 	}
@@ -120,6 +143,45 @@ func NewEVM(ctx Context, statedb *CommitStateDB, vmConfig Config) *EVM {
 	return evm
 }
 
+// precompile returns the precompiled contract at addr, nil if there is none. The
+// NetCloth native contracts of the fork rules are bound to the evm.
+func (evm *EVM) precompile(addr sdk.AccAddress) PrecompiledContract {
+	if p := PrecompiledContracts[addr.String()]; p != nil {
+		return p
+	}
+
+	if c := evm.vmConfig.Rules.nativeContract(addr); c != nil {
+		return c.newContract(evm)
+	}
+
+	return nil
+}
+
+// recordFailure keeps track of the contract a failure originates from. A frame
+// failing with the data returned by the last failed frame is taken to propagate its
+// failure, as contracts bubble up reverts by reverting with the returned data.
+func (evm *EVM) recordFailure(contract sdk.AccAddress, ret []byte, err error) {
+	if err == nil {
+		return
+	}
+
+	if evm.failure != nil && bytes.Equal(evm.failure.ret, ret) {
+		return
+	}
+
+	evm.failure = &failure{contract: contract, ret: ret}
+}
+
+// FailingContract returns the contract the failure of the execution originates
+// from, nil if no call frame failed
+func (evm *EVM) FailingContract() sdk.AccAddress {
+	if evm.failure == nil {
+		return nil
+	}
+
+	return evm.failure.contract
+}
+
 // Interpreter returns the current interpreter
 func (evm *EVM) Interpreter() Interpreter {
 	return evm.interpreter
@@ -128,10 +190,10 @@ func (evm *EVM) Interpreter() Interpreter {
 // Create creates a new contract using code as deployment code
 func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.Int) (ret []byte, contractAddr sdk.AccAddress, leftOverGas uint64, err error) {
 	contractAddr = CreateAddress(caller.Address(), evm.StateDB.GetNonce(caller.Address()))
-	return evm.create(caller, &codeAndHash{code: code}, gas, value, contractAddr)
+	return evm.create(caller, &codeAndHash{code: code}, gas, value, contractAddr, CREATE)
 }
 
-func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64, value *big.Int, address sdk.AccAddress) ([]byte, sdk.AccAddress, uint64, error) {
+func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64, value *big.Int, address sdk.AccAddress, typ OpCode) ([]byte, sdk.AccAddress, uint64, error) {
 	// Depth check execution. Fail if we're trying to execute above the limit
 	if evm.depth > evm.vmConfig.MaxCallCreateDepth {
 		return nil, sdk.AccAddress{}, gas, ErrDepth
@@ -154,6 +216,12 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		return nil, sdk.AccAddress{}, 0, ErrContractAddressCollision
 	}
 
+	// We add this to the access list _before_ taking a snapshot. Even if the creation fails,
+	// the access-list change should not be rolled back
+	if evm.vmConfig.Rules.IsBerlin {
+		evm.StateDB.AddAddressToAccessList(address)
+	}
+
 	// Create a new account on the state
 	snapshot := evm.StateDB.Snapshot()
 	evm.StateDB.CreateAccount(address)
@@ -169,9 +237,21 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		return nil, address, gas, nil
 	}
 
+	if evm.vmConfig.Debug {
+		if evm.depth == 0 {
+			evm.vmConfig.Tracer.CaptureStart(caller.Address(), address, true, codeAndHash.code, gas, value)
+		} else {
+			evm.vmConfig.Tracer.CaptureEnter(typ, caller.Address(), address, codeAndHash.code, gas, value)
+		}
+	}
+
 	start := time.Now()
 	ret, err := run(evm, contract, nil, false)
 	maxCodeSizeExceeded := len(ret) > int(evm.vmConfig.MaxCodeSize)
+	// Reject code starting with 0xEF if EIP-3541 is enabled
+	if err == nil && len(ret) >= 1 && ret[0] == 0xEF && evm.vmConfig.Rules.IsLondon {
+		err = ErrInvalidCode
+	}
 	if err == nil && !maxCodeSizeExceeded {
 		createGas := evm.vmConfig.ContractCreationGasConfig.Gas + uint64(len(ret))*evm.vmConfig.ContractCreationGasConfig.GasPerByte
 		if contract.UseGas(createGas) {
@@ -195,8 +275,12 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		err = ErrMaxCodeSizeExceeded
 	}
 
-	if evm.vmConfig.Debug && evm.depth == 0 {
-		evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
+	if evm.vmConfig.Debug {
+		if evm.depth == 0 {
+			evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
+		} else {
+			evm.vmConfig.Tracer.CaptureExit(ret, gas-contract.Gas, err)
+		}
 	}
 
 	if err == nil {
@@ -213,7 +297,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *big.Int, salt *big.Int) (ret []byte, contractAddr sdk.AccAddress, leftOverGas uint64, err error) {
 	codeAndHash := &codeAndHash{code: code}
 	contractAddr = CreateAddress2(caller.Address(), sdk.BigToHash(salt), codeAndHash.Hash().Bytes())
-	return evm.create(caller, codeAndHash, gas, endowment, contractAddr)
+	return evm.create(caller, codeAndHash, gas, endowment, contractAddr, CREATE2)
 }
 
 func (evm *EVM) Call(caller ContractRef, addr sdk.AccAddress, input []byte, gas uint64, value *big.Int) (ret []byte, leftOverGas uint64, err error) {
@@ -240,13 +324,16 @@ func (evm *EVM) Call(caller ContractRef, addr sdk.AccAddress, input []byte, gas
 		snapshot = evm.StateDB.Snapshot()
 	)
 	if !evm.StateDB.Exist(addr) {
-		precompiles := PrecompiledContracts
-
-		if precompiles[addr.String()] == nil && value.Sign() == 0 {
+		if evm.precompile(addr) == nil && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
-			if evm.vmConfig.Debug && evm.depth == 0 {
-				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
-				evm.vmConfig.Tracer.CaptureEnd(ret, 0, 0, nil)
+			if evm.vmConfig.Debug {
+				if evm.depth == 0 {
+					evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
+					evm.vmConfig.Tracer.CaptureEnd(ret, 0, 0, nil)
+				} else {
+					evm.vmConfig.Tracer.CaptureEnter(CALL, caller.Address(), addr, input, gas, value)
+					evm.vmConfig.Tracer.CaptureExit(ret, 0, nil)
+				}
 			}
 			return nil, gas, nil
 		}
@@ -258,12 +345,20 @@ func (evm *EVM) Call(caller ContractRef, addr sdk.AccAddress, input []byte, gas
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
 
 	start := time.Now()
-	if evm.vmConfig.Debug && evm.depth == 0 {
-		evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
+	if evm.vmConfig.Debug {
+		if evm.depth == 0 {
+			evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
 
-		defer func() {
-			evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
-		}()
+			defer func() {
+				evm.vmConfig.Tracer.CaptureEnd(ret, gas-contract.Gas, time.Since(start), err)
+			}()
+		} else {
+			evm.vmConfig.Tracer.CaptureEnter(CALL, caller.Address(), addr, input, gas, value)
+
+			defer func() {
+				evm.vmConfig.Tracer.CaptureExit(ret, gas-contract.Gas, err)
+			}()
+		}
 	}
 	ret, err = run(evm, contract, input, false)
 
@@ -306,6 +401,11 @@ func (evm *EVM) CallCode(caller ContractRef, addr sdk.AccAddress, input []byte,
 	contract := NewContract(caller, to, value, gas)
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
 
+	evm.captureEnter(CALLCODE, caller.Address(), addr, input, gas, value)
+	defer func() {
+		evm.captureExit(ret, gas-contract.Gas, err)
+	}()
+
 	ret, err = run(evm, contract, input, false)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
@@ -340,6 +440,11 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr sdk.AccAddress, input []by
 	contract := NewContract(caller, to, nil, gas).AsDelegate()
 	contract.SetCallCode(&addr, evm.StateDB.GetCodeHash(addr), evm.StateDB.GetCode(addr))
 
+	evm.captureEnter(DELEGATECALL, caller.Address(), addr, input, gas, nil)
+	defer func() {
+		evm.captureExit(ret, gas-contract.Gas, err)
+	}()
+
 	ret, err = run(evm, contract, input, false)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
@@ -382,6 +487,11 @@ func (evm *EVM) StaticCall(caller ContractRef, addr sdk.AccAddress, input []byte
 	// future scenarios
 	evm.StateDB.AddBalance(addr, bigZero)
 
+	evm.captureEnter(STATICCALL, caller.Address(), addr, input, gas, nil)
+	defer func() {
+		evm.captureExit(ret, gas-contract.Gas, err)
+	}()
+
 	// When an error was returned by the EVM or when setting the creation code
 	// above we revert to the snapshot and consume any gas remaining. Additionally
 	// when we're in Homestead this also counts for code storage gas errors.
@@ -394,3 +504,17 @@ func (evm *EVM) StaticCall(caller ContractRef, addr sdk.AccAddress, input []byte
 	}
 	return ret, contract.Gas, err
 }
+
+// captureEnter notifies the tracer of an internal call
+func (evm *EVM) captureEnter(typ OpCode, from sdk.AccAddress, to sdk.AccAddress, input []byte, gas uint64, value *big.Int) {
+	if evm.vmConfig.Debug && evm.depth > 0 {
+		evm.vmConfig.Tracer.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+// captureExit notifies the tracer of the end of an internal call
+func (evm *EVM) captureExit(output []byte, gasUsed uint64, err error) {
+	if evm.vmConfig.Debug && evm.depth > 0 {
+		evm.vmConfig.Tracer.CaptureExit(output, gasUsed, err)
+	}
+}
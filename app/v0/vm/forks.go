@@ -0,0 +1,210 @@
+package vm
+
+import (
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+var (
+	berlinInstructionSet = newBerlinInstructionSet()
+	londonInstructionSet = newLondonInstructionSet()
+)
+
+// eip2929Operations are the operations whose constant gas is set by EIP-2929 rather
+// than by the vm params, their cost depends on whether the state they access is warm
+var eip2929Operations = []OpCode{
+	SLOAD, SSTORE, BALANCE, EXTCODESIZE, EXTCODECOPY, EXTCODEHASH,
+	CALL, CALLCODE, DELEGATECALL, STATICCALL, SELFDESTRUCT,
+}
+
+// ForkRules are the EVM rules of a fork: the instruction set with its dynamic gas
+// functions, the operations whose constant gas the fork sets and the checks it adds
+// to the execution
+type ForkRules struct {
+	Name     string
+	IsBerlin bool // EIP-1344 CHAINID and EIP-2929 cold and warm state accesses
+	IsLondon bool // EIP-3529 refund reduction and EIP-3541 rejection of the code starting with 0xEF
+
+	jumpTable       *JumpTable
+	forkGasOps      []OpCode
+	nativeContracts []nativeContract
+}
+
+var forkRules = map[string]ForkRules{
+	types.ForkIstanbul: {
+		Name:      types.ForkIstanbul,
+		jumpTable: &istanbulInstructionSet,
+	},
+	types.ForkBerlin: {
+		Name:            types.ForkBerlin,
+		IsBerlin:        true,
+		jumpTable:       &berlinInstructionSet,
+		forkGasOps:      eip2929Operations,
+		nativeContracts: netclothContracts,
+	},
+	types.ForkLondon: {
+		Name:            types.ForkLondon,
+		IsBerlin:        true,
+		IsLondon:        true,
+		jumpTable:       &londonInstructionSet,
+		forkGasOps:      eip2929Operations,
+		nativeContracts: netclothContracts,
+	},
+}
+
+// GetForkRules returns the rules of the named fork, the Istanbul ones for an unknown name
+func GetForkRules(name string) ForkRules {
+	if rules, ok := forkRules[name]; ok {
+		return rules
+	}
+
+	return forkRules[types.ForkIstanbul]
+}
+
+// JumpTable returns the instruction set of the fork
+func (r ForkRules) JumpTable() JumpTable {
+	if r.jumpTable == nil {
+		return istanbulInstructionSet
+	}
+
+	return *r.jumpTable
+}
+
+// OpConstGas returns the constant gas of the operations under the fork: the one of
+// the vm params, but for the operations the fork sets the constant gas of
+func (r ForkRules) OpConstGas(params [256]uint64) *[256]uint64 {
+	jt := r.JumpTable()
+	for _, op := range r.forkGasOps {
+		params[op] = jt[op].constantGas
+	}
+
+	return &params
+}
+
+// RefundQuotient returns the quotient of the gas used capping the refund under the
+// fork: the refund_quotient vm param, at least 5 under the EIP-3529 rules. A zero
+// param keeps the refunds disabled.
+func (r ForkRules) RefundQuotient(quotient uint64) uint64 {
+	if r.IsLondon && quotient != 0 && quotient < RefundQuotientEIP3529 {
+		return RefundQuotientEIP3529
+	}
+
+	return quotient
+}
+
+// nativeContract returns the NetCloth native contract at addr under the fork, nil if there is none
+func (r ForkRules) nativeContract(addr sdk.AccAddress) *nativeContract {
+	for i := range r.nativeContracts {
+		if r.nativeContracts[i].address.Equals(addr) {
+			return &r.nativeContracts[i]
+		}
+	}
+
+	return nil
+}
+
+// PrecompiledAddresses returns the addresses of the precompiled contracts and of the
+// native contracts of the fork, warm from the start of the transactions executed
+// with the EIP-2929 rules
+func (r ForkRules) PrecompiledAddresses() []sdk.AccAddress {
+	addrs := append([]sdk.AccAddress{}, precompiledAddresses...)
+	for _, c := range r.nativeContracts {
+		addrs = append(addrs, c.address)
+	}
+
+	return addrs
+}
+
+// precompiledAddresses are the addresses of the precompiled contracts of every fork
+var precompiledAddresses = []sdk.AccAddress{
+	sdk.BytesToAddress([]byte{1}),
+	sdk.BytesToAddress([]byte{2}),
+	sdk.BytesToAddress([]byte{3}),
+	sdk.BytesToAddress([]byte{4}),
+	sdk.BytesToAddress([]byte{5}),
+	sdk.BytesToAddress([]byte{6}),
+	sdk.BytesToAddress([]byte{7}),
+	sdk.BytesToAddress([]byte{8}),
+	sdk.BytesToAddress([]byte{9}),
+}
+
+// accessListGas returns the intrinsic gas of an EIP-2930 access list, charged
+// before the execution for pre-warming its accounts and storage slots
+func accessListGas(list types.AccessList) uint64 {
+	return uint64(len(list))*TxAccessListAddressGas + uint64(list.StorageKeys())*TxAccessListStorageKeyGas
+}
+
+// newBerlinInstructionSet returns the Istanbul instruction set with the EIP-2929 gas
+// functions, the CHAINID opcode and the BLOCKHASH of the last 256 blocks
+func newBerlinInstructionSet() JumpTable {
+	instructionSet := newIstanbulInstructionSet()
+	enable1344(&instructionSet)
+	enable2929(&instructionSet)
+	enableBlockhashWindow(&instructionSet)
+	return instructionSet
+}
+
+// newLondonInstructionSet returns the Berlin instruction set with the EIP-3529 refunds
+func newLondonInstructionSet() JumpTable {
+	instructionSet := newBerlinInstructionSet()
+	enable3529(&instructionSet)
+	return instructionSet
+}
+
+// enable1344 enables "EIP-1344: ChainID opcode", returning the evm_chain_id vm param.
+// It is invalid under the Istanbul rules, as it was before the param existed.
+// https://eips.ethereum.org/EIPS/eip-1344
+func enable1344(jt *JumpTable) {
+	jt[CHAINID].valid = true
+}
+
+// enableBlockhashWindow makes BLOCKHASH return the hash of any of the last 256 blocks,
+// and zero out of them, as in Ethereum. Under the Istanbul rules it returns the hash
+// of the previous block whatever the block number asked.
+func enableBlockhashWindow(jt *JumpTable) {
+	jt[BLOCKHASH].execute = opBlockhash
+}
+
+// enable2929 enables "EIP-2929: Gas cost increases for state access opcodes"
+// https://eips.ethereum.org/EIPS/eip-2929
+func enable2929(jt *JumpTable) {
+	jt[SSTORE].dynamicGas = gasSStoreEIP2929
+
+	jt[SLOAD].constantGas = 0
+	jt[SLOAD].dynamicGas = gasSLoadEIP2929
+
+	jt[EXTCODECOPY].constantGas = WarmStorageReadCostEIP2929
+	jt[EXTCODECOPY].dynamicGas = gasExtCodeCopyEIP2929
+
+	jt[EXTCODESIZE].constantGas = WarmStorageReadCostEIP2929
+	jt[EXTCODESIZE].dynamicGas = gasEip2929AccountCheck
+
+	jt[EXTCODEHASH].constantGas = WarmStorageReadCostEIP2929
+	jt[EXTCODEHASH].dynamicGas = gasEip2929AccountCheck
+
+	jt[BALANCE].constantGas = WarmStorageReadCostEIP2929
+	jt[BALANCE].dynamicGas = gasEip2929AccountCheck
+
+	jt[CALL].constantGas = WarmStorageReadCostEIP2929
+	jt[CALL].dynamicGas = gasCallEIP2929
+
+	jt[CALLCODE].constantGas = WarmStorageReadCostEIP2929
+	jt[CALLCODE].dynamicGas = gasCallCodeEIP2929
+
+	jt[STATICCALL].constantGas = WarmStorageReadCostEIP2929
+	jt[STATICCALL].dynamicGas = gasStaticCallEIP2929
+
+	jt[DELEGATECALL].constantGas = WarmStorageReadCostEIP2929
+	jt[DELEGATECALL].dynamicGas = gasDelegateCallEIP2929
+
+	// the SELFDESTRUCT cost stays in its dynamic gas, as in the Istanbul rules
+	jt[SELFDESTRUCT].constantGas = 0
+	jt[SELFDESTRUCT].dynamicGas = gasSelfdestructEIP2929
+}
+
+// enable3529 enables "EIP-3529: Reduction in refunds"
+// https://eips.ethereum.org/EIPS/eip-3529
+func enable3529(jt *JumpTable) {
+	jt[SSTORE].dynamicGas = gasSStoreEIP3529
+	jt[SELFDESTRUCT].dynamicGas = gasSelfdestructEIP3529
+}
@@ -0,0 +1,126 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestForkRulesOpConstGas(t *testing.T) {
+	istanbul := GetForkRules(types.ForkIstanbul).OpConstGas(types.DefaultVMOpGasParams)
+	require.Equal(t, types.DefaultVMOpGasParams, *istanbul)
+
+	berlin := GetForkRules(types.ForkBerlin).OpConstGas(types.DefaultVMOpGasParams)
+	require.Equal(t, uint64(0), berlin[SLOAD])
+	require.Equal(t, WarmStorageReadCostEIP2929, berlin[CALL])
+	require.Equal(t, WarmStorageReadCostEIP2929, berlin[BALANCE])
+	require.Equal(t, types.DefaultVMOpGasParams[ADD], berlin[ADD])
+
+	require.Equal(t, *berlin, *GetForkRules(types.ForkLondon).OpConstGas(types.DefaultVMOpGasParams))
+	require.Equal(t, types.ForkIstanbul, GetForkRules("unknown").Name)
+}
+
+func TestForkRulesRefundQuotient(t *testing.T) {
+	require.Equal(t, uint64(2), GetForkRules(types.ForkBerlin).RefundQuotient(2))
+	require.Equal(t, uint64(5), GetForkRules(types.ForkLondon).RefundQuotient(2))
+	require.Equal(t, uint64(10), GetForkRules(types.ForkLondon).RefundQuotient(10))
+	require.Equal(t, uint64(0), GetForkRules(types.ForkLondon).RefundQuotient(0))
+}
+
+func TestGasSLoadEIP2929(t *testing.T) {
+	evm := newEVM()
+	addr := sdk.BytesToAddress([]byte("contract"))
+	evm.StateDB.PrepareAccessList(sdk.HexToAddress("1337"), addr, precompiledAddresses, nil)
+	contract := NewContract(AccountRef(sdk.HexToAddress("1337")), AccountRef(addr), new(big.Int), 100000)
+
+	sload := func() uint64 {
+		stack := newstack()
+		stack.push(big.NewInt(1))
+		gas, err := gasSLoadEIP2929(evm, contract, stack, NewMemory(), 0)
+		require.NoError(t, err)
+		return gas
+	}
+
+	snapshot := evm.StateDB.Snapshot()
+	require.Equal(t, ColdSloadCostEIP2929, sload())
+	require.Equal(t, WarmStorageReadCostEIP2929, sload())
+
+	// the access is cold again once reverted
+	evm.StateDB.RevertToSnapshot(snapshot)
+	require.Equal(t, ColdSloadCostEIP2929, sload())
+}
+
+func TestGasEip2929AccountCheck(t *testing.T) {
+	evm := newEVM()
+	evm.StateDB.PrepareAccessList(sdk.HexToAddress("1337"), nil, GetForkRules(types.ForkBerlin).PrecompiledAddresses(), nil)
+	contract := NewContract(AccountRef(sdk.HexToAddress("1337")), AccountRef(sdk.HexToAddress("1337")), new(big.Int), 100000)
+
+	balance := func(addr sdk.AccAddress) uint64 {
+		stack := newstack()
+		stack.push(new(big.Int).SetBytes(addr))
+		gas, err := gasEip2929AccountCheck(evm, contract, stack, NewMemory(), 0)
+		require.NoError(t, err)
+		return gas
+	}
+
+	require.Equal(t, uint64(0), balance(StakingContractAddress))
+	require.Equal(t, ColdAccountAccessCostEIP2929-WarmStorageReadCostEIP2929, balance(sdk.HexToAddress("beef")))
+	require.Equal(t, uint64(0), balance(sdk.HexToAddress("beef")))
+}
+
+func newForkEVM(fork string) *EVM {
+	rules := GetForkRules(fork)
+	ctx := Context{
+		CanTransfer: func(sdk.AccAddress, *big.Int) bool { return true },
+		Transfer:    func(sdk.AccAddress, sdk.AccAddress, *big.Int) {},
+	}
+	cfg := Config{
+		Rules:                     rules,
+		OpConstGasConfig:          rules.OpConstGas(types.DefaultVMOpGasParams),
+		ContractCreationGasConfig: &types.VMContractCreationGasParams{Gas: 53000, GasPerByte: 200},
+		MaxCodeSize:               MaxCodeSize,
+		MaxCallCreateDepth:        CallCreateDepth,
+	}
+	return NewEVM(ctx, newEVM().StateDB, ChainConfig{}, cfg)
+}
+
+func TestCreateEIP3541(t *testing.T) {
+	// init code returning the code 0xef
+	code := sdk.FromHex("60ef60005360016000f3")
+	sender := AccountRef(sdk.HexToAddress("1337"))
+
+	_, _, _, err := newForkEVM(types.ForkBerlin).Create(sender, code, 100000, new(big.Int))
+	require.NoError(t, err)
+
+	_, _, leftOverGas, err := newForkEVM(types.ForkLondon).Create(sender, code, 100000, new(big.Int))
+	require.Equal(t, ErrInvalidCode, err)
+	require.Equal(t, uint64(0), leftOverGas)
+}
+
+func TestBlockhashForks(t *testing.T) {
+	blockhash := func(fork string, num byte) sdk.Hash {
+		evm := newForkEVM(fork)
+		evm.BlockNumber = big.NewInt(300)
+		evm.GetHash = func(n uint64) sdk.Hash {
+			return sdk.BigToHash(new(big.Int).SetUint64(n))
+		}
+
+		// init code returning BLOCKHASH(num) as the code of the contract
+		code := append([]byte{byte(PUSH1), num}, sdk.FromHex("4060005260206000f3")...)
+		ret, _, _, err := evm.Create(AccountRef(sdk.HexToAddress("1337")), code, 100000, new(big.Int))
+		require.NoError(t, err)
+		return sdk.BytesToHash(ret)
+	}
+
+	// the hash of the previous block whatever the number before the berlin fork
+	require.Equal(t, sdk.BigToHash(big.NewInt(299)), blockhash(types.ForkIstanbul, 43))
+	require.Equal(t, sdk.BigToHash(big.NewInt(299)), blockhash(types.ForkIstanbul, 200))
+
+	require.Equal(t, sdk.Hash{}, blockhash(types.ForkBerlin, 43))
+	require.Equal(t, sdk.BigToHash(big.NewInt(200)), blockhash(types.ForkBerlin, 200))
+	require.Equal(t, sdk.BigToHash(big.NewInt(200)), blockhash(types.ForkLondon, 200))
+}
@@ -1,6 +1,15 @@
 package vm
 
-import "testing"
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	keep "github.com/netcloth/netcloth-chain/app/v0/vm/keeper"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	"github.com/netcloth/netcloth-chain/hexutil"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
 
 func TestMemoryGasCost(t *testing.T) {
 	tests := []struct {
@@ -21,3 +30,90 @@ func TestMemoryGasCost(t *testing.T) {
 		}
 	}
 }
+
+var eip2200Tests = []struct {
+	original byte
+	gaspool  uint64
+	input    string
+	used     uint64
+	refund   uint64
+}{
+	{0, math.MaxUint64, "0x60006000556000600055", 1612, 0},                // 0 -> 0 -> 0
+	{0, math.MaxUint64, "0x60006000556001600055", 20812, 0},               // 0 -> 0 -> 1
+	{0, math.MaxUint64, "0x60016000556000600055", 20812, 19200},           // 0 -> 1 -> 0
+	{0, math.MaxUint64, "0x60016000556002600055", 20812, 0},               // 0 -> 1 -> 2
+	{0, math.MaxUint64, "0x60016000556001600055", 20812, 0},               // 0 -> 1 -> 1
+	{1, math.MaxUint64, "0x60006000556000600055", 5812, 15000},            // 1 -> 0 -> 0
+	{1, math.MaxUint64, "0x60006000556001600055", 5812, 4200},             // 1 -> 0 -> 1
+	{1, math.MaxUint64, "0x60006000556002600055", 5812, 0},                // 1 -> 0 -> 2
+	{1, math.MaxUint64, "0x60026000556000600055", 5812, 15000},            // 1 -> 2 -> 0
+	{1, math.MaxUint64, "0x60026000556003600055", 5812, 0},                // 1 -> 2 -> 3
+	{1, math.MaxUint64, "0x60026000556001600055", 5812, 4200},             // 1 -> 2 -> 1
+	{1, math.MaxUint64, "0x60026000556002600055", 5812, 0},                // 1 -> 2 -> 2
+	{1, math.MaxUint64, "0x60016000556000600055", 5812, 15000},            // 1 -> 1 -> 0
+	{1, math.MaxUint64, "0x60016000556002600055", 5812, 0},                // 1 -> 1 -> 2
+	{1, math.MaxUint64, "0x60016000556001600055", 1612, 0},                // 1 -> 1 -> 1
+	{0, math.MaxUint64, "0x600160005560006000556001600055", 40818, 19200}, // 0 -> 1 -> 0 -> 1
+	{1, math.MaxUint64, "0x600060005560016000556000600055", 10818, 19200}, // 1 -> 0 -> 1 -> 0
+	{1, 2306, "0x6001600055", 2306, 0},                                    // 1 -> 1 (2300 sentry + 2xPUSH)
+	{1, 2307, "0x6001600055", 806, 0},                                     // 1 -> 1 (2301 sentry + 2xPUSH)
+}
+
+// TestEIP2200 checks the gas used and refunded by SSTORE against the test vectors of EIP-2200
+func TestEIP2200(t *testing.T) {
+	for i, tt := range eip2200Tests {
+		ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+
+		address := sdk.BytesToAddress([]byte("contract"))
+		stateDB := types.NewStateDB(vmKeeper.StateDB).WithContext(ctx)
+		stateDB.CreateAccount(address)
+		stateDB.SetCode(address, hexutil.MustDecode(tt.input))
+		stateDB.SetState(address, sdk.Hash{}, sdk.BytesToHash([]byte{tt.original}))
+		stateDB.Finalise(true) // Push the state into the "original" slot
+
+		st := StateTransition{StateDB: stateDB}
+		evmCtx := Context{CanTransfer: st.CanTransfer, Transfer: st.Transfer, GetHash: st.GetHashFn(ctx.BlockHeader()), BlockNumber: big.NewInt(1), Time: big.NewInt(0)}
+		params := types.DefaultParams()
+		cfg := Config{
+			OpConstGasConfig:          &params.VMOpGasParams,
+			ContractCreationGasConfig: &params.VMContractCreationGasParams,
+			MaxCodeSize:               params.MaxCodeSize,
+			MaxCallCreateDepth:        params.MaxCallCreateDepth,
+		}
+		vmenv := NewEVM(evmCtx, stateDB, ChainConfig{}, cfg)
+
+		gaspool := tt.gaspool
+		if gaspool == math.MaxUint64 {
+			gaspool = DefaultVMGasLimit
+		}
+		_, gas, err := vmenv.Call(sdk.AccAddress(make([]byte, sdk.AddrLen)), address, nil, gaspool, new(big.Int))
+		if err != nil && tt.gaspool == math.MaxUint64 {
+			t.Errorf("test %d: execution failed: %v", i, err)
+		}
+		if used := gaspool - gas; used != tt.used {
+			t.Errorf("test %d: gas used mismatch: have %v, want %v", i, used, tt.used)
+		}
+		if refund := stateDB.GetRefund(); refund != tt.refund {
+			t.Errorf("test %d: gas refund mismatch: have %v, want %v", i, refund, tt.refund)
+		}
+	}
+}
+
+func TestRefundGas(t *testing.T) {
+	tests := []struct {
+		refundCounter, gasUsed, vmGasUsed, quotient uint64
+		refund                                      uint64
+	}{
+		{15000, 60000, 30000, 2, 15000},
+		{15000, 20000, 10000, 2, 10000},
+		{15000, 20000, 5000, 2, 5000},
+		{15000, 60000, 30000, 5, 12000},
+		{15000, 60000, 30000, 0, 0},
+		{0, 60000, 30000, 2, 0},
+	}
+	for i, tt := range tests {
+		if refund := refundGas(tt.refundCounter, tt.gasUsed, tt.vmGasUsed, tt.quotient); refund != tt.refund {
+			t.Errorf("test %d: refund mismatch: have %v, want %v", i, refund, tt.refund)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package vm
 
 import (
+	"github.com/tendermint/tendermint/crypto/tmhash"
+
 	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
 	sdk "github.com/netcloth/netcloth-chain/types"
 	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
@@ -13,24 +15,39 @@ func NewHandler(k Keeper) sdk.Handler {
 
 		switch msg := msg.(type) {
 		case MsgContract:
-			return handleMsgContract(ctx, msg, k)
+			return handleMsgContract(ctx, msg, k, nil)
 		default:
 			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized %s message type: %T", ModuleName, msg)
 		}
 	}
 }
 
-func handleMsgContract(ctx sdk.Context, msg MsgContract, k Keeper) (*sdk.Result, error) {
+// handleMsgContract runs the contract call or creation of msg, traced by tracer if not nil
+func handleMsgContract(ctx sdk.Context, msg MsgContract, k Keeper, tracer Tracer) (*sdk.Result, error) {
 	err := msg.ValidateBasic()
 	if err != nil {
 		return nil, err
 	}
 
-	_, res, err := DoStateTransition(ctx, msg, k, ctx.Simulate)
+	// Ethereum clients look a tx up by its Ethereum hash, indexed by Tendermint along
+	// with the events of the tx, whether it succeeds or fails
+	ethTxEvents := sdk.EmptyEvents()
+	if hash, ok := types.EthereumTxHash(ctx.TxBytes()); ok {
+		ethTxEvents = ethTxEvents.AppendEvent(
+			sdk.NewEvent(
+				types.EventTypeEthereumTx,
+				sdk.NewAttribute(types.AttributeKeyHash, hash.Hex()),
+			),
+		)
+	}
+
+	_, res, err := doStateTransition(ctx, msg, k, ctx.Simulate, tracer)
 	if err != nil {
-		return &sdk.Result{Data: res.Data, GasUsed: res.GasUsed}, err
+		return &sdk.Result{Data: res.Data, GasUsed: res.GasUsed, Events: res.Events.AppendEvents(ethTxEvents)}, err
 	}
 
+	ctx.EventManager().EmitEvents(ethTxEvents)
+
 	ctx.EventManager().EmitEvent(
 		sdk.NewEvent(
 			sdk.EventTypeMessage,
@@ -38,5 +55,16 @@ func handleMsgContract(ctx sdk.Context, msg MsgContract, k Keeper) (*sdk.Result,
 		),
 	)
 
+	// the logs bloom of the tx goes with the events, the result data stays the return value of the contract
+	bloom := types.LogsBloom(k.GetLogs(ctx, sdk.BytesToHash(tmhash.Sum(ctx.TxBytes()))))
+	if !bloom.Empty() {
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeLogsBloom,
+				sdk.NewAttribute(types.AttributeKeyBloom, bloom.String()),
+			),
+		)
+	}
+
 	return &sdk.Result{Data: res.Data, GasUsed: res.GasUsed, Events: ctx.EventManager().Events()}, nil
 }
@@ -2,15 +2,22 @@ package vm
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"testing"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto/tmhash"
 
+	guardiantypes "github.com/netcloth/netcloth-chain/app/v0/guardian/types"
 	"github.com/netcloth/netcloth-chain/app/v0/vm/common"
 	keep "github.com/netcloth/netcloth-chain/app/v0/vm/keeper"
 	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
@@ -91,3 +98,198 @@ func TestMsgContractCreateAndCall(t *testing.T) {
 	}
 
 }
+
+func TestMsgContractRevertReason(t *testing.T) {
+	ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	handler := NewHandler(vmKeeper)
+
+	// reverts with Error("Insufficient allowance") whatever the input
+	revertData := "08c379a000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000016496e73756666696369656e7420616c6c6f77616e636500000000000000000000"
+	reverter := "607080600b6000396000f36064600c60003960646000fd" + revertData
+
+	reverterAddr := CreateAddress(keep.Addrs[0], 0)
+	_, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, sdk.FromHex(reverter), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+
+	// calls the reverter and reverts with its returned data
+	proxy := "602c80600b6000396000f36000600060006000600073" + common.Bytes2Hex(reverterAddr) + "5af1503d600060003e3d6000fd"
+	proxyAddr := CreateAddress(keep.Addrs[1], 0)
+	_, err = handler(ctx, types.NewMsgContract(keep.Addrs[1], nil, sdk.FromHex(proxy), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	for _, to := range []sdk.AccAddress{reverterAddr, proxyAddr} {
+		res, err := handler(ctx, types.NewMsgContract(keep.Addrs[2], to, sdk.FromHex("a9059cbb"), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+		require.Error(t, err)
+		require.True(t, errors.Is(err, types.ErrExecutionReverted))
+
+		execErr, ok := err.(*types.ExecutionError)
+		require.True(t, ok)
+		require.True(t, execErr.Reverted())
+		require.Equal(t, "Insufficient allowance", execErr.Reason)
+		require.Equal(t, revertData, common.Bytes2Hex(execErr.Data))
+		require.Equal(t, reverterAddr, execErr.Contract)
+		require.NotZero(t, execErr.GasUsed)
+		require.Contains(t, err.Error(), "Insufficient allowance")
+
+		require.Len(t, res.Events, 1)
+		require.Equal(t, types.EventTypeExecutionError, res.Events[0].Type)
+	}
+
+	// the call path reports the failure in its result, estimate_gas fails
+	querier := NewQuerier(vmKeeper)
+	data := vmKeeper.Cdc.MustMarshalJSON(types.NewMsgContractQuery(keep.Addrs[2], proxyAddr, sdk.FromHex("a9059cbb"), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+
+	bz, err := querier(ctx, []string{types.QueryCall}, abci.RequestQuery{Data: data})
+	require.NoError(t, err)
+	var out types.SimulationResult
+	vmKeeper.Cdc.MustUnmarshalJSON(bz, &out)
+	require.NotNil(t, out.Err)
+	require.True(t, out.Err.Reverted())
+	require.Equal(t, "Insufficient allowance", out.Err.Reason)
+	require.Equal(t, reverterAddr, out.Err.Contract)
+	require.Equal(t, revertData, out.Res)
+
+	_, err = querier(ctx, []string{types.EstimateGas}, abci.RequestQuery{Data: data})
+	require.True(t, errors.Is(err, types.ErrExecutionReverted))
+}
+
+func TestMsgContractEthereumTxHash(t *testing.T) {
+	ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	handler := NewHandler(vmKeeper)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx, err := ethtypes.SignTx(ethtypes.NewTransaction(0, ethcommon.Address{}, big.NewInt(0), 100000, big.NewInt(1), nil), ethtypes.HomesteadSigner{}, key)
+	require.NoError(t, err)
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+
+	ethTxEvent := func(events sdk.Events) string {
+		for _, event := range events {
+			if event.Type == types.EventTypeEthereumTx {
+				return string(event.Attributes[0].Value)
+			}
+		}
+		return ""
+	}
+
+	// reverts whatever the input
+	reverter := "600580600b6000396000f360006000fd"
+	reverterAddr := CreateAddress(keep.Addrs[0], 0)
+	res, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, sdk.FromHex(reverter), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	require.Empty(t, ethTxEvent(res.Events))
+	EndBlocker(ctx, vmKeeper)
+
+	// the Ethereum hash is the one clients compute, for succeeding and failing txs
+	ethCtx := ctx.WithTxBytes(txBytes)
+	res, err = handler(ethCtx, types.NewMsgContract(keep.Addrs[1], keep.Addrs[2], sdk.FromHex("a9059cbb"), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	require.Equal(t, common.Bytes2Hex(tx.Hash().Bytes()), ethTxEvent(res.Events))
+
+	res, err = handler(ethCtx, types.NewMsgContract(keep.Addrs[1], reverterAddr, sdk.FromHex("a9059cbb"), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.Error(t, err)
+	require.Equal(t, common.Bytes2Hex(tx.Hash().Bytes()), ethTxEvent(res.Events))
+}
+
+func TestMsgContractGasRefund(t *testing.T) {
+	// stores 1 in slot 0 at creation, clears slot 0 when called
+	clearer := "600160005560068060106000396000f3" + "600060005500"
+
+	callGasUsed := func(refundQuotient uint64, fork string) uint64 {
+		ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+		vmKeeper.SetRefundQuotient(ctx, refundQuotient)
+		vmKeeper.SetEVMFork(ctx, fork)
+		handler := NewHandler(vmKeeper)
+
+		_, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, sdk.FromHex(clearer), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+		require.NoError(t, err)
+		EndBlocker(ctx, vmKeeper)
+
+		msg := types.NewMsgContract(keep.Addrs[1], CreateAddress(keep.Addrs[0], 0), sdk.FromHex("00"), sdk.NewInt64Coin(sdk.NativeTokenName, 0))
+		res, err := handler(ctx.WithGasMeter(sdk.NewGasMeter(1000000)), msg)
+		require.NoError(t, err)
+		return res.GasUsed
+	}
+
+	// PUSH1, PUSH1, SSTORE of a clean slot, the clear refund is capped at the vm gas used
+	vmGasUsed := uint64(3 + 3 + 5000)
+
+	gasUsed := callGasUsed(0, types.ForkIstanbul)
+	require.Equal(t, gasUsed-vmGasUsed, callGasUsed(2, types.ForkIstanbul))
+
+	// the refund is capped at gas used / quotient
+	require.Equal(t, gasUsed-gasUsed/5, callGasUsed(5, types.ForkIstanbul))
+
+	// the london rules cap the refund at gas used / 5 at least
+	londonGasUsed := callGasUsed(0, types.ForkLondon)
+	require.Equal(t, londonGasUsed-londonGasUsed/5, callGasUsed(2, types.ForkLondon))
+}
+
+type mockGuardianKeeper map[string]bool
+
+func (k mockGuardianKeeper) GetDeployer(_ sdk.Context, addr sdk.AccAddress) (guardiantypes.Guardian, bool) {
+	return guardiantypes.Guardian{Address: addr}, k[addr.String()]
+}
+
+func TestMsgContractDeployPermission(t *testing.T) {
+	ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	deployers := mockGuardianKeeper{keep.Addrs[0].String(): true}
+	vmKeeper.GuardianKeeper = deployers
+	vmKeeper.SetDeployPermission(ctx, types.DeployPermissionGuardianWhitelist)
+	handler := NewHandler(vmKeeper)
+
+	code := sdk.FromHex("600160005560068060106000396000f3" + "600060005500")
+	_, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, code, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	_, err = handler(ctx, types.NewMsgContract(keep.Addrs[1], nil, code, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.True(t, errors.Is(err, types.ErrDeployNotPermitted))
+
+	// anyone can still call contracts
+	_, err = handler(ctx, types.NewMsgContract(keep.Addrs[1], CreateAddress(keep.Addrs[0], 0), sdk.FromHex("00"), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+
+	vmKeeper.SetDeployPermission(ctx, types.DeployPermissionOpen)
+	_, err = handler(ctx, types.NewMsgContract(keep.Addrs[1], nil, code, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+}
+
+func TestMsgContractEVMForks(t *testing.T) {
+	ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	handler := NewHandler(vmKeeper)
+
+	// runtime code reading the slot 0 twice
+	code := sdk.FromHex("6007600c60003960076000f3" + "60005460005400")
+	vmKeeper.SetEVMFork(ctx, types.ForkBerlin)
+	_, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, code, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	vmKeeper.SetEVMFork(ctx, types.ForkLondon)
+	_, err = handler(ctx, types.NewMsgContract(keep.Addrs[1], CreateAddress(keep.Addrs[0], 0), sdk.FromHex("00"), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+}
+
+func TestMsgContractAccessList(t *testing.T) {
+	ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	handler := NewHandler(vmKeeper)
+
+	// runtime code reading the slot 0
+	code := sdk.FromHex("6004600c60003960046000f3" + "60005400")
+	_, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, code, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	msg := types.NewMsgContract(keep.Addrs[1], CreateAddress(keep.Addrs[0], 0), sdk.FromHex("00"), sdk.NewInt64Coin(sdk.NativeTokenName, 0))
+	msg.AccessList = types.AccessList{{Address: msg.To, StorageKeys: []sdk.Hash{{}}}}
+	_, err = handler(ctx, msg)
+	require.True(t, types.ErrAccessListNotSupported.Is(err))
+
+	vmKeeper.SetEVMFork(ctx, types.ForkBerlin)
+	res, err := handler(ctx.WithGasMeter(sdk.NewGasMeter(1000000)), msg)
+	require.NoError(t, err)
+	require.True(t, res.GasUsed > TxAccessListAddressGas+TxAccessListStorageKeyGas)
+}
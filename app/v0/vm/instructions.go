@@ -511,9 +511,25 @@ func opGasprice(pc *uint64, interpreter *EVMInterpreter, contract *Contract, mem
 	return nil, nil
 }
 
+// opBlockhashIstanbul is BLOCKHASH under the Istanbul rules: the hash of the previous
+// block, whatever the block number asked
+func opBlockhashIstanbul(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	interpreter.intPool.put(stack.pop())
+	stack.push(interpreter.evm.GetHash(interpreter.evm.BlockNumber.Uint64() - 1).Big())
+
+	return nil, nil
+}
+
 func opBlockhash(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
-	stack.pop()
-	stack.push(interpreter.evm.GetHash().Big())
+	num := stack.pop()
+
+	n := interpreter.intPool.get().Sub(interpreter.evm.BlockNumber, common.Big257)
+	if num.Cmp(n) > 0 && num.Cmp(interpreter.evm.BlockNumber) < 0 {
+		stack.push(interpreter.evm.GetHash(num.Uint64()).Big())
+	} else {
+		stack.push(interpreter.intPool.getZero())
+	}
+	interpreter.intPool.put(num, n)
 
 	return nil, nil
 }
@@ -910,7 +926,7 @@ func opSelfBalance(pc *uint64, interpreter *EVMInterpreter, contract *Contract,
 }
 
 func opChainID(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
-	chainID := interpreter.intPool.get().SetBytes([]byte(interpreter.evm.chainConfig.ChainID))
+	chainID := interpreter.intPool.get().SetUint64(interpreter.evm.chainConfig.ChainID)
 	stack.push(chainID)
 	return nil, nil
 }
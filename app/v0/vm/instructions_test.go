@@ -1424,6 +1424,63 @@ func TestOpNumber(t *testing.T) {
 	require.Equal(t, blockNumber, v.Int64())
 }
 
+func TestOpBlockhash(t *testing.T) {
+	var (
+		env         = newEVM()
+		stack       = newstack()
+		interpreter = NewEVMInterpreter(env, env.vmConfig)
+		contract    = NewContract(&dummyContractRef{}, &dummyContractRef{}, nil, 0)
+	)
+
+	interpreter.intPool = poolOfIntPools.get()
+	interpreter.evm.BlockNumber = big.NewInt(300)
+	interpreter.evm.GetHash = func(n uint64) sdk.Hash {
+		return sdk.BigToHash(new(big.Int).SetUint64(n))
+	}
+	pc := uint64(0)
+
+	cases := []struct {
+		num      int64
+		expected int64
+	}{
+		{299, 299},
+		{44, 44},
+		{43, 0},
+		{300, 0},
+		{301, 0},
+	}
+
+	for _, tc := range cases {
+		stack.push(big.NewInt(tc.num))
+		_, err := opBlockhash(&pc, interpreter, contract, nil, stack)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, stack.pop().Int64(), "block number %d", tc.num)
+	}
+}
+
+func TestOpBlockhashIstanbul(t *testing.T) {
+	var (
+		env         = newEVM()
+		stack       = newstack()
+		interpreter = NewEVMInterpreter(env, env.vmConfig)
+		contract    = NewContract(&dummyContractRef{}, &dummyContractRef{}, nil, 0)
+	)
+
+	interpreter.intPool = poolOfIntPools.get()
+	interpreter.evm.BlockNumber = big.NewInt(300)
+	interpreter.evm.GetHash = func(n uint64) sdk.Hash {
+		return sdk.BigToHash(new(big.Int).SetUint64(n))
+	}
+	pc := uint64(0)
+
+	for _, num := range []int64{299, 44, 43, 300, 301} {
+		stack.push(big.NewInt(num))
+		_, err := opBlockhashIstanbul(&pc, interpreter, contract, nil, stack)
+		require.NoError(t, err)
+		require.Equal(t, int64(299), stack.pop().Int64(), "block number %d", num)
+	}
+}
+
 func TestOpDifficulty(t *testing.T) {
 	var (
 		addr        = sdk.AccAddress{0xab}
@@ -1455,15 +1512,13 @@ func TestOpChainID(t *testing.T) {
 	)
 
 	interpreter.intPool = poolOfIntPools.get()
-	interpreter.evm.chainConfig.ChainID = "test"
+	interpreter.evm.chainConfig.ChainID = 1234
 	pc := uint64(0)
 
 	opChainID(&pc, interpreter, contract, nil, stack)
 
 	v := stack.pop()
-	t.Log(string(v.Bytes()))
-
-	require.True(t, string(v.Bytes()) == interpreter.evm.chainConfig.ChainID)
+	require.Equal(t, uint64(1234), v.Uint64())
 }
 
 func TestOpPop(t *testing.T) {
@@ -18,7 +18,8 @@ type Config struct {
 	NoRecursion             bool   // Disables call, callcode, delegate call and create
 	EnablePreimageRecording bool   // Enables recording of SHA3/keccak preimages
 
-	JumpTable                 [256]operation // EVM instruction table, automatically populated if unset
+	Rules                     ForkRules      // EVM rules of the active fork, the Istanbul ones if unset
+	JumpTable                 [256]operation // EVM instruction table, automatically populated with the one of the fork if unset
 	OpConstGasConfig          *[256]uint64
 	ContractCreationGasConfig *types.VMContractCreationGasParams
 	MaxCodeSize               uint64
@@ -252,8 +253,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 // run by the current interpreter.
 func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 	if !cfg.JumpTable[STOP].valid {
-		jt := istanbulInstructionSet
-		cfg.JumpTable = jt
+		cfg.JumpTable = cfg.Rules.JumpTable()
 	}
 
 	return &EVMInterpreter{
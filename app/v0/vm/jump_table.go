@@ -308,7 +308,7 @@ func newIstanbulInstructionSet() JumpTable {
 			valid:       true,
 		},
 		BLOCKHASH: {
-			execute:     opBlockhash,
+			execute:     opBlockhashIstanbul,
 			constantGas: GasExtStep,
 			minStack:    minStack(1, 1),
 			maxStack:    maxStack(1, 1),
@@ -16,17 +16,52 @@ type Keeper struct {
 	Cdc        *codec.Codec
 	paramstore params.Subspace
 	StateDB    *types.CommitStateDB
+
+	// node-local index of the contract logs, nil if the node doesn't index them
+	logIndex *types.LogIndex
+
+	// used by the NetCloth native contracts
+	CIPALKeeper        types.CIPALKeeper
+	IPALKeeper         types.IPALKeeper
+	StakingKeeper      types.StakingKeeper
+	DistributionKeeper types.DistributionKeeper
+
+	// holds the accounts allowed to deploy contracts
+	GuardianKeeper types.GuardianKeeper
+
+	// used by the gas estimation to price the simulated txs
+	AccountKeeper auth.AccountKeeper
+
+	// used by the trace query to replay txs as DeliverTx does
+	AnteHandler      sdk.AnteHandler
+	Router           sdk.Router
+	FeeRefundHandler sdk.FeeRefundHandler
 }
 
 // NewKeeper returns vm keeper
-func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, paramstore params.Subspace, ak auth.AccountKeeper) Keeper {
+func NewKeeper(cdc *codec.Codec, storeKey, tStoreKey sdk.StoreKey, paramstore params.Subspace, ak auth.AccountKeeper,
+	cipalKeeper types.CIPALKeeper, ipalKeeper types.IPALKeeper,
+	stakingKeeper types.StakingKeeper, distrKeeper types.DistributionKeeper, guardianKeeper types.GuardianKeeper) Keeper {
 	return Keeper{
-		Cdc:        cdc,
-		paramstore: paramstore.WithKeyTable(ParamKeyTable()),
-		StateDB:    types.NewCommitStateDB(ak, storeKey),
+		Cdc:                cdc,
+		paramstore:         paramstore.WithKeyTable(ParamKeyTable()),
+		StateDB:            types.NewCommitStateDB(ak, storeKey, tStoreKey),
+		CIPALKeeper:        cipalKeeper,
+		IPALKeeper:         ipalKeeper,
+		StakingKeeper:      stakingKeeper,
+		DistributionKeeper: distrKeeper,
+		GuardianKeeper:     guardianKeeper,
+		AccountKeeper:      ak,
 	}
 }
 
+// SetTxHandlers sets the ante handler, the msg router and the fee refund handler of the app
+func (k *Keeper) SetTxHandlers(anteHandler sdk.AnteHandler, router sdk.Router, feeRefundHandler sdk.FeeRefundHandler) {
+	k.AnteHandler = anteHandler
+	k.Router = router
+	k.FeeRefundHandler = feeRefundHandler
+}
+
 // Logger returns a module-specific logger.
 func (k Keeper) Logger(ctx sdk.Context) log.Logger {
 	return ctx.Logger().With("module", fmt.Sprintf("modules/%s", types.ModuleName))
@@ -44,6 +79,78 @@ func (k *Keeper) GetLogs(ctx sdk.Context, hash sdk.Hash) []*types.Log {
 	return k.StateDB.WithContext(ctx).GetLogs(hash)
 }
 
+// WithLogIndex returns a keeper indexing the contract logs in logIndex
+func (k Keeper) WithLogIndex(logIndex *types.LogIndex) Keeper {
+	k.logIndex = logIndex
+	return k
+}
+
+// IndexBlockLogs adds the logs committed during the block at height to the log index
+func (k Keeper) IndexBlockLogs(ctx sdk.Context, height uint64) {
+	if k.logIndex == nil {
+		return
+	}
+
+	stateDB := k.StateDB.WithContext(ctx)
+	k.logIndex.IndexBlock(height, stateDB.BlockLogs(), stateDB.StoredLogs)
+}
+
+// IndexStoredLogs adds all the committed logs to the log index, after a genesis import
+func (k Keeper) IndexStoredLogs(ctx sdk.Context) {
+	if k.logIndex == nil {
+		return
+	}
+
+	stateDB := k.StateDB.WithContext(ctx)
+	logs := stateDB.AllStoredLogs()
+	for start := 0; start < len(logs); {
+		end := start + 1
+		for end < len(logs) && logs[end].BlockNumber == logs[start].BlockNumber {
+			end++
+		}
+		k.logIndex.IndexBlock(logs[start].BlockNumber, logs[start:end], stateDB.StoredLogs)
+		start = end
+	}
+}
+
+// FilterLogs returns the committed logs matching the filter, at most limit of them
+func (k Keeper) FilterLogs(ctx sdk.Context, filter types.LogFilter, limit int) ([]*types.Log, error) {
+	if k.logIndex == nil {
+		return nil, types.ErrLogIndexDisabled
+	}
+
+	return k.logIndex.FilterLogs(filter, limit, k.StateDB.WithContext(ctx).StoredLogs)
+}
+
+// GetBlockBloom returns the logs bloom of the block at height
+func (k Keeper) GetBlockBloom(height uint64) (types.Bloom, error) {
+	if k.logIndex == nil {
+		return types.Bloom{}, types.ErrLogIndexDisabled
+	}
+
+	return k.logIndex.GetBlockBloom(height), nil
+}
+
+// SetBlockHash stores the hash of the block at height in the BLOCKHASH window
+func (k Keeper) SetBlockHash(ctx sdk.Context, height int64, hash sdk.Hash) {
+	k.StateDB.WithContext(ctx).SetBlockHash(height, hash)
+}
+
+// GetBlockHash returns the hash of the block at height, or the zero hash outside of the BLOCKHASH window
+func (k Keeper) GetBlockHash(ctx sdk.Context, height int64) sdk.Hash {
+	return k.StateDB.WithContext(ctx).GetBlockHash(height)
+}
+
+// SetEVMFork activates the fork the vm runs with
+func (k Keeper) SetEVMFork(ctx sdk.Context, name string) {
+	k.StateDB.WithContext(ctx).SetEVMFork(name)
+}
+
+// GetEVMFork returns the name of the fork the vm runs with
+func (k Keeper) GetEVMFork(ctx sdk.Context) string {
+	return k.StateDB.WithContext(ctx).GetEVMFork()
+}
+
 func (k *Keeper) GetAllHostContractAddresses(ctx sdk.Context) []sdk.AccAddress {
 	return k.StateDB.WithContext(ctx).GetAllHotContractAddrs()
 }
@@ -54,12 +54,78 @@ func (k Keeper) SetVMContractCreationGasParams(ctx sdk.Context, params types.VMC
 	k.paramstore.Set(ctx, types.KeyVMContractCreationGasParams, params)
 }
 
+// GetRefundQuotient return RefundQuotient from store, chains started before the param was
+// introduced don't have it and don't refund gas
+func (k Keeper) GetRefundQuotient(ctx sdk.Context) (res uint64) {
+	k.paramstore.GetIfExists(ctx, types.KeyRefundQuotient, &res)
+	return
+}
+
+// SetRefundQuotient save RefundQuotient to store
+func (k Keeper) SetRefundQuotient(ctx sdk.Context, quotient uint64) {
+	k.paramstore.Set(ctx, types.KeyRefundQuotient, quotient)
+}
+
+// GetEVMChainID return EVMChainID from store, chains started before the param was
+// introduced don't have it and keep the chain id 0
+func (k Keeper) GetEVMChainID(ctx sdk.Context) (res uint64) {
+	k.paramstore.GetIfExists(ctx, types.KeyEVMChainID, &res)
+	return
+}
+
+// SetEVMChainID save EVMChainID to store
+func (k Keeper) SetEVMChainID(ctx sdk.Context, chainID uint64) {
+	k.paramstore.Set(ctx, types.KeyEVMChainID, chainID)
+}
+
+// GetDeployPermission return DeployPermission from store, chains started before the param was
+// introduced don't have it and anyone can deploy contracts
+func (k Keeper) GetDeployPermission(ctx sdk.Context) string {
+	res := types.DeployPermissionOpen
+	k.paramstore.GetIfExists(ctx, types.KeyDeployPermission, &res)
+	return res
+}
+
+// SetDeployPermission save DeployPermission to store
+func (k Keeper) SetDeployPermission(ctx sdk.Context, permission string) {
+	k.paramstore.Set(ctx, types.KeyDeployPermission, permission)
+}
+
+// GetEVMForks return EVMForks from store, chains started before the param was
+// introduced don't have it and stay on Istanbul
+func (k Keeper) GetEVMForks(ctx sdk.Context) (res types.EVMForks) {
+	k.paramstore.GetIfExists(ctx, types.KeyEVMForks, &res)
+	return
+}
+
+// SetEVMForks save EVMForks to store
+func (k Keeper) SetEVMForks(ctx sdk.Context, forks types.EVMForks) {
+	k.paramstore.Set(ctx, types.KeyEVMForks, forks)
+}
+
+// CanDeploy returns whether addr is allowed to deploy contracts
+func (k Keeper) CanDeploy(ctx sdk.Context, addr sdk.AccAddress) bool {
+	if k.GetDeployPermission(ctx) != types.DeployPermissionGuardianWhitelist {
+		return true
+	}
+
+	if k.GuardianKeeper == nil {
+		return false
+	}
+	_, found := k.GuardianKeeper.GetDeployer(ctx, addr)
+	return found
+}
+
 func (k Keeper) GetParams(ctx sdk.Context) (res types.Params) {
 	return types.NewParams(
 		k.GetMaxCodeSize(ctx),
 		k.GetMaxCallCreateDepth(ctx),
 		k.GetVMOpGasParams(ctx),
 		k.GetVMContractCreationGasParams(ctx),
+		k.GetRefundQuotient(ctx),
+		k.GetEVMChainID(ctx),
+		k.GetDeployPermission(ctx),
+		k.GetEVMForks(ctx),
 	)
 }
 
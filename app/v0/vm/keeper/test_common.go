@@ -79,7 +79,7 @@ func CreateTestInput(t *testing.T, isCheckTx bool, initPower int64) (sdk.Context
 		ipal.StoreKey,
 		types.StoreKey,
 	)
-	tkeys := sdk.NewTransientStoreKeys(staking.TStoreKey, staking.TStoreKey, params.TStoreKey)
+	tkeys := sdk.NewTransientStoreKeys(staking.TStoreKey, staking.TStoreKey, params.TStoreKey, types.TStoreKey)
 
 	db := dbm.NewMemDB()
 	ms := store.NewCommitMultiStore(db)
@@ -134,9 +134,15 @@ func CreateTestInput(t *testing.T, isCheckTx bool, initPower int64) (sdk.Context
 	keeper := NewKeeper(
 		cdc,
 		keys[types.StoreKey],
+		tkeys[types.TStoreKey],
 		paramsKeeper.Subspace(DefaultParamspace),
 		accountKeeper,
-	)
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	).WithLogIndex(types.NewLogIndex(dbm.NewMemDB(), 0))
 	keeper.SetParams(ctx, types.DefaultParams())
 
 	supplyKeeper.SetModuleAccount(ctx, feeCollectorAcc)
@@ -74,6 +74,10 @@ type Tracer interface {
 	CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth uint64, err error) error
 	CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth uint64, err error) error
 	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error
+	// CaptureEnter and CaptureExit are called around internal calls and creations,
+	// i.e. the ones made by a contract below the depth of the top level call
+	CaptureEnter(typ OpCode, from sdk.AccAddress, to sdk.AccAddress, input []byte, gas uint64, value *big.Int) error
+	CaptureExit(output []byte, gasUsed uint64, err error) error
 }
 
 // StructLogger is an VM state logger and implements Tracer.
@@ -118,7 +122,6 @@ func (l *StructLogger) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost ui
 	}
 
 	// initialise new changed values storage container for this contract if not presend
-	fmt.Println(contract.Address().String())
 	if l.changedValues[contract.Address().String()] == nil {
 		l.changedValues[contract.Address().String()] = make(Storage)
 	}
@@ -181,6 +184,16 @@ func (l *StructLogger) CaptureEnd(output []byte, gasUsed uint64, t time.Duration
 	return nil
 }
 
+// CaptureEnter implements the Tracer interface, internal calls are captured by CaptureState
+func (l *StructLogger) CaptureEnter(typ OpCode, from sdk.AccAddress, to sdk.AccAddress, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureExit implements the Tracer interface, internal calls are captured by CaptureState
+func (l *StructLogger) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	return nil
+}
+
 // StructLogs returns the captured log entries
 func (l *StructLogger) StructLogs() []StructLog {
 	return l.logs
@@ -196,6 +209,47 @@ func (l *StructLogger) Output() []byte {
 	return l.output
 }
 
+// FormatLogs formats the captured log entries for a trace query
+func FormatLogs(logs []StructLog) []types.StructLogRes {
+	formatted := make([]types.StructLogRes, len(logs))
+	for index, trace := range logs {
+		formatted[index] = types.StructLogRes{
+			Pc:      trace.Pc,
+			Op:      trace.Op.String(),
+			Gas:     trace.Gas,
+			GasCost: trace.GasCost,
+			Depth:   trace.Depth,
+			Error:   trace.ErrorString(),
+		}
+
+		if trace.Stack != nil {
+			stack := make([]string, len(trace.Stack))
+			for i, stackValue := range trace.Stack {
+				stack[i] = hex.EncodeToString(math.PaddedBigBytes(stackValue, 32))
+			}
+			formatted[index].Stack = stack
+		}
+
+		if trace.Memory != nil {
+			memory := make([]string, 0, (len(trace.Memory)+31)/32)
+			for i := 0; i+32 <= len(trace.Memory); i += 32 {
+				memory = append(memory, hex.EncodeToString(trace.Memory[i:i+32]))
+			}
+			formatted[index].Memory = memory
+		}
+
+		if trace.Storage != nil {
+			storage := make(map[string]string, len(trace.Storage))
+			for i, storageValue := range trace.Storage {
+				storage[hex.EncodeToString(i.Bytes())] = hex.EncodeToString(storageValue.Bytes())
+			}
+			formatted[index].Storage = storage
+		}
+	}
+
+	return formatted
+}
+
 // WriteTrace writes a formatted trace to the given writer
 func WriteTrace(writer io.Writer, logs []StructLog) {
 	for _, log := range logs {
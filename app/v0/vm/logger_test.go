@@ -40,11 +40,17 @@ func TestStoreCapture(t *testing.T) {
 	vmKeeper := NewKeeper(
 		types.ModuleCdc,
 		sdk.NewKVStoreKey(StoreKey),
+		sdk.NewTransientStoreKey(types.TStoreKey),
 		paramsKeeper.Subspace(bank.DefaultParamspace),
-		accountKeeper)
+		accountKeeper,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil)
 
 	var (
-		env      = NewEVM(Context{}, vmKeeper.StateDB, Config{})
+		env      = NewEVM(Context{}, vmKeeper.StateDB, ChainConfig{}, Config{})
 		logger   = NewStructLogger(nil)
 		mem      = NewMemory()
 		stack    = newstack()
@@ -83,7 +83,13 @@ func (am AppModule) InitGenesis(ctx sdk.Context, data json.RawMessage) []abci.Va
 	types.ModuleCdc.MustUnmarshalJSON(data, &genesisState)
 	am.keeper.SetParams(ctx, genesisState.Params)
 
+	// a chain starts on the fork its params schedule at height 0
+	if genesisState.EVMFork == "" {
+		genesisState.EVMFork = genesisState.Params.EVMForks.At(0)
+	}
+
 	am.keeper.StateDB.WithContext(ctx).ImportState(genesisState)
+	am.keeper.IndexStoredLogs(ctx)
 
 	return nil
 }
@@ -116,6 +122,7 @@ func (am AppModule) NewQuerierHandler() sdk.Querier {
 
 // BeginBlock function for module at start of each block
 func (am AppModule) BeginBlock(ctx sdk.Context, req abci.RequestBeginBlock) {
+	BeginBlocker(ctx, am.keeper)
 }
 
 // EndBlock function for module at end of block
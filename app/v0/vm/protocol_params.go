@@ -50,6 +50,19 @@ const (
 	ExtcodeHashGas  uint64 = 700  // Cost of EXTCODEHASH after EIP 1884 (part in Istanbul)
 	SelfdestructGas uint64 = 5000 // Cost of SELFDESTRUCT post EIP 150 (Tangerine)
 
+	// Costs of the state accesses after EIP 2929 (Berlin)
+	ColdAccountAccessCostEIP2929 uint64 = 2600 // Cost of the first access to an account by a transaction
+	ColdSloadCostEIP2929         uint64 = 2100 // Cost of the first access to a storage slot by a transaction
+	WarmStorageReadCostEIP2929   uint64 = 100  // Cost of the later accesses to an account or a storage slot
+
+	TxAccessListAddressGas    uint64 = 2400 // Per address specified in an EIP 2930 access list
+	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key specified in an EIP 2930 access list
+
+	// SstoreClearsScheduleRefundEIP3529 is the refund for clearing a storage slot after EIP 3529 (London):
+	// the reset gas of EIP 2929 and the cost of a storage key of an access list, 5000 - 2100 + 1900
+	SstoreClearsScheduleRefundEIP3529 uint64 = SstoreCleanGas - ColdSloadCostEIP2929 + TxAccessListStorageKeyGas
+	RefundQuotientEIP3529             uint64 = 5 // Minimum quotient of the gas used capping the refund after EIP 3529 (London)
+
 	// EXP has a dynamic portion depending on the size of the exponent
 	ExpByte uint64 = 50 // was raised to 50 during Eip158 (Spurious Dragon)
 
@@ -79,4 +92,9 @@ const (
 	Bn256ScalarMulGas       uint64 = 6000  // Gas needed for an elliptic curve scalar multiplication
 	Bn256PairingBaseGas     uint64 = 45000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 34000 // Per-point price for an elliptic curve pairing check
+
+	NativeContractQueryGas   uint64 = 2000  // Base price for a lookup of a NetCloth native contract
+	NativeContractPerWordGas uint64 = 100   // Per-word price of the data returned by a NetCloth native contract
+	StakingContractTxGas     uint64 = 50000 // Price of a delegation change made through the staking native contract
+	StakingContractRewardGas uint64 = 30000 // Price of a reward withdrawal made through the staking native contract
 )
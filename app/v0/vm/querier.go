@@ -3,9 +3,13 @@ package vm
 import (
 	"encoding/hex"
 	"encoding/json"
+	"math/big"
+	"strconv"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
 
+	"github.com/netcloth/netcloth-chain/app/v0/auth"
 	"github.com/netcloth/netcloth-chain/app/v0/vm/keeper"
 	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
 	"github.com/netcloth/netcloth-chain/codec"
@@ -30,8 +34,18 @@ func NewQuerier(k keeper.Keeper) sdk.Querier {
 			return queryStorage(ctx, path, k)
 		case types.QueryTxLogs:
 			return queryTxLogs(ctx, path, k)
+		case types.QueryFilterLogs:
+			return queryFilterLogs(ctx, req, k)
+		case types.QueryBloom:
+			return queryBloom(ctx, path, k)
 		case types.EstimateGas, types.QueryCall:
-			return simulateStateTransition(ctx, req, k)
+			return simulateStateTransition(ctx, path, req, k)
+		case types.QueryTrace:
+			return queryTrace(ctx, req, k)
+		case types.QueryTraceCall:
+			return queryTraceCall(ctx, req, k)
+		case types.QueryCreateAccessList:
+			return queryCreateAccessList(ctx, req, k)
 		default:
 			return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unknown query path: %s", path[0])
 		}
@@ -95,20 +109,487 @@ func queryTxLogs(ctx sdk.Context, path []string, keeper keeper.Keeper) ([]byte,
 	return res, nil
 }
 
-func simulateStateTransition(ctx sdk.Context, req abci.RequestQuery, k keeper.Keeper) ([]byte, error) {
-	var msg types.MsgContract
-	codec.Cdc.UnmarshalJSON(req.Data, &msg)
+func queryFilterLogs(ctx sdk.Context, req abci.RequestQuery, k keeper.Keeper) ([]byte, error) {
+	var filter types.LogFilter
+	if err := k.Cdc.UnmarshalJSON(req.Data, &filter); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+
+	if filter.ToBlock == 0 {
+		filter.ToBlock = uint64(ctx.BlockHeight())
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	logs, err := k.FilterLogs(ctx, filter, types.MaxFilterLogsResults)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
 
-	_, result, err := DoStateTransition(ctx, msg, k, true)
+	bRes := types.QueryLogsResult{Logs: logs}
+	res, err := codec.MarshalJSONIndent(k.Cdc, bRes)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
 
+	return res, nil
+}
+
+func queryBloom(ctx sdk.Context, path []string, k keeper.Keeper) ([]byte, error) {
+	if len(path) < 2 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "missing block height")
+	}
+
+	height, err := strconv.ParseUint(path[1], 10, 64)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	bloom, err := k.GetBlockBloom(height)
+	if err != nil {
+		return nil, err
+	}
+
+	bRes := types.QueryBloomResult{Height: height, Bloom: bloom}
+	res, err := codec.MarshalJSONIndent(k.Cdc, bRes)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+
+	return res, nil
+}
+
+// simulateStateTransition executes a MsgContract against the current state, with
+// the state overrides of the query applied, without committing it. A failed
+// execution is an error of the estimate_gas path, while the call path reports it
+// in the result along with the revert reason and data.
+func simulateStateTransition(ctx sdk.Context, path []string, req abci.RequestQuery, k keeper.Keeper) ([]byte, error) {
+	params, err := types.UnmarshalSimulateParams(k.Cdc, req.Data)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	msg := types.MsgContract(params.Msg)
+	result, err := simulateMsg(ctx.WithGasMeter(sdk.NewInfiniteGasMeter()), msg, params.Overrides, k, nil)
+
+	var bRes types.SimulationResult
 	if err == nil {
-		bRes := types.SimulationResult{Gas: result.GasUsed, Res: hex.EncodeToString(result.Data)}
-		res, err := codec.MarshalJSONIndent(k.Cdc, bRes)
+		bRes = types.SimulationResult{Gas: result.GasUsed, Res: hex.EncodeToString(result.Data)}
+	} else {
+		execErr, ok := err.(*types.ExecutionError)
+		if !ok || path[0] == types.EstimateGas {
+			return nil, err
+		}
+		bRes = types.SimulationResult{Gas: execErr.GasUsed, Res: hex.EncodeToString(execErr.Data), Err: execErr}
+	}
+
+	if path[0] == types.EstimateGas {
+		bRes.Gas, err = estimateGas(ctx, params, k, result.GasUsed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := codec.MarshalJSONIndent(k.Cdc, bRes)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
+
+// simulateMsg executes a MsgContract against a throwaway copy of the state with
+// the overrides applied, in a cache wrapped context
+func simulateMsg(ctx sdk.Context, msg types.MsgContract, overrides types.StateOverrides, k keeper.Keeper, tracer Tracer) (*sdk.Result, error) {
+	ctx, _ = ctx.CacheContext()
+	ctx.Simulate = true
+
+	st := StateTransition{
+		Sender:     msg.From,
+		Recipient:  msg.To,
+		Payload:    msg.Payload,
+		Amount:     msg.Amount.Amount,
+		StateDB:    types.NewStateDB(k.StateDB).WithContext(ctx),
+		Overrides:  overrides,
+		AccessList: msg.AccessList,
+		Tracer:     tracer,
+	}
+
+	_, result, err := st.TransitionCSDB(ctx, k)
+	return result, err
+}
+
+// estimateGas searches the smallest gas limit under which the tx sending the msg of
+// params succeeds, run as the simulate mode of the app runs it: the ante handler,
+// which consumes the gas of the tx size, of the signature verification and of the fee
+// deduction, then the msg. The search starts from the gas used by an unmetered
+// execution of the msg, which may use less: a call only forwards 63/64 of the gas
+// left to an inner call. A tx also pays for reading the accounts the txs before it
+// in its block touched, which the estimate can't foresee.
+func estimateGas(ctx sdk.Context, params types.QuerySimulateParams, k keeper.Keeper, gasUsed uint64) (uint64, error) {
+	msg := types.MsgContract(params.Msg)
+	return sdk.SearchGasLimit(gasUsed, DefaultVMGasLimit, func(gasLimit uint64) error {
+		return simulateTx(ctx.WithGasMeter(sdk.NewGasMeter(gasLimit)), msg, params, k)
+	})
+}
+
+// simulationTx returns the unsigned tx sending msg with the given gas limit and its
+// encoding, an Ethereum tx or a StdTx. The ante handler consumes the gas of a
+// placeholder signature for a simulated tx. The tx pays the fee of the gas price
+// threshold if the sender can afford it, and no fee otherwise. A StdTx is estimated
+// while the evm chain id is unset, the ante handler rejects Ethereum txs until then.
+func simulationTx(ctx sdk.Context, msg types.MsgContract, gasLimit uint64, ethereumTx bool, k keeper.Keeper) (sdk.Tx, []byte, error) {
+	gasPrice := new(big.Int).SetUint64(k.AccountKeeper.GetParams(ctx).GasPriceThreshold)
+	fee := sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, sdk.NewIntFromBigInt(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit)))))
+	if acc := k.AccountKeeper.GetAccount(ctx, msg.From); acc == nil || !acc.GetCoins().IsAllGTE(fee.Add(sdk.NewCoins(msg.Amount))) {
+		gasPrice, fee = new(big.Int), nil
+	}
+
+	if evmChainID := k.GetEVMChainID(ctx); ethereumTx && evmChainID != 0 {
+		nonce := types.NewStateDB(k.StateDB).WithContext(ctx).GetNonce(msg.From)
+		tx, txBytes, err := types.NewSimulationEthereumTx(msg, nonce, gasLimit, gasPrice, new(big.Int).SetUint64(evmChainID))
+		return tx, txBytes, err
+	}
+
+	tx := auth.NewStdTx([]sdk.Msg{msg}, auth.NewStdFee(gasLimit, fee), []auth.StdSignature{{}}, "")
+	txBytes, err := auth.DefaultTxEncoder(k.Cdc)(tx)
+	return tx, txBytes, err
+}
+
+// simulateTx runs the tx sending msg through the ante handler in simulate mode, if the
+// app set one, then runs msg against a throwaway copy of the state with the overrides
+// of params applied. A sender without account, e.g. the zero address eth_estimateGas
+// defaults to, runs as a new account. An out of gas panic is turned into an error.
+func simulateTx(ctx sdk.Context, msg types.MsgContract, params types.QuerySimulateParams, k keeper.Keeper) (err error) {
+	gasLimit := ctx.GasMeter().Limit()
+	defer func() {
+		if r := recover(); r != nil {
+			oog, ok := r.(sdk.ErrorOutOfGas)
+			if !ok {
+				panic(r)
+			}
+			err = sdkerrors.Wrapf(sdkerrors.ErrOutOfGas, "out of gas in location: %v; gasWanted: %d", oog.Descriptor, gasLimit)
+		}
+	}()
+
+	ctx, _ = ctx.CacheContext()
+	if k.AnteHandler != nil {
+		if stateDB := types.NewStateDB(k.StateDB).WithContext(ctx); !stateDB.Exist(msg.From) {
+			stateDB.CreateAccount(msg.From)
+			if _, err := stateDB.Commit(false); err != nil {
+				return err
+			}
+		}
+
+		tx, txBytes, err := simulationTx(ctx, msg, gasLimit, params.EthereumTx, k)
+		if err != nil {
+			return err
+		}
+
+		ctx, err = k.AnteHandler(ctx.WithTxBytes(txBytes), tx, true)
 		if err != nil {
-			return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+			return err
 		}
-		return res, nil
 	}
 
-	return nil, sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "StateTransition faileds")
+	if _, err = simulateMsg(ctx, msg, params.Overrides, k, nil); err != nil {
+		return err
+	}
+
+	// the handler then reads the logs of the tx to emit their bloom, from the store
+	// unless the tx emitted some
+	types.NewStateDB(k.StateDB).WithContext(ctx).GetLogs(sdk.BytesToHash(tmhash.Sum(ctx.TxBytes())))
+	return nil
+}
+
+// queryCreateAccessList builds the access list of a simulated contract call or
+// creation: the execution is traced with the list found so far until it accesses
+// no account or storage slot out of it. The list starts from the one of the msg.
+func queryCreateAccessList(ctx sdk.Context, req abci.RequestQuery, k keeper.Keeper) ([]byte, error) {
+	params, err := types.UnmarshalSimulateParams(k.Cdc, req.Data)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	rules := GetForkRules(k.GetEVMFork(ctx))
+	if !rules.IsBerlin {
+		return nil, types.ErrAccessListNotSupported
+	}
+
+	msg := types.MsgContract(params.Msg)
+	to := msg.To
+	if to.Empty() {
+		to = CreateAddress(msg.From, k.StateDB.WithContext(ctx).GetNonce(msg.From))
+	}
+
+	prevTracer := NewAccessListTracer(msg.AccessList, msg.From, to, rules.PrecompiledAddresses())
+	for {
+		msg.AccessList = prevTracer.AccessList()
+		tracer := NewAccessListTracer(msg.AccessList, msg.From, to, rules.PrecompiledAddresses())
+		result, err := simulateMsg(ctx.WithGasMeter(sdk.NewInfiniteGasMeter()), msg, params.Overrides, k, tracer)
+
+		var bRes types.AccessListResult
+		if err == nil {
+			bRes = types.AccessListResult{AccessList: msg.AccessList, Gas: result.GasUsed}
+		} else {
+			execErr, ok := err.(*types.ExecutionError)
+			if !ok {
+				return nil, err
+			}
+			bRes = types.AccessListResult{AccessList: msg.AccessList, Gas: execErr.GasUsed, Err: execErr}
+		}
+
+		if tracer.Equal(prevTracer) {
+			res, err := codec.MarshalJSONIndent(k.Cdc, bRes)
+			if err != nil {
+				return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+			}
+			return res, nil
+		}
+		prevTracer = tracer
+	}
+}
+
+// queryTrace replays a committed tx with a tracer attached. The query must be made
+// against the state of the parent block: the preceding txs of the block are
+// re-executed first, then every MsgContract of the target tx is traced. The txs
+// run in full, through the ante handler, the msg router and the fee refund.
+func queryTrace(ctx sdk.Context, req abci.RequestQuery, k keeper.Keeper) ([]byte, error) {
+	var params types.QueryTraceParams
+	if err := k.Cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+
+	if _, err := newTracer(params.Config); err != nil {
+		return nil, err
+	}
+
+	if k.Router == nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, "no msg router to replay txs with")
+	}
+
+	header := ctx.BlockHeader()
+	header.Height = params.Height
+	header.Time = params.Time
+	header.ProposerAddress = params.ProposerAddress
+	header.LastBlockId.Hash = params.LastBlockHash
+	ctx = ctx.WithBlockHeader(header).WithIsCheckTx(false)
+
+	// the replay caches the state objects it touches, as DeliverTx does: it runs on a
+	// throwaway state db so that they never reach the one committed by the EndBlocker
+	k.StateDB = types.NewStateDB(k.StateDB)
+
+	if params.Height > 1 {
+		k.SetBlockHash(ctx, params.Height-1, sdk.BytesToHash(params.LastBlockHash))
+	}
+
+	txDecoder := types.TxDecoder(k.Cdc)
+	for _, txBytes := range params.PrecedingTxs {
+		tx, err := txDecoder(txBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		// a tx rejected by the ante handler leaves the state unchanged, as in DeliverTx
+		_, _ = replayTx(ctx, k, txBytes, tx, nil)
+	}
+
+	tx, err := txDecoder(params.Tx)
+	if err != nil {
+		return nil, err
+	}
+
+	traces, err := replayTx(ctx, k, params.Tx, tx, &params.Config)
+	if err != nil {
+		return nil, err
+	}
+	if len(traces) == 0 {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, "tx contains no MsgContract")
+	}
+
+	results := make(types.TraceResults, len(traces))
+	for i, trace := range traces {
+		results[i] = newTraceResult(trace.gasUsed, trace.tracer, trace.err)
+	}
+
+	res, err := codec.MarshalJSONIndent(k.Cdc, results)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
+
+// queryTraceCall traces a simulated contract call or creation, like the call and
+// estimate_gas queries do without tracer
+func queryTraceCall(ctx sdk.Context, req abci.RequestQuery, k keeper.Keeper) ([]byte, error) {
+	var params types.QueryTraceCallParams
+	if err := k.Cdc.UnmarshalJSON(req.Data, &params); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+
+	tracer, err := newTracer(params.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := params.Overrides.Validate(); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	ctx.Simulate = true
+	st := newTraceStateTransition(types.MsgContract(params.Msg), types.NewStateDB(k.StateDB).WithContext(ctx), tracer)
+	st.Overrides = params.Overrides
+	_, result, vmErr := st.TransitionCSDB(ctx, k)
+
+	var gasUsed uint64
+	if result != nil {
+		gasUsed = result.GasUsed
+	}
+
+	res, err := codec.MarshalJSONIndent(k.Cdc, newTraceResult(gasUsed, tracer, vmErr))
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return res, nil
+}
+
+// newTracer creates the tracer selected by the trace config
+func newTracer(config types.TraceConfig) (Tracer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+
+	if config.Tracer == types.CallTracer {
+		return NewCallTracer(), nil
+	}
+
+	return NewStructLogger(&LogConfig{
+		DisableMemory:  config.DisableMemory,
+		DisableStack:   config.DisableStack,
+		DisableStorage: config.DisableStorage,
+		Limit:          config.Limit,
+	}), nil
+}
+
+// newTraceResult builds the trace result of a contract execution from its tracer
+func newTraceResult(gasUsed uint64, tracer Tracer, vmErr error) types.TraceResult {
+	res := types.TraceResult{
+		Gas:    gasUsed,
+		Failed: vmErr != nil,
+	}
+	if vmErr != nil {
+		res.Error = vmErr.Error()
+	}
+
+	switch tracer := tracer.(type) {
+	case *StructLogger:
+		res.ReturnValue = hex.EncodeToString(tracer.Output())
+		res.StructLogs = FormatLogs(tracer.StructLogs())
+	case *CallTracer:
+		callTrace := tracer.Result()
+		res.ReturnValue = hex.EncodeToString(callTrace.Output)
+		res.CallTrace = &callTrace
+	}
+
+	return res
+}
+
+// msgTrace is the trace of a MsgContract replayed by the trace query, gasUsed is
+// the gas consumed by the tx up to the end of the msg
+type msgTrace struct {
+	gasUsed uint64
+	tracer  Tracer
+	err     error
+}
+
+// replayTx runs tx as DeliverTx does: the ante handler, then the msgs, whose state
+// changes are discarded if one of them fails, then the fee refund. If config is not
+// nil, the MsgContracts are traced up to the first failing msg. The error of the
+// ante handler is returned, the ones of the msgs are part of their traces.
+func replayTx(ctx sdk.Context, k keeper.Keeper, txBytes []byte, tx sdk.Tx, config *types.TraceConfig) (traces []msgTrace, err error) {
+	ctx = ctx.WithTxBytes(txBytes).WithEventManager(sdk.NewEventManager())
+	ctx.Simulate = false
+
+	if k.AnteHandler != nil {
+		anteCtx, writeCache := ctx.CacheContext()
+		newCtx, err := k.AnteHandler(anteCtx, tx, false)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx = newCtx.WithMultiStore(ctx.MultiStore())
+		writeCache()
+	}
+
+	runMsgCtx, writeCache := ctx.CacheContext()
+	var msgErr error
+	for _, msg := range tx.GetMsgs() {
+		var tracer Tracer
+		if _, ok := msg.(types.MsgContract); ok && config != nil {
+			tracer, _ = newTracer(*config)
+		}
+
+		msgErr = replayMsg(runMsgCtx, k, msg, tracer)
+		if tracer != nil {
+			traces = append(traces, msgTrace{gasUsed: ctx.GasMeter().GasConsumed(), tracer: tracer, err: msgErr})
+		}
+		if msgErr != nil {
+			break
+		}
+	}
+
+	if msgErr == nil {
+		writeCache()
+	}
+
+	// as in DeliverTx, the unspent fee is not refunded when the tx runs out of gas
+	if k.FeeRefundHandler != nil && !sdkerrors.ErrOutOfGas.Is(msgErr) {
+		refundCtx, writeRefund := ctx.CacheContext()
+		result := sdk.Result{GasWanted: ctx.GasMeter().Limit(), GasUsed: ctx.GasMeter().GasConsumed()}
+		if _, err := k.FeeRefundHandler(refundCtx, tx, result); err == nil {
+			writeRefund()
+		}
+	}
+
+	return traces, nil
+}
+
+// replayMsg runs msg through the msg router, except a MsgContract which runs against
+// the state db of k, traced by tracer if not nil. An out of gas panic is turned into
+// an error.
+func replayMsg(ctx sdk.Context, k keeper.Keeper, msg sdk.Msg, tracer Tracer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(sdk.ErrorOutOfGas); !ok {
+				panic(r)
+			}
+			err = sdkerrors.ErrOutOfGas
+		}
+	}()
+
+	if msg, ok := msg.(types.MsgContract); ok {
+		_, err = handleMsgContract(ctx.WithEventManager(sdk.NewEventManager()), msg, k, tracer)
+		return err
+	}
+
+	handler := k.Router.Route(ctx, msg.Route())
+	if handler == nil {
+		return sdkerrors.Wrapf(sdkerrors.ErrUnknownRequest, "unrecognized message route: %s", msg.Route())
+	}
+
+	_, err = handler(ctx, msg)
+	return err
+}
+
+func newTraceStateTransition(msg types.MsgContract, stateDB *types.CommitStateDB, tracer Tracer) StateTransition {
+	return StateTransition{
+		Sender:     msg.From,
+		Recipient:  msg.To,
+		Payload:    msg.Payload,
+		Amount:     msg.Amount.Amount,
+		StateDB:    stateDB,
+		Tracer:     tracer,
+		AccessList: msg.AccessList,
+	}
 }
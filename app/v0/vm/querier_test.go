@@ -0,0 +1,584 @@
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/netcloth/netcloth-chain/app/protocol"
+	"github.com/netcloth/netcloth-chain/app/v0/auth"
+	"github.com/netcloth/netcloth-chain/app/v0/auth/ante"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/common"
+	keep "github.com/netcloth/netcloth-chain/app/v0/vm/keeper"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/types"
+	"github.com/netcloth/netcloth-chain/hexutil"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
+)
+
+// token contract from TestMsgContractCreateAndCall, the creator owns the whole supply
+const tokenCode = "608060405234801561001057600080fd5b506509184e72a0006000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002081905550610344806100696000396000f300608060405260043610610057576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806327e235e31461005c57806370a08231146100b3578063a9059cbb1461010a575b600080fd5b34801561006857600080fd5b5061009d600480360381019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190505050610162565b6040518082815260200191505060405180910390f35b3480156100bf57600080fd5b506100f4600480360381019080803573ffffffffffffffffffffffffffffffffffffffff16906020019092919050505061017a565b6040518082815260200191505060405180910390f35b610148600480360381019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803590602001909291905050506101c2565b604051808215151515815260200191505060405180910390f35b60006020528060005260406000206000915090505481565b60008060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020549050919050565b6000816000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020541015151561021157600080fd5b816000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008282540392505081905550816000808573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020600082825401925050819055508273ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef846040518082815260200191505060405180910390a360019050929150505600a165627a7a7230582015481e18f5439ee76271037928d88d33cc7d7d4bf1e5e801b78db9e902f255560029"
+
+func TestQueryTrace(t *testing.T) {
+	ctx, accountKeeper, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	types.RegisterCodec(vmKeeper.Cdc)
+
+	code := sdk.FromHex(tokenCode)
+	transfer := common.FromHex("a9059cbb0000000000000000000000005376329591cde25497d29de88ec553229ad10a610000000000000000000000000000000000000000000000000000000000000064")
+
+	acc := accountKeeper.GetAccount(ctx, keep.Addrs[0])
+	contractAddr := CreateAddress(acc.GetAddress(), acc.GetSequence())
+
+	_, err := NewHandler(vmKeeper)(ctx, types.NewMsgContract(acc.GetAddress(), nil, code, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	txBytes := func(from sdk.AccAddress, payload []byte) []byte {
+		msg := types.NewMsgContract(from, contractAddr, payload, sdk.NewInt64Coin(sdk.NativeTokenName, 0))
+		tx := auth.NewStdTx([]sdk.Msg{msg}, auth.NewStdFee(1000000, nil), nil, "")
+		return vmKeeper.Cdc.MustMarshalBinaryLengthPrefixed(tx)
+	}
+
+	// the test txs are not signed, the ante handler only sets the gas meter
+	router := protocol.NewRouter().AddRoute(RouterKey, NewHandler(vmKeeper))
+	anteHandler := func(ctx sdk.Context, tx sdk.Tx, _ bool) (sdk.Context, error) {
+		return ctx.WithGasMeter(sdk.NewGasMeter(tx.(auth.StdTx).Fee.Gas)), nil
+	}
+	vmKeeper.SetTxHandlers(anteHandler, router, nil)
+
+	querier := NewQuerier(vmKeeper)
+	queryAll := func(params types.QueryTraceParams) types.TraceResults {
+		bz, err := querier(ctx, []string{types.QueryTrace}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(params)})
+		require.NoError(t, err)
+
+		var out types.TraceResults
+		vmKeeper.Cdc.MustUnmarshalJSON(bz, &out)
+		return out
+	}
+	query := func(params types.QueryTraceParams) types.TraceResult {
+		out := queryAll(params)
+		require.Len(t, out, 1)
+		return out[0]
+	}
+
+	params := types.QueryTraceParams{
+		Height: 2,
+		Time:   time.Unix(0, 0),
+		Tx:     txBytes(keep.Addrs[0], transfer),
+	}
+
+	out := query(params)
+	require.False(t, out.Failed)
+	require.NotZero(t, out.Gas)
+	require.Equal(t, "0000000000000000000000000000000000000000000000000000000000000001", out.ReturnValue)
+	require.NotEmpty(t, out.StructLogs)
+	require.Equal(t, "PUSH1", out.StructLogs[0].Op)
+	require.NotNil(t, out.StructLogs[len(out.StructLogs)-1].Stack)
+
+	params.Config = types.TraceConfig{DisableStack: true, DisableMemory: true, Limit: 10}
+	out = query(params)
+	require.Len(t, out.StructLogs, 10)
+	for _, log := range out.StructLogs {
+		require.Nil(t, log.Stack)
+		require.Nil(t, log.Memory)
+	}
+
+	// the second account owns no tokens, its transfer reverts unless the preceding tx funds it
+	params = types.QueryTraceParams{
+		Height: 2,
+		Time:   time.Unix(0, 0),
+		Tx:     txBytes(keep.Addrs[1], transfer),
+	}
+	out = query(params)
+	require.True(t, out.Failed)
+	require.Equal(t, "REVERT", out.StructLogs[len(out.StructLogs)-1].Op)
+
+	fund := common.FromHex("a9059cbb000000000000000000000000" + common.Bytes2Hex(keep.Addrs[1]) + "0000000000000000000000000000000000000000000000000000000000000064")
+	params.PrecedingTxs = [][]byte{txBytes(keep.Addrs[0], fund)}
+	out = query(params)
+	require.False(t, out.Failed, out.Error)
+
+	// every MsgContract of the tx is traced, the second transfer is funded by the first one
+	msgs := []sdk.Msg{
+		types.NewMsgContract(keep.Addrs[0], contractAddr, fund, sdk.NewInt64Coin(sdk.NativeTokenName, 0)),
+		types.NewMsgContract(keep.Addrs[1], contractAddr, transfer, sdk.NewInt64Coin(sdk.NativeTokenName, 0)),
+	}
+	params = types.QueryTraceParams{
+		Height: 2,
+		Time:   time.Unix(0, 0),
+		Tx:     vmKeeper.Cdc.MustMarshalBinaryLengthPrefixed(auth.NewStdTx(msgs, auth.NewStdFee(1000000, nil), nil, "")),
+	}
+	traces := queryAll(params)
+	require.Len(t, traces, 2)
+	require.False(t, traces[0].Failed, traces[0].Error)
+	require.False(t, traces[1].Failed, traces[1].Error)
+	require.True(t, traces[1].Gas > traces[0].Gas)
+
+	_, err = querier(ctx, []string{types.QueryTrace}, abci.RequestQuery{Data: []byte("invalid")})
+	require.Error(t, err)
+}
+
+func TestQueryTraceStateUnchanged(t *testing.T) {
+	ctx, accountKeeper, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	types.RegisterCodec(vmKeeper.Cdc)
+	handler := NewHandler(vmKeeper)
+
+	acc := accountKeeper.GetAccount(ctx, keep.Addrs[0])
+	contractAddr := CreateAddress(acc.GetAddress(), acc.GetSequence())
+
+	_, err := handler(ctx, types.NewMsgContract(acc.GetAddress(), nil, sdk.FromHex(tokenCode), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	transfer := func(to sdk.AccAddress) types.MsgContract {
+		payload := common.FromHex("a9059cbb000000000000000000000000" + common.Bytes2Hex(to) + "0000000000000000000000000000000000000000000000000000000000000064")
+		return types.NewMsgContract(acc.GetAddress(), contractAddr, payload, sdk.NewInt64Coin(sdk.NativeTokenName, 0))
+	}
+	balance := func(addr sdk.AccAddress) string {
+		payload := common.FromHex("70a08231000000000000000000000000" + common.Bytes2Hex(addr))
+		msg := types.NewMsgContractQuery(keep.Addrs[0], contractAddr, payload, sdk.NewInt64Coin(sdk.NativeTokenName, 0))
+		bz, err := NewQuerier(vmKeeper)(ctx, []string{types.QueryCall}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(msg)})
+		require.NoError(t, err)
+
+		var out types.SimulationResult
+		vmKeeper.Cdc.MustUnmarshalJSON(bz, &out)
+		return out.Res
+	}
+
+	router := protocol.NewRouter().AddRoute(RouterKey, handler)
+	anteHandler := func(ctx sdk.Context, tx sdk.Tx, _ bool) (sdk.Context, error) {
+		return ctx.WithGasMeter(sdk.NewGasMeter(tx.(auth.StdTx).Fee.Gas)), nil
+	}
+	vmKeeper.SetTxHandlers(anteHandler, router, nil)
+
+	// the trace of a transfer to the second account, made against a cache of the state as queries are
+	tx := auth.NewStdTx([]sdk.Msg{transfer(keep.Addrs[1])}, auth.NewStdFee(1000000, nil), nil, "")
+	params := types.QueryTraceParams{
+		Height: 2,
+		Time:   time.Unix(0, 0),
+		Tx:     vmKeeper.Cdc.MustMarshalBinaryLengthPrefixed(tx),
+	}
+	queryCtx, _ := ctx.CacheContext()
+	_, err = NewQuerier(vmKeeper)(queryCtx, []string{types.QueryTrace}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(params)})
+	require.NoError(t, err)
+
+	// a transfer to the third account is delivered and committed, the traced transfer isn't
+	ctx = ctx.WithBlockHeight(2).WithTxBytes([]byte{2})
+	_, err = handler(ctx, transfer(keep.Addrs[2]))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	require.Equal(t, "0000000000000000000000000000000000000000000000000000000000000000", balance(keep.Addrs[1]))
+	require.Equal(t, "0000000000000000000000000000000000000000000000000000000000000064", balance(keep.Addrs[2]))
+	require.Equal(t, "000000000000000000000000000000000000000000000000000009184e729f9c", balance(keep.Addrs[0]))
+}
+
+func TestQueryTraceCall(t *testing.T) {
+	ctx, accountKeeper, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+
+	// ./testdata/opCreate, bf335e62 creates two contracts
+	code := sdk.FromHex("608060405234801561001057600080fd5b5060008060006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550610230806100616000396000f3fe608060405234801561001057600080fd5b50600436106100365760003560e01c80630dbe671f1461003b578063bf335e6214610085575b600080fd5b61004361008f565b604051808273ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200191505060405180910390f35b61008d6100b4565b005b6000809054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b60006040516100c290610192565b604051809103906000f0801580156100de573d6000803e3d6000fd5b509050806000806101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff160217905550600060405161012f90610192565b604051809103906000f08015801561014b573d6000803e3d6000fd5b509050806000806101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055505050565b605c8061019f8339019056fe6080604052348015600f57600080fd5b50603f80601d6000396000f3fe6080604052600080fdfea2646970667358221220b405addc262113ddf77e588ca32b50e0a49f3faea9d197a08e25695efdd1408c64736f6c63430006000033a2646970667358221220547e8e8e5af3e1fd635f2d113ac5dba66cc8686d58fb862e15a05827434a39b564736f6c63430006000033")
+
+	acc := accountKeeper.GetAccount(ctx, keep.Addrs[0])
+	contractAddr := CreateAddress(acc.GetAddress(), acc.GetSequence())
+
+	_, err := NewHandler(vmKeeper)(ctx, types.NewMsgContract(acc.GetAddress(), nil, code, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	querier := NewQuerier(vmKeeper)
+	params := types.QueryTraceCallParams{
+		Msg:    types.NewMsgContractQuery(acc.GetAddress(), contractAddr, common.FromHex("bf335e62"), sdk.NewInt64Coin(sdk.NativeTokenName, 0)),
+		Config: types.TraceConfig{Tracer: types.CallTracer},
+	}
+
+	bz, err := querier(ctx, []string{types.QueryTraceCall}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(params)})
+	require.NoError(t, err)
+
+	var out types.TraceResult
+	vmKeeper.Cdc.MustUnmarshalJSON(bz, &out)
+	require.False(t, out.Failed, out.Error)
+	require.Empty(t, out.StructLogs)
+	require.NotNil(t, out.CallTrace)
+
+	root := out.CallTrace
+	require.Equal(t, "CALL", root.Type)
+	require.Equal(t, acc.GetAddress(), root.From)
+	require.Equal(t, contractAddr, root.To)
+	require.NotZero(t, root.GasUsed)
+	require.Len(t, root.Calls, 2)
+	for _, call := range root.Calls {
+		require.Equal(t, "CREATE", call.Type)
+		require.Equal(t, contractAddr, call.From)
+		require.NotEmpty(t, call.To)
+		require.True(t, call.GasUsed > 0 && call.GasUsed <= call.Gas)
+		require.Empty(t, call.Error)
+	}
+	require.NotEqual(t, root.Calls[0].To, root.Calls[1].To)
+
+	params.Config = types.TraceConfig{Tracer: "unknown"}
+	_, err = querier(ctx, []string{types.QueryTraceCall}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(params)})
+	require.Error(t, err)
+}
+
+func TestQueryFilterLogs(t *testing.T) {
+	ctx, accountKeeper, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	// only the logs of the last three blocks are kept in the index
+	vmKeeper = vmKeeper.WithLogIndex(types.NewLogIndex(dbm.NewMemDB(), 3))
+	handler := NewHandler(vmKeeper)
+
+	acc := accountKeeper.GetAccount(ctx, keep.Addrs[0])
+	contractAddr := CreateAddress(acc.GetAddress(), acc.GetSequence())
+
+	_, err := handler(ctx, types.NewMsgContract(acc.GetAddress(), nil, sdk.FromHex(tokenCode), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	// a transfer of 100 tokens to the second account in each of the blocks 2 to 4
+	transfer := common.FromHex("a9059cbb000000000000000000000000" + common.Bytes2Hex(keep.Addrs[1]) + "0000000000000000000000000000000000000000000000000000000000000064")
+	for height := int64(2); height <= 4; height++ {
+		ctx = ctx.WithBlockHeight(height).WithTxBytes([]byte{byte(height)})
+		res, err := handler(ctx, types.NewMsgContract(acc.GetAddress(), contractAddr, transfer, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+		require.NoError(t, err)
+		EndBlocker(ctx, vmKeeper)
+
+		require.Equal(t, common.FromHex("0000000000000000000000000000000000000000000000000000000000000001"), res.Data)
+
+		var txBloom types.Bloom
+		for _, e := range res.Events {
+			if e.Type == types.EventTypeLogsBloom {
+				require.NoError(t, txBloom.UnmarshalJSON([]byte(`"`+string(e.Attributes[0].Value)+`"`)))
+			}
+		}
+		require.True(t, txBloom.Test(contractAddr.Bytes()))
+		bloom, err := vmKeeper.GetBlockBloom(uint64(height))
+		require.NoError(t, err)
+		require.Equal(t, txBloom, bloom)
+	}
+
+	querier := NewQuerier(vmKeeper)
+	query := func(filter types.LogFilter) []*types.Log {
+		bz, err := querier(ctx, []string{types.QueryFilterLogs}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(filter)})
+		require.NoError(t, err)
+
+		var out types.QueryLogsResult
+		vmKeeper.Cdc.MustUnmarshalJSON(bz, &out)
+		return out.Logs
+	}
+
+	transferEvent := sdk.HexToHash("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	to := sdk.BytesToHash(keep.Addrs[1])
+
+	logs := query(types.NewLogFilter(0, 0, []sdk.AccAddress{contractAddr}, [][]sdk.Hash{{transferEvent}, nil, {to}}))
+	require.Len(t, logs, 3)
+	for i, log := range logs {
+		require.Equal(t, uint64(2+i), log.BlockNumber)
+		require.Equal(t, contractAddr, log.Address)
+	}
+
+	require.Len(t, query(types.NewLogFilter(3, 3, nil, [][]sdk.Hash{{transferEvent}})), 1)
+
+	bz, err := querier(ctx, []string{types.QueryBloom, "3"}, abci.RequestQuery{})
+	require.NoError(t, err)
+	var bloom types.QueryBloomResult
+	vmKeeper.Cdc.MustUnmarshalJSON(bz, &bloom)
+	require.True(t, bloom.Bloom.Test(transferEvent.Bytes()))
+	require.False(t, bloom.Bloom.Test(keep.Addrs[0]))
+	require.Empty(t, query(types.NewLogFilter(0, 0, []sdk.AccAddress{keep.Addrs[0]}, nil)))
+
+	// the logs of the block 2 fall out of the retention window at the block 5
+	EndBlocker(ctx.WithBlockHeight(5), vmKeeper)
+	logs = query(types.NewLogFilter(0, 0, nil, nil))
+	require.Len(t, logs, 2)
+	require.Equal(t, uint64(3), logs[0].BlockNumber)
+	require.Equal(t, uint64(4), logs[1].BlockNumber)
+
+	_, err = NewQuerier(vmKeeper.WithLogIndex(nil))(ctx, []string{types.QueryBloom, "3"}, abci.RequestQuery{})
+	require.Error(t, err)
+
+	_, err = querier(ctx, []string{types.QueryFilterLogs}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(types.NewLogFilter(2, 1, nil, nil))})
+	require.Error(t, err)
+
+	// a filter up to the latest block spans the blocks up to the height of the query
+	_, err = querier(ctx.WithBlockHeight(types.MaxFilterLogsBlockRange), []string{types.QueryFilterLogs}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(types.NewLogFilter(0, 0, nil, nil))})
+	require.Error(t, err)
+}
+
+func TestQueryEstimateGas(t *testing.T) {
+	ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	handler := NewHandler(vmKeeper)
+
+	// loops 32768 times
+	burner := "600f80600b6000396000f3" + "6000" + "5b600101806180001160025700"
+	burnerAddr := CreateAddress(keep.Addrs[0], 0)
+	_, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, sdk.FromHex(burner), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+
+	// calls the burner with all the gas left and reverts if the call fails
+	proxy := "602b80600b6000396000f36000600060006000600073" + common.Bytes2Hex(burnerAddr) + "5af160295760006000fd5b00"
+	proxyAddr := CreateAddress(keep.Addrs[1], 0)
+	_, err = handler(ctx, types.NewMsgContract(keep.Addrs[1], nil, sdk.FromHex(proxy), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	querier := NewQuerier(vmKeeper)
+	msg := types.NewMsgContractQuery(keep.Addrs[2], proxyAddr, sdk.FromHex("00"), sdk.NewInt64Coin(sdk.NativeTokenName, 0))
+	data := vmKeeper.Cdc.MustMarshalJSON(msg)
+
+	bz, err := querier(ctx, []string{types.QueryCall}, abci.RequestQuery{Data: data})
+	require.NoError(t, err)
+	var call types.SimulationResult
+	vmKeeper.Cdc.MustUnmarshalJSON(bz, &call)
+	require.Nil(t, call.Err)
+
+	bz, err = querier(ctx, []string{types.EstimateGas}, abci.RequestQuery{Data: data})
+	require.NoError(t, err)
+	var estimate types.SimulationResult
+	vmKeeper.Cdc.MustUnmarshalJSON(bz, &estimate)
+
+	// the inner call only gets 63/64 of the gas left, the gas used isn't enough
+	require.True(t, estimate.Gas > call.Gas)
+
+	execute := func(gasLimit uint64) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = sdkerrors.ErrOutOfGas
+			}
+		}()
+
+		cacheCtx, _ := ctx.CacheContext()
+		_, err = handler(cacheCtx.WithGasMeter(sdk.NewGasMeter(gasLimit)), types.NewMsgContract(keep.Addrs[2], proxyAddr, sdk.FromHex("00"), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+		return
+	}
+	require.NoError(t, execute(estimate.Gas))
+	require.Error(t, execute(estimate.Gas-1))
+	require.Error(t, execute(call.Gas))
+}
+
+func TestQueryEstimateGasAnteHandler(t *testing.T) {
+	ctx, accountKeeper, vmKeeper, supplyKeeper := keep.CreateTestInput(t, false, 1000000)
+	types.RegisterCodec(vmKeeper.Cdc)
+	ctx = ctx.WithBlockHeight(2)
+	accountKeeper.SetParams(ctx, auth.DefaultParams())
+	vmKeeper.SetEVMChainID(ctx, 88)
+	handler := NewHandler(vmKeeper)
+
+	contractAddr := CreateAddress(keep.Addrs[0], 0)
+	_, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, sdk.FromHex(tokenCode), sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+	balanceOf := common.FromHex("70a08231000000000000000000000000" + common.Bytes2Hex(keep.Addrs[0]))
+
+	// the test txs are signed, by a secp256k1 key for the StdTx and an Ethereum key for the Ethereum tx
+	stdKey := secp256k1.GenPrivKey()
+	stdAddr := sdk.AccAddress(stdKey.PubKey().Address())
+	ethKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	ethAddr := sdk.AccAddress(crypto.PubkeyToAddress(ethKey.PublicKey).Bytes())
+	for _, addr := range []sdk.AccAddress{stdAddr, ethAddr} {
+		acc := accountKeeper.NewAccountWithAddress(ctx, addr)
+		require.NoError(t, acc.SetCoins(sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, sdk.TokensFromConsensusPower(1000000)))))
+		accountKeeper.SetAccount(ctx, acc)
+	}
+
+	gasPrice := accountKeeper.GetParams(ctx).GasPriceThreshold
+	stdTx := func(gasLimit uint64) (sdk.Tx, []byte) {
+		msgs := []sdk.Msg{types.NewMsgContract(stdAddr, contractAddr, balanceOf, sdk.NewInt64Coin(sdk.NativeTokenName, 0))}
+		fee := auth.NewStdFee(gasLimit, sdk.NewCoins(sdk.NewInt64Coin(sdk.NativeTokenName, int64(gasLimit*gasPrice))))
+		acc := accountKeeper.GetAccount(ctx, stdAddr)
+		sig, err := stdKey.Sign(auth.StdSignBytes(ctx.ChainID(), acc.GetAccountNumber(), acc.GetSequence(), fee, msgs, ""))
+		require.NoError(t, err)
+
+		tx := auth.NewStdTx(msgs, fee, []auth.StdSignature{{PubKey: stdKey.PubKey(), Signature: sig}}, "")
+		return tx, vmKeeper.Cdc.MustMarshalBinaryLengthPrefixed(tx)
+	}
+	ethTx := func(gasLimit uint64) (sdk.Tx, []byte) {
+		nonce := accountKeeper.GetAccount(ctx, ethAddr).GetSequence()
+		tx := ethtypes.NewTransaction(nonce, ethcommon.BytesToAddress(contractAddr), big.NewInt(0), gasLimit, new(big.Int).SetUint64(gasPrice), balanceOf)
+		tx, err := ethtypes.SignTx(tx, ethtypes.NewEIP155Signer(big.NewInt(88)), ethKey)
+		require.NoError(t, err)
+
+		txBytes, err := rlp.EncodeToBytes(tx)
+		require.NoError(t, err)
+		ethereumTx, err := types.DecodeEthereumTx(txBytes)
+		require.NoError(t, err)
+		return ethereumTx, txBytes
+	}
+
+	// delivers the tx through the ante handler then the handler, as the first tx of a block
+	anteHandler := ante.NewAnteHandler(accountKeeper, supplyKeeper, vmKeeper, ante.DefaultSigVerificationGasConsumer)
+	deliver := func(tx sdk.Tx, txBytes []byte) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = sdkerrors.ErrOutOfGas
+			}
+		}()
+
+		vmKeeper.StateDB.ClearStateObjects()
+		cacheCtx, _ := ctx.CacheContext()
+		newCtx, err := anteHandler(cacheCtx.WithTxBytes(txBytes), tx, false)
+		if err != nil {
+			return err
+		}
+
+		_, err = handler(newCtx, tx.GetMsgs()[0])
+		return err
+	}
+
+	estimateGas := func(k keep.Keeper, params types.QuerySimulateParams) uint64 {
+		bz, err := NewQuerier(k)(ctx, []string{types.EstimateGas}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(params)})
+		require.NoError(t, err)
+
+		var estimate types.SimulationResult
+		vmKeeper.Cdc.MustUnmarshalJSON(bz, &estimate)
+		return estimate.Gas
+	}
+
+	// without ante handler only the gas of the msg is estimated
+	vmOnlyGas := estimateGas(vmKeeper, types.QuerySimulateParams{Msg: types.NewMsgContractQuery(stdAddr, contractAddr, balanceOf, sdk.NewInt64Coin(sdk.NativeTokenName, 0))})
+	vmKeeper.SetTxHandlers(anteHandler, protocol.NewRouter().AddRoute(RouterKey, handler), nil)
+
+	gas := estimateGas(vmKeeper, types.QuerySimulateParams{Msg: types.NewMsgContractQuery(stdAddr, contractAddr, balanceOf, sdk.NewInt64Coin(sdk.NativeTokenName, 0))})
+	require.True(t, gas > vmOnlyGas)
+	require.NoError(t, deliver(stdTx(gas)))
+	require.Error(t, deliver(stdTx(vmOnlyGas)))
+
+	ethGas := estimateGas(vmKeeper, types.QuerySimulateParams{Msg: types.NewMsgContractQuery(ethAddr, contractAddr, balanceOf, sdk.NewInt64Coin(sdk.NativeTokenName, 0)), EthereumTx: true})
+	require.True(t, ethGas > vmOnlyGas)
+	require.NoError(t, deliver(ethTx(ethGas)))
+	require.Error(t, deliver(ethTx(ethGas-1)))
+}
+
+func TestQueryCallStateOverrides(t *testing.T) {
+	ctx, accountKeeper, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	querier := NewQuerier(vmKeeper)
+
+	// returns its balance and the value of its storage slot 0
+	code := hexutil.Bytes(sdk.FromHex("30316000526000546020526040" + "6000f3"))
+	contractAddr := sdk.AccAddress(bytes.Repeat([]byte{0x42}, sdk.AddrLen))
+	slot := sdk.BytesToHash([]byte{0x99})
+
+	amount, ok := sdk.NewIntFromString("1000000000000000000000000000000")
+	require.True(t, ok)
+	msg := types.NewMsgContractQuery(keep.Addrs[0], contractAddr, sdk.FromHex("00"), sdk.NewCoin(sdk.NativeTokenName, amount))
+
+	call := func(overrides types.StateOverrides) (types.SimulationResult, error) {
+		bz, err := querier(ctx, []string{types.QueryCall}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(types.QuerySimulateParams{Msg: msg, Overrides: overrides})})
+		var out types.SimulationResult
+		if err == nil {
+			vmKeeper.Cdc.MustUnmarshalJSON(bz, &out)
+		}
+		return out, err
+	}
+
+	// the sender can't afford the transfer
+	out, err := call(types.StateOverrides{{Address: contractAddr, Code: &code}})
+	require.NoError(t, err)
+	require.NotNil(t, out.Err)
+
+	contractBalance := sdk.NewInt(12345)
+	out, err = call(types.StateOverrides{
+		{Address: keep.Addrs[0], Balance: &amount},
+		{Address: contractAddr, Balance: &contractBalance, Code: &code, Storage: []types.StorageOverride{{Key: sdk.Hash{}, Value: slot}}},
+	})
+	require.NoError(t, err)
+	require.Nil(t, out.Err)
+	require.Equal(t, common.Bytes2Hex(sdk.BigToHash(amount.Add(contractBalance).BigInt()).Bytes())+common.Bytes2Hex(slot.Bytes()), out.Res)
+
+	// nothing is written to the state
+	require.Empty(t, vmKeeper.GetCode(ctx, contractAddr))
+	require.Nil(t, accountKeeper.GetAccount(ctx, contractAddr))
+	require.True(t, accountKeeper.GetAccount(ctx, keep.Addrs[0]).GetCoins().AmountOf(sdk.NativeTokenName).LT(amount))
+
+	// invalid overrides
+	_, err = call(types.StateOverrides{{Address: contractAddr, Code: &code}, {Address: contractAddr}})
+	require.Error(t, err)
+}
+
+func TestQueryCallChainID(t *testing.T) {
+	ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	querier := NewQuerier(vmKeeper)
+
+	// returns the chain id pushed by CHAINID
+	code := hexutil.Bytes(sdk.FromHex("4660005260206000f3"))
+	contractAddr := sdk.AccAddress(bytes.Repeat([]byte{0x42}, sdk.AddrLen))
+	msg := types.NewMsgContractQuery(keep.Addrs[0], contractAddr, sdk.FromHex("00"), sdk.NewInt64Coin(sdk.NativeTokenName, 0))
+	call := func() types.SimulationResult {
+		bz, err := querier(ctx, []string{types.QueryCall}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(types.QuerySimulateParams{Msg: msg, Overrides: types.StateOverrides{{Address: contractAddr, Code: &code}}})})
+		require.NoError(t, err)
+
+		var out types.SimulationResult
+		vmKeeper.Cdc.MustUnmarshalJSON(bz, &out)
+		return out
+	}
+
+	// CHAINID is an invalid opcode until the berlin fork
+	vmKeeper.SetEVMChainID(ctx, 1)
+	require.NotNil(t, call().Err)
+
+	vmKeeper.SetEVMFork(ctx, types.ForkBerlin)
+	for _, chainID := range []uint64{0, 1, 8888} {
+		vmKeeper.SetEVMChainID(ctx, chainID)
+		require.Equal(t, chainID, vmKeeper.GetParams(ctx).EVMChainID)
+
+		out := call()
+		require.Nil(t, out.Err)
+		require.Equal(t, common.Bytes2Hex(sdk.BigToHash(sdk.NewInt(int64(chainID)).BigInt()).Bytes()), out.Res)
+	}
+}
+
+func TestQueryCreateAccessList(t *testing.T) {
+	ctx, _, vmKeeper, _ := keep.CreateTestInput(t, false, 1000000)
+	handler := NewHandler(vmKeeper)
+	querier := NewQuerier(vmKeeper)
+
+	// runtime code reading the slot 0 and the balance of an account
+	code := sdk.FromHex("601c600c600039601c6000f3" + "60005450" + "73" + common.Bytes2Hex(keep.Addrs[3]) + "3150" + "00")
+	contractAddr := CreateAddress(keep.Addrs[0], 0)
+	_, err := handler(ctx, types.NewMsgContract(keep.Addrs[0], nil, code, sdk.NewInt64Coin(sdk.NativeTokenName, 0)))
+	require.NoError(t, err)
+	EndBlocker(ctx, vmKeeper)
+
+	msg := types.NewMsgContractQuery(keep.Addrs[1], contractAddr, sdk.FromHex("00"), sdk.NewInt64Coin(sdk.NativeTokenName, 0))
+	_, err = querier(ctx, []string{types.QueryCreateAccessList}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(msg)})
+	require.True(t, types.ErrAccessListNotSupported.Is(err))
+
+	vmKeeper.SetEVMFork(ctx, types.ForkBerlin)
+	bz, err := querier(ctx, []string{types.QueryCreateAccessList}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(msg)})
+	require.NoError(t, err)
+	var out types.AccessListResult
+	vmKeeper.Cdc.MustUnmarshalJSON(bz, &out)
+	require.Nil(t, out.Err)
+	require.Equal(t, types.AccessList{
+		{Address: contractAddr, StorageKeys: []sdk.Hash{{}}},
+		{Address: keep.Addrs[3]},
+	}, out.AccessList)
+
+	call := func(msg types.MsgContractQuery) types.SimulationResult {
+		bz, err := querier(ctx, []string{types.QueryCall}, abci.RequestQuery{Data: vmKeeper.Cdc.MustMarshalJSON(msg)})
+		require.NoError(t, err)
+		var res types.SimulationResult
+		vmKeeper.Cdc.MustUnmarshalJSON(bz, &res)
+		require.Nil(t, res.Err)
+		return res
+	}
+
+	// the slot and the account are warm: 2000 and 2500 gas are saved on their first
+	// access, the list costs 2 * 2400 + 1900 gas
+	cold := call(msg)
+	msg.AccessList = out.AccessList
+	warm := call(msg)
+	require.Equal(t, out.Gas, warm.Gas)
+	require.Equal(t, cold.Gas+2*TxAccessListAddressGas+TxAccessListStorageKeyGas-2000-2500, warm.Gas)
+}
@@ -18,6 +18,10 @@ type StateTransition struct {
 	Amount    sdk.Int
 	Payload   []byte
 	StateDB   *types.CommitStateDB
+	Tracer    Tracer               // optional, traces the execution when set
+	Overrides types.StateOverrides // optional, applied to the state before the execution of a simulation
+
+	AccessList types.AccessList // optional, pre-warmed and charged before the execution, requires the berlin fork
 }
 
 func (st StateTransition) CanTransfer(acc sdk.AccAddress, amount *big.Int) bool {
@@ -29,18 +33,24 @@ func (st StateTransition) Transfer(from, to sdk.AccAddress, amount *big.Int) {
 	st.StateDB.AddBalance(to, amount)
 }
 
-func (st StateTransition) GetHashFn(header abci.Header) func() sdk.Hash {
-	return func() sdk.Hash {
-		var res = sdk.Hash{}
-		blockID := header.GetLastBlockId()
-		res.SetBytes(blockID.GetHash())
-		return res
+// GetHashFn returns the hash of the block at height n: the hash of the previous block
+// comes with the header, the other ones from the window of block hashes kept in the vm store
+func (st StateTransition) GetHashFn(header abci.Header) func(n uint64) sdk.Hash {
+	return func(n uint64) sdk.Hash {
+		if n+1 == uint64(header.Height) {
+			return sdk.BytesToHash(header.LastBlockId.Hash)
+		}
+		return st.StateDB.GetBlockHash(int64(n))
 	}
 }
 
 func (st StateTransition) TransitionCSDB(ctx sdk.Context, k Keeper) (*big.Int, *sdk.Result, error) {
 	logger := k.Logger(ctx)
 
+	if st.Recipient.Empty() && !k.CanDeploy(ctx, st.Sender) {
+		return nil, &sdk.Result{}, types.ErrDeployNotPermitted
+	}
+
 	evmCtx := Context{
 		CanTransfer: st.CanTransfer,
 		Transfer:    st.Transfer,
@@ -49,11 +59,21 @@ func (st StateTransition) TransitionCSDB(ctx sdk.Context, k Keeper) (*big.Int, *
 		CoinBase:    ctx.BlockHeader().ProposerAddress,
 		Time:        sdk.NewInt(ctx.BlockHeader().Time.Unix()).BigInt(),
 		BlockNumber: sdk.NewInt(ctx.BlockHeader().Height).BigInt(),
+
+		CIPALKeeper:        k.CIPALKeeper,
+		IPALKeeper:         k.IPALKeeper,
+		StakingKeeper:      k.StakingKeeper,
+		DistributionKeeper: k.DistributionKeeper,
 	}
 
+	// an unmetered simulation runs with DefaultVMGasLimit, otherwise the vm gets
+	// the gas left in the gas meter
 	gasLimitForVM := uint64(DefaultVMGasLimit)
-	if !ctx.Simulate {
-		gasLimitForVM = ctx.GasMeter().Limit() - ctx.GasMeter().GasConsumed()
+	if limit := ctx.GasMeter().Limit(); !ctx.Simulate || limit > 0 {
+		gasLimitForVM = 0
+		if consumed := ctx.GasMeter().GasConsumed(); consumed < limit {
+			gasLimitForVM = limit - consumed
+		}
 	}
 	evmCtx.GasLimit = gasLimitForVM
 
@@ -63,13 +83,42 @@ func (st StateTransition) TransitionCSDB(ctx sdk.Context, k Keeper) (*big.Int, *
 	vmParams := k.GetParams(ctx) // will consume gas
 	st.StateDB.UpdateAccounts()  // will consume gas
 
+	// the state db of the evm doesn't charge the tx gas meter, reading the fork is free
+	stateDB := st.StateDB.WithContext(ctx.WithGasMeter(gasMeterForEvm))
+	rules := GetForkRules(stateDB.GetEVMFork())
+	if len(st.AccessList) > 0 {
+		if !rules.IsBerlin {
+			return nil, &sdk.Result{}, types.ErrAccessListNotSupported
+		}
+
+		// the access list is paid for from the gas given to the vm
+		accessGas := accessListGas(st.AccessList)
+		curGasMeter.ConsumeGas(accessGas, "access list")
+		if accessGas > gasLimitForVM {
+			gasLimitForVM = 0
+		} else {
+			gasLimitForVM -= accessGas
+		}
+		evmCtx.GasLimit = gasLimitForVM
+	}
+
 	cfg := Config{
-		OpConstGasConfig:          &vmParams.VMOpGasParams,
+		Rules:                     rules,
+		OpConstGasConfig:          rules.OpConstGas(vmParams.VMOpGasParams),
 		ContractCreationGasConfig: &vmParams.VMContractCreationGasParams,
 		MaxCodeSize:               vmParams.MaxCodeSize,
 		MaxCallCreateDepth:        vmParams.MaxCallCreateDepth,
+		Debug:                     st.Tracer != nil,
+		Tracer:                    st.Tracer,
+	}
+	chainCfg := ChainConfig{ChainID: vmParams.EVMChainID}
+	evm := NewEVM(evmCtx, stateDB, chainCfg, cfg)
+	if ctx.Simulate {
+		st.Overrides.Apply(st.StateDB)
+	}
+	if rules.IsBerlin {
+		st.StateDB.PrepareAccessList(st.Sender, st.Recipient, rules.PrecompiledAddresses(), st.AccessList)
 	}
-	evm := NewEVM(evmCtx, st.StateDB.WithContext(ctx.WithGasMeter(gasMeterForEvm)), cfg)
 
 	var (
 		ret         []byte
@@ -77,19 +126,12 @@ func (st StateTransition) TransitionCSDB(ctx sdk.Context, k Keeper) (*big.Int, *
 		vmerr       error
 	)
 
+	contractAddr := st.Recipient
 	if st.Recipient.Empty() {
-		ret, _, leftOverGas, vmerr = evm.Create(st.Sender, st.Payload, gasLimitForVM, st.Amount.BigInt())
+		ret, contractAddr, leftOverGas, vmerr = evm.Create(st.Sender, st.Payload, gasLimitForVM, st.Amount.BigInt())
 		logger.Info(fmt.Sprintf("create contract, consumed gas = %v, leftOverGas = %v, vm err = %v ", gasLimitForVM-leftOverGas, leftOverGas, vmerr))
 	} else {
 		ret, leftOverGas, vmerr = evm.Call(st.Sender, st.Recipient, st.Payload, gasLimitForVM, st.Amount.BigInt())
-		if vmerr == ErrExecutionReverted {
-			reason := "null"
-			if len(ret) > 4 {
-				reason = string(ret[4:])
-			}
-			logger.Info(fmt.Sprintf("VM revert error, reason provided by the contract: %s", reason))
-		}
-
 		logger.Info(fmt.Sprintf("call contract, ret = %x, consumed gas = %v, leftOverGas = %v, vm err = %v", ret, gasLimitForVM-leftOverGas, leftOverGas, vmerr))
 	}
 
@@ -98,24 +140,66 @@ func (st StateTransition) TransitionCSDB(ctx sdk.Context, k Keeper) (*big.Int, *
 	if vmerr != nil {
 		ctx.EventManager().Clear()
 		ctx.WithGasMeter(curGasMeter).GasMeter().ConsumeGas(vmGasUsed, "VM execution consumption")
-		return nil, &sdk.Result{Data: ret, GasUsed: curGasMeter.GasConsumed()}, vmerr
+
+		if failing := evm.FailingContract(); failing != nil {
+			contractAddr = failing
+		}
+		execErr := types.NewExecutionError(vmerr, ret, curGasMeter.GasConsumed(), contractAddr)
+		if execErr.Reason != "" {
+			logger.Info(fmt.Sprintf("VM revert error, reason provided by the contract: %s", execErr.Reason))
+		}
+
+		ctx.EventManager().EmitEvents(execErr.Events())
+		return nil, &sdk.Result{Data: ret, GasUsed: curGasMeter.GasConsumed(), Events: ctx.EventManager().Events()}, execErr
+	}
+
+	refund := refundGas(st.StateDB.GetRefund(), curGasMeter.GasConsumed()+vmGasUsed, vmGasUsed, rules.RefundQuotient(vmParams.RefundQuotient))
+	if refund > 0 {
+		logger.Info(fmt.Sprintf("refund gas = %v, refund counter = %v", refund, st.StateDB.GetRefund()))
 	}
 
 	st.StateDB.Finalise(true)
 
 	// comsume vm gas
-	ctx.WithGasMeter(curGasMeter).GasMeter().ConsumeGas(vmGasUsed, "VM execution consumption")
+	ctx.WithGasMeter(curGasMeter).GasMeter().ConsumeGas(vmGasUsed-refund, "VM execution consumption")
 
 	return nil, &sdk.Result{Data: ret, GasUsed: ctx.GasMeter().GasConsumed()}, nil
 }
 
+// refundGas returns the part of the SSTORE and SELFDESTRUCT refund counter given back
+// to the sender: at most gasUsed / quotient as in Ethereum, and no more than the gas
+// used by the vm since the gas consumed before the execution can't be given back.
+// A zero quotient disables the refund.
+func refundGas(refundCounter, gasUsed, vmGasUsed, quotient uint64) uint64 {
+	if quotient == 0 {
+		return 0
+	}
+
+	refund := gasUsed / quotient
+	if refund > refundCounter {
+		refund = refundCounter
+	}
+	if refund > vmGasUsed {
+		refund = vmGasUsed
+	}
+
+	return refund
+}
+
 func DoStateTransition(ctx sdk.Context, msg types.MsgContract, k Keeper, readonly bool) (*big.Int, *sdk.Result, error) {
+	return doStateTransition(ctx, msg, k, readonly, nil)
+}
+
+// doStateTransition runs the state transition of msg, traced by tracer if not nil
+func doStateTransition(ctx sdk.Context, msg types.MsgContract, k Keeper, readonly bool, tracer Tracer) (*big.Int, *sdk.Result, error) {
 	st := StateTransition{
-		Sender:    msg.From,
-		Recipient: msg.To,
-		Payload:   msg.Payload,
-		Amount:    msg.Amount.Amount,
-		StateDB:   k.StateDB.WithContext(ctx).WithTxHash(tmhash.Sum(ctx.TxBytes())),
+		Sender:     msg.From,
+		Recipient:  msg.To,
+		Payload:    msg.Payload,
+		Amount:     msg.Amount.Amount,
+		StateDB:    k.StateDB.WithContext(ctx).WithTxHash(tmhash.Sum(ctx.TxBytes())),
+		Tracer:     tracer,
+		AccessList: msg.AccessList,
 	}
 
 	if readonly {
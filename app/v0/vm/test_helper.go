@@ -16,6 +16,7 @@ func newEVM() *EVM {
 	authKey := sdk.NewKVStoreKey(auth.StoreKey)
 	paramsKey := sdk.NewKVStoreKey(params.StoreKey)
 	tParamsKey := sdk.NewTransientStoreKey(params.TStoreKey)
+	tKey := sdk.NewTransientStoreKey(types.TStoreKey)
 
 	paramsKeeper := params.NewKeeper(types.ModuleCdc, paramsKey, tParamsKey)
 	accountKeeper := auth.NewAccountKeeper(types.ModuleCdc, authKey, paramsKeeper.Subspace(auth.DefaultParamspace), auth.ProtoBaseAccount)
@@ -25,7 +26,8 @@ func newEVM() *EVM {
 
 	ms := store.NewCommitMultiStore(db)
 	ms.MountStoreWithDB(authKey, sdk.StoreTypeDB, db)
+	ms.MountStoreWithDB(tKey, sdk.StoreTypeTransient, nil)
 	ms.LoadLatestVersion()
 
-	return NewEVM(Context{}, NewCommitStateDB(accountKeeper, authKey).WithContext(sdk.NewContext(ms, abci.Header{}, false, logger)), Config{})
+	return NewEVM(Context{}, NewCommitStateDB(accountKeeper, authKey, tKey).WithContext(sdk.NewContext(ms, abci.Header{}, false, logger)), ChainConfig{}, Config{})
 }
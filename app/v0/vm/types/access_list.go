@@ -0,0 +1,147 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// AccessTuple is an account and the storage slots of it declared by an access list
+type AccessTuple struct {
+	Address     sdk.AccAddress `json:"address" yaml:"address"`
+	StorageKeys []sdk.Hash     `json:"storage_keys" yaml:"storage_keys"`
+}
+
+// AccessList is the EIP-2930 list of the accounts and storage slots a MsgContract
+// declares to access, they are warm from the start of its execution
+type AccessList []AccessTuple
+
+// StorageKeys returns the number of storage slots in the access list
+func (al AccessList) StorageKeys() int {
+	sum := 0
+	for _, tuple := range al {
+		sum += len(tuple.StorageKeys)
+	}
+
+	return sum
+}
+
+// Validate checks that every entry of the access list has an address
+func (al AccessList) Validate() error {
+	for _, tuple := range al {
+		if tuple.Address.Empty() {
+			return fmt.Errorf("access list entry without address")
+		}
+	}
+
+	return nil
+}
+
+// accessList is the EIP-2929 set of the addresses and storage slots accessed by a
+// transaction, the accesses to the ones in the set are warm
+type accessList struct {
+	addresses map[string]int
+	slots     []map[sdk.Hash]struct{}
+}
+
+// newAccessList creates a new accessList
+func newAccessList() *accessList {
+	return &accessList{
+		addresses: make(map[string]int),
+	}
+}
+
+// ContainsAddress returns true if the address is in the access list
+func (al *accessList) ContainsAddress(address sdk.AccAddress) bool {
+	_, ok := al.addresses[address.String()]
+	return ok
+}
+
+// Contains checks if a slot within an account is present in the access list,
+// returning separate flags for the presence of the account and the slot
+func (al *accessList) Contains(address sdk.AccAddress, slot sdk.Hash) (addressPresent bool, slotPresent bool) {
+	idx, ok := al.addresses[address.String()]
+	if !ok {
+		// no such address (and hence zero slots)
+		return false, false
+	}
+	if idx == -1 {
+		// address yes, but no slots
+		return true, false
+	}
+	_, slotPresent = al.slots[idx][slot]
+	return true, slotPresent
+}
+
+// Copy creates an independent copy of an accessList
+func (al *accessList) Copy() *accessList {
+	cp := newAccessList()
+	for k, v := range al.addresses {
+		cp.addresses[k] = v
+	}
+	cp.slots = make([]map[sdk.Hash]struct{}, len(al.slots))
+	for i, slotMap := range al.slots {
+		newSlotmap := make(map[sdk.Hash]struct{}, len(slotMap))
+		for k := range slotMap {
+			newSlotmap[k] = struct{}{}
+		}
+		cp.slots[i] = newSlotmap
+	}
+	return cp
+}
+
+// AddAddress adds an address to the access list, and returns 'true' if the operation
+// caused a change (addr was not previously in the list)
+func (al *accessList) AddAddress(address sdk.AccAddress) bool {
+	if _, present := al.addresses[address.String()]; present {
+		return false
+	}
+	al.addresses[address.String()] = -1
+	return true
+}
+
+// AddSlot adds the specified (addr, slot) combo to the access list. The return
+// values tell whether the address and the slot were added by the operation
+func (al *accessList) AddSlot(address sdk.AccAddress, slot sdk.Hash) (addrChange bool, slotChange bool) {
+	idx, addrPresent := al.addresses[address.String()]
+	if !addrPresent || idx == -1 {
+		// Address not present, or addr present but no slots there
+		al.addresses[address.String()] = len(al.slots)
+		slotmap := map[sdk.Hash]struct{}{slot: {}}
+		al.slots = append(al.slots, slotmap)
+		return !addrPresent, true
+	}
+	// There is already an (address,slot) mapping
+	slotmap := al.slots[idx]
+	if _, ok := slotmap[slot]; !ok {
+		slotmap[slot] = struct{}{}
+		return false, true
+	}
+	// No changes required
+	return false, false
+}
+
+// DeleteSlot removes an (address, slot)-tuple from the access list, it is only
+// meant to revert an AddSlot made right before
+func (al *accessList) DeleteSlot(address sdk.AccAddress, slot sdk.Hash) {
+	idx, addrOk := al.addresses[address.String()]
+	// There are two ways this can fail
+	if !addrOk {
+		panic("reverting slot change, address not present in list")
+	}
+	slotmap := al.slots[idx]
+	delete(slotmap, slot)
+	// If that was the last (first) slot, remove it
+	// Since additions and rollbacks are always performed in order,
+	// we can delete the item last added, which is also the last in the slots list
+	if len(slotmap) == 0 {
+		al.slots = al.slots[:idx]
+		al.addresses[address.String()] = -1
+	}
+}
+
+// DeleteAddress removes an address from the access list, it is only meant to
+// revert an AddAddress made right before
+func (al *accessList) DeleteAddress(address sdk.AccAddress) {
+	delete(al.addresses, address.String())
+}
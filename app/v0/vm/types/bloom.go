@@ -0,0 +1,113 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/netcloth/netcloth-chain/hexutil"
+)
+
+// BloomByteLength is the length of a logs bloom in bytes, 2048 bits
+const BloomByteLength = 256
+
+// Bloom is a 2048-bit bloom filter of the addresses and topics of contract logs,
+// computed as in Ethereum: each item sets 3 bits picked from its keccak256 hash.
+type Bloom [BloomByteLength]byte
+
+// BytesToBloom converts a byte slice to a bloom filter, the slice is left padded or
+// truncated to BloomByteLength
+func BytesToBloom(b []byte) (bloom Bloom) {
+	if len(b) > BloomByteLength {
+		b = b[len(b)-BloomByteLength:]
+	}
+	copy(bloom[BloomByteLength-len(b):], b)
+	return
+}
+
+// Add adds an address or a topic to the bloom filter
+func (b *Bloom) Add(d []byte) {
+	idx, bits := bloomValues(d)
+	for i := range idx {
+		b[idx[i]] |= bits[i]
+	}
+}
+
+// Or merges another bloom filter into b
+func (b *Bloom) Or(other Bloom) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+// Test returns whether an address or a topic may be in the bloom filter. False
+// positives are possible, false negatives aren't.
+func (b Bloom) Test(d []byte) bool {
+	idx, bits := bloomValues(d)
+	for i := range idx {
+		if b[idx[i]]&bits[i] != bits[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Empty returns whether no item was added to the bloom filter
+func (b Bloom) Empty() bool {
+	return b == Bloom{}
+}
+
+// Bytes returns the bloom filter as a byte slice
+func (b Bloom) Bytes() []byte {
+	return b[:]
+}
+
+func (b Bloom) String() string {
+	return hexutil.Encode(b[:])
+}
+
+// MarshalJSON encodes the bloom filter as a hex string
+func (b Bloom) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hexutil.Bytes(b[:]))
+}
+
+// UnmarshalJSON decodes a hex encoded bloom filter
+func (b *Bloom) UnmarshalJSON(data []byte) error {
+	var bz hexutil.Bytes
+	if err := json.Unmarshal(data, &bz); err != nil {
+		return err
+	}
+
+	if len(bz) != BloomByteLength {
+		return fmt.Errorf("invalid bloom length: %d, expected %d", len(bz), BloomByteLength)
+	}
+
+	copy(b[:], bz)
+	return nil
+}
+
+// LogsBloom returns the bloom filter of the addresses and topics of the logs
+func LogsBloom(logs []*Log) (bloom Bloom) {
+	for _, log := range logs {
+		bloom.Add(log.Address.Bytes())
+		for _, topic := range log.Topics {
+			bloom.Add(topic.Bytes())
+		}
+	}
+
+	return
+}
+
+// bloomValues returns the bytes and the bits within them set by an item
+func bloomValues(data []byte) (idx [3]uint, bits [3]byte) {
+	h := ethcrypto.Keccak256(data)
+	for i := 0; i < 3; i++ {
+		bit := (uint(h[2*i])<<8 | uint(h[2*i+1])) & 2047
+		idx[i] = BloomByteLength - 1 - bit/8
+		bits[i] = byte(1) << (bit % 8)
+	}
+
+	return
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestLogsBloom(t *testing.T) {
+	addr := sdk.AccAddress(common.HexToAddress("0x00000000000000000000000000000000000000aa").Bytes())
+	topic := sdk.HexToHash("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+	bloom := LogsBloom([]*Log{{Address: addr, Topics: []sdk.Hash{topic}}})
+	require.False(t, bloom.Empty())
+	require.True(t, bloom.Test(addr.Bytes()))
+	require.True(t, bloom.Test(topic.Bytes()))
+	require.False(t, bloom.Test([]byte("absent")))
+
+	// same bits as the Ethereum bloom
+	ethBloom := ethtypes.BytesToBloom(ethtypes.LogsBloom([]*ethtypes.Log{{
+		Address: common.BytesToAddress(addr.Bytes()),
+		Topics:  []common.Hash{common.BytesToHash(topic.Bytes())},
+	}}).Bytes())
+	require.Equal(t, ethBloom.Bytes(), bloom.Bytes())
+
+	var merged Bloom
+	merged.Or(bloom)
+	require.Equal(t, bloom, merged)
+	require.True(t, Bloom{}.Empty())
+
+	bz, err := ModuleCdc.MarshalJSON(bloom)
+	require.NoError(t, err)
+	var decoded Bloom
+	require.NoError(t, ModuleCdc.UnmarshalJSON(bz, &decoded))
+	require.Equal(t, bloom, decoded)
+}
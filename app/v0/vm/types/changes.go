@@ -57,6 +57,19 @@ type (
 	touchChange struct {
 		account *sdk.AccAddress
 	}
+
+	// changes to the NetCloth modules made by a native contract
+	nativeChange struct{}
+
+	// changes to the access list
+	accessListAddAccountChange struct {
+		address *sdk.AccAddress
+	}
+
+	accessListAddSlotChange struct {
+		address *sdk.AccAddress
+		slot    *sdk.Hash
+	}
 )
 
 // createObjectChange
@@ -169,3 +182,32 @@ func (ch addPreimageChange) revert(s *CommitStateDB) {
 func (ch addPreimageChange) dirtied() *sdk.AccAddress {
 	return nil
 }
+
+// nativeChange
+func (ch nativeChange) revert(s *CommitStateDB) {
+	last := len(s.nativeCaches) - 1
+	s.ctx = s.nativeCaches[last].prev
+	s.nativeCaches = s.nativeCaches[:last]
+}
+
+func (ch nativeChange) dirtied() *sdk.AccAddress {
+	return nil
+}
+
+// accessListAddAccountChange
+func (ch accessListAddAccountChange) revert(s *CommitStateDB) {
+	s.accessList.DeleteAddress(*ch.address)
+}
+
+func (ch accessListAddAccountChange) dirtied() *sdk.AccAddress {
+	return nil
+}
+
+// accessListAddSlotChange
+func (ch accessListAddSlotChange) revert(s *CommitStateDB) {
+	s.accessList.DeleteSlot(*ch.address, *ch.slot)
+}
+
+func (ch accessListAddSlotChange) dirtied() *sdk.AccAddress {
+	return nil
+}
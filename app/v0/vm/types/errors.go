@@ -1,6 +1,10 @@
 package types
 
 import (
+	"fmt"
+
+	"github.com/netcloth/netcloth-chain/hexutil"
+	sdk "github.com/netcloth/netcloth-chain/types"
 	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
 )
 
@@ -22,4 +26,83 @@ var (
 	ErrInvalidJump              = sdkerrors.New(ModuleName, 15, "evm: invalid jump destination")
 	ErrGasUintOverflow          = sdkerrors.New(ModuleName, 16, "gas uint64 overflow")
 	ErrWrongCtx                 = sdkerrors.New(ModuleName, 17, "must be simulate mode when gas limit is 0")
+	ErrDeployNotPermitted       = sdkerrors.New(ModuleName, 18, "sender is not allowed to deploy contracts")
+	ErrInvalidCode              = sdkerrors.New(ModuleName, 19, "evm: invalid code: must not begin with 0xef")
+	ErrAccessListNotSupported   = sdkerrors.New(ModuleName, 20, "access list not supported before the berlin fork")
+	ErrLogIndexDisabled         = sdkerrors.New(ModuleName, 21, "the contract log index is disabled on this node")
 )
+
+// ExecutionError is returned when the vm execution of a MsgContract fails. Its ABCI
+// code is the one of the vm error it is caused by, and it carries the details of the
+// failure: the decoded revert reason, the raw revert data, the gas used by the tx and
+// the contract the failure originated from.
+type ExecutionError struct {
+	cause error
+
+	Codespace string         `json:"codespace" yaml:"codespace"`
+	Code      uint32         `json:"code" yaml:"code"`
+	Message   string         `json:"message" yaml:"message"`
+	Reason    string         `json:"reason,omitempty" yaml:"reason"`
+	Data      hexutil.Bytes  `json:"data,omitempty" yaml:"data"`
+	GasUsed   uint64         `json:"gas_used" yaml:"gas_used"`
+	Contract  sdk.AccAddress `json:"contract" yaml:"contract"`
+}
+
+// NewExecutionError returns an ExecutionError caused by the vm error err, the revert
+// reason is decoded from the returned data
+func NewExecutionError(err error, ret []byte, gasUsed uint64, contract sdk.AccAddress) *ExecutionError {
+	codespace, code, _ := sdkerrors.ABCIInfo(err, false)
+	e := &ExecutionError{
+		cause:     err,
+		Codespace: codespace,
+		Code:      code,
+		Message:   err.Error(),
+		GasUsed:   gasUsed,
+		Contract:  contract,
+	}
+
+	if err == ErrExecutionReverted {
+		e.Reason = UnpackRevertReason(ret)
+		e.Data = ret
+	}
+
+	return e
+}
+
+func (e *ExecutionError) Error() string {
+	msg := e.Message
+	if e.Reason != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Reason)
+	}
+
+	return fmt.Sprintf("%s; contract: %s, gas used: %d, data: %s", msg, e.Contract, e.GasUsed, e.Data)
+}
+
+// Reverted returns whether the execution was reverted by the contract
+func (e *ExecutionError) Reverted() bool {
+	return e.Codespace == ErrExecutionReverted.Codespace() && e.Code == ErrExecutionReverted.ABCICode()
+}
+
+// Cause returns the vm error, which gives the ABCI code and codespace of the error
+func (e *ExecutionError) Cause() error {
+	return e.cause
+}
+
+// Unwrap returns the vm error
+func (e *ExecutionError) Unwrap() error {
+	return e.cause
+}
+
+// Events returns the event describing the failure
+func (e *ExecutionError) Events() sdk.Events {
+	return sdk.Events{
+		sdk.NewEvent(
+			EventTypeExecutionError,
+			sdk.NewAttribute(AttributeKeyError, e.Message),
+			sdk.NewAttribute(AttributeKeyReason, e.Reason),
+			sdk.NewAttribute(AttributeKeyData, e.Data.String()),
+			sdk.NewAttribute(AttributeKeyGasUsed, fmt.Sprintf("%d", e.GasUsed)),
+			sdk.NewAttribute(AttributeKeyContract, e.Contract.String()),
+		),
+	}
+}
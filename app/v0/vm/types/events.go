@@ -1,8 +1,18 @@
 package types
 
 const (
-	EventTypeNewContract = "new_contract"
+	EventTypeNewContract    = "new_contract"
+	EventTypeExecutionError = "execution_error"
+	EventTypeLogsBloom      = "logs_bloom"
+	EventTypeEthereumTx     = "ethereum_tx"
 
 	AttributeKeyAddress    = "address"
+	AttributeKeyError      = "error"
+	AttributeKeyReason     = "reason"
+	AttributeKeyData       = "data"
+	AttributeKeyGasUsed    = "gas_used"
+	AttributeKeyContract   = "contract"
+	AttributeKeyBloom      = "bloom"
+	AttributeKeyHash       = "hash"
 	AttributeValueCategory = "vm"
 )
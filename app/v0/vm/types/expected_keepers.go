@@ -1,7 +1,13 @@
 package types
 
 import (
+	"time"
+
 	"github.com/netcloth/netcloth-chain/app/v0/auth/exported"
+	cipaltypes "github.com/netcloth/netcloth-chain/app/v0/cipal/types"
+	guardiantypes "github.com/netcloth/netcloth-chain/app/v0/guardian/types"
+	ipaltypes "github.com/netcloth/netcloth-chain/app/v0/ipal/types"
+	stakingtypes "github.com/netcloth/netcloth-chain/app/v0/staking/types"
 	sdk "github.com/netcloth/netcloth-chain/types"
 )
 
@@ -17,3 +23,37 @@ type AccountKeeper interface {
 type BankKeeper interface {
 	SendCoins(ctx sdk.Context, fromAddr sdk.AccAddress, toAddr sdk.AccAddress, amt sdk.Coins) error
 }
+
+// CIPALKeeper defines the expected cipal keeper read by the CIPAL native contract
+type CIPALKeeper interface {
+	GetCIPALObject(ctx sdk.Context, userAddress string) (obj cipaltypes.CIPALObject, found bool)
+}
+
+// IPALKeeper defines the expected ipal keeper read by the IPAL native contract
+type IPALKeeper interface {
+	GetIPALNode(ctx sdk.Context, operator sdk.AccAddress) (obj ipaltypes.IPALNode, found bool)
+	GetAllIPALNodes(ctx sdk.Context) (ipalNodes ipaltypes.IPALNodes)
+}
+
+// StakingKeeper defines the expected staking keeper used by the staking native contract
+type StakingKeeper interface {
+	BondDenom(ctx sdk.Context) string
+	GetValidator(ctx sdk.Context, addr sdk.ValAddress) (validator stakingtypes.Validator, found bool)
+	GetDelegation(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) (delegation stakingtypes.Delegation, found bool)
+	Delegate(ctx sdk.Context, delAddr sdk.AccAddress, bondAmt sdk.Int, tokenSrc sdk.BondStatus,
+		validator stakingtypes.Validator, subtractAccount bool) (newShares sdk.Dec, err error)
+	ValidateUnbondAmount(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress, amt sdk.Int) (shares sdk.Dec, err error)
+	Undelegate(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress, sharesAmount sdk.Dec) (time.Time, error)
+	BeginRedelegation(ctx sdk.Context, delAddr sdk.AccAddress, valSrcAddr, valDstAddr sdk.ValAddress,
+		sharesAmount sdk.Dec) (completionTime time.Time, err error)
+}
+
+// DistributionKeeper defines the expected distribution keeper used by the staking native contract
+type DistributionKeeper interface {
+	WithdrawDelegationRewards(ctx sdk.Context, delAddr sdk.AccAddress, valAddr sdk.ValAddress) (sdk.Coins, error)
+}
+
+// GuardianKeeper defines the expected guardian keeper holding the accounts allowed to deploy contracts
+type GuardianKeeper interface {
+	GetDeployer(ctx sdk.Context, addr sdk.AccAddress) (deployer guardiantypes.Guardian, found bool)
+}
@@ -0,0 +1,104 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// Names of the EVM forks, each one selects the instruction set and the gas rules the vm runs with
+const (
+	ForkIstanbul = "istanbul" // the rules the vm started with
+	ForkBerlin   = "berlin"   // Istanbul with the EIP-2929 cold and warm state accesses
+	ForkLondon   = "london"   // Berlin with the EIP-3529 refund reduction and EIP-3541, without the base fee
+)
+
+// EVMFork schedules the activation of a fork at a block height. The fork is
+// activated by the EndBlocker of the upgrade module at Height, like a software
+// upgrade: the txs of the block Height+1 are the first ones it runs.
+type EVMFork struct {
+	Name   string `json:"name" yaml:"name"`
+	Height uint64 `json:"height" yaml:"height"`
+}
+
+// EVMForks is the schedule of the EVM forks, ordered by activation height
+type EVMForks []EVMFork
+
+// At returns the name of the fork scheduled at height, the last one activated at
+// or below it, Istanbul if there is none
+func (forks EVMForks) At(height uint64) string {
+	name := ForkIstanbul
+	for _, fork := range forks {
+		if fork.Height > height {
+			break
+		}
+		name = fork.Name
+	}
+
+	return name
+}
+
+// ValidateUpdate checks a param change of the schedule against the current one: the
+// forks scheduled at or below the current height, activated already, can't be removed
+// or moved, and the forks it adds must be scheduled above the current height. A chain
+// without the param runs the default schedule.
+func (forks EVMForks) ValidateUpdate(ctx sdk.Context, current interface{}) error {
+	currentForks, ok := current.(EVMForks)
+	if !ok {
+		return fmt.Errorf("EVMForks'type must be EVMForks: %T", current)
+	}
+	if len(currentForks) == 0 {
+		currentForks = defaultEVMForks
+	}
+
+	height := uint64(ctx.BlockHeight())
+	activated := 0
+	for _, fork := range currentForks {
+		if fork.Height > height {
+			break
+		}
+
+		if activated >= len(forks) || forks[activated] != fork {
+			return fmt.Errorf("evm fork %s activated at %d can't be removed or moved", fork.Name, fork.Height)
+		}
+		activated++
+	}
+
+	for _, fork := range forks[activated:] {
+		if fork.Height <= height {
+			return fmt.Errorf("evm fork %s must be scheduled above the current height %d, got %d", fork.Name, height, fork.Height)
+		}
+	}
+
+	return nil
+}
+
+// IsValidEVMFork returns whether name is the name of a known fork
+func IsValidEVMFork(name string) bool {
+	switch name {
+	case ForkIstanbul, ForkBerlin, ForkLondon:
+		return true
+	default:
+		return false
+	}
+}
+
+func validateEVMForks(i interface{}) error {
+	forks, ok := i.(EVMForks)
+	if !ok {
+		return fmt.Errorf("EVMForks'type must be EVMForks: %T", i)
+	}
+
+	for i, fork := range forks {
+		if !IsValidEVMFork(fork.Name) {
+			return fmt.Errorf("unknown evm fork: %s", fork.Name)
+		}
+
+		if i > 0 && fork.Height <= forks[i-1].Height {
+			return fmt.Errorf("evm forks must be scheduled at increasing heights: %s at %d after %s at %d",
+				fork.Name, fork.Height, forks[i-1].Name, forks[i-1].Height)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestEVMForksAt(t *testing.T) {
+	forks := EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 10}, {Name: ForkLondon, Height: 20}}
+	require.Equal(t, ForkIstanbul, forks.At(9))
+	require.Equal(t, ForkBerlin, forks.At(10))
+	require.Equal(t, ForkBerlin, forks.At(19))
+	require.Equal(t, ForkLondon, forks.At(100))
+
+	require.Equal(t, ForkIstanbul, EVMForks(nil).At(100))
+}
+
+func TestValidateEVMForks(t *testing.T) {
+	require.NoError(t, validateEVMForks(EVMForks(nil)))
+	require.NoError(t, validateEVMForks(defaultEVMForks))
+	require.NoError(t, validateEVMForks(EVMForks{{Name: ForkBerlin, Height: 5}, {Name: ForkLondon, Height: 6}}))
+
+	require.Error(t, validateEVMForks(EVMForks{{Name: "frontier", Height: 0}}))
+	require.Error(t, validateEVMForks(EVMForks{{Name: ForkBerlin, Height: 5}, {Name: ForkLondon, Height: 5}}))
+	require.Error(t, validateEVMForks([]EVMFork{}))
+}
+
+func TestEVMForksValidateUpdate(t *testing.T) {
+	ctx := sdk.Context{}.WithBlockHeight(10)
+	current := EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 10}, {Name: ForkLondon, Height: 20}}
+
+	// the forks above the current height can be moved, removed or added
+	require.NoError(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 10}, {Name: ForkLondon, Height: 11}}.ValidateUpdate(ctx, current))
+	require.NoError(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 10}}.ValidateUpdate(ctx, current))
+	require.NoError(t, current.ValidateUpdate(ctx, current))
+
+	// the activated forks can't be removed or moved
+	require.Error(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkLondon, Height: 20}}.ValidateUpdate(ctx, current))
+	require.Error(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 11}}.ValidateUpdate(ctx, current))
+	require.Error(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 9}}.ValidateUpdate(ctx, current))
+	require.Error(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkLondon, Height: 10}}.ValidateUpdate(ctx, current))
+
+	// the new forks must be scheduled above the current height
+	current = EVMForks{{Name: ForkIstanbul, Height: 0}}
+	require.Error(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 10}}.ValidateUpdate(ctx, current))
+	require.NoError(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 11}}.ValidateUpdate(ctx, current))
+
+	// a chain without the param runs the default schedule
+	require.NoError(t, EVMForks{{Name: ForkIstanbul, Height: 0}, {Name: ForkBerlin, Height: 11}}.ValidateUpdate(ctx, EVMForks(nil)))
+	require.Error(t, EVMForks{{Name: ForkBerlin, Height: 11}}.ValidateUpdate(ctx, EVMForks(nil)))
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/netcloth/netcloth-chain/hexutil"
 	sdk "github.com/netcloth/netcloth-chain/types"
@@ -14,6 +15,10 @@ type (
 		Storage []Storage           `json:"storage"`
 		Codes   map[string]sdk.Code `json:"codes"`
 		VMLogs  VMLogs              `json:"vm_logs"`
+
+		BlockHashes []BlockHash `json:"block_hashes"`
+
+		EVMFork string `json:"evm_fork,omitempty"` // fork the vm runs with, the one scheduled at height 0 by the params if empty
 	}
 
 	// Storage vm storage of k, v pairs
@@ -27,6 +32,12 @@ type (
 		Logs     map[string]string `json:"logs"`
 		LogIndex int64             `json:"log_index"`
 	}
+
+	// BlockHash hash of the block at height, the window of the most recent ones is reachable by BLOCKHASH
+	BlockHash struct {
+		Height int64         `json:"height"`
+		Hash   hexutil.Bytes `json:"hash"`
+	}
 )
 
 func DefaultGenesisState() GenesisState {
@@ -54,7 +65,55 @@ func ValidateGenesis(data GenesisState) error {
 		return err
 	}
 
-	return validateVMCommonGasParams(data.Params.VMContractCreationGasParams)
+	if err := validateVMCommonGasParams(data.Params.VMContractCreationGasParams); err != nil {
+		return err
+	}
+
+	if err := validateRefundQuotient(data.Params.RefundQuotient); err != nil {
+		return err
+	}
+
+	if err := validateEVMChainID(data.Params.EVMChainID); err != nil {
+		return err
+	}
+
+	if err := validateDeployPermission(data.Params.DeployPermission); err != nil {
+		return err
+	}
+
+	if err := validateEVMForks(data.Params.EVMForks); err != nil {
+		return err
+	}
+
+	if data.EVMFork != "" && !IsValidEVMFork(data.EVMFork) {
+		return fmt.Errorf("unknown evm fork: %s", data.EVMFork)
+	}
+
+	return validateBlockHashes(data.BlockHashes)
+}
+
+func validateBlockHashes(blockHashes []BlockHash) error {
+	if len(blockHashes) > BlockHashWindow {
+		return fmt.Errorf("too many block hashes: %d, at most %d", len(blockHashes), BlockHashWindow)
+	}
+
+	heights := make(map[int64]bool, len(blockHashes))
+	for _, blockHash := range blockHashes {
+		if blockHash.Height <= 0 {
+			return fmt.Errorf("invalid block hash height: %d", blockHash.Height)
+		}
+
+		if len(blockHash.Hash) != sdk.HashLength {
+			return fmt.Errorf("invalid block hash length at height %d: %d", blockHash.Height, len(blockHash.Hash))
+		}
+
+		if heights[blockHash.Height] {
+			return fmt.Errorf("duplicate block hash at height %d", blockHash.Height)
+		}
+		heights[blockHash.Height] = true
+	}
+
+	return nil
 }
 
 // Equal judge GenesisState equal
@@ -8,12 +8,14 @@ import (
 const (
 	ModuleName   = protocol.VMModuleName
 	StoreKey     = ModuleName
+	TStoreKey    = protocol.VMTStoreKey
 	QuerierRoute = ModuleName
 	RouterKey    = ModuleName
 )
 
 var (
 	LogIndexKey = []byte("logIndexKey")
+	EVMForkKey  = []byte("evmForkKey")
 )
 
 // KVStore key prefixes
@@ -22,9 +24,72 @@ var (
 	KeyPrefixLogsIndex = []byte{0x02}
 	KeyPrefixCode      = []byte{0x03}
 	KeyPrefixStorage   = []byte{0x04}
+	KeyPrefixBlockHash = []byte{0x05}
+
+	// 0x06 to 0x09 were used by the log indexes, now kept in the node-local LogIndex
 )
 
+// LogIndex key prefixes, the values of the log indexes are the hashes of the txs emitting the logs
+var (
+	KeyPrefixLogsByAddress = []byte{0x06}
+	KeyPrefixLogsByTopic   = []byte{0x07}
+	KeyPrefixLogsByHeight  = []byte{0x08}
+
+	KeyPrefixBloom = []byte{0x09}
+)
+
+// MaxLogTopics is the number of topic positions of a log, LOG0 to LOG4
+const MaxLogTopics = 4
+
+// BlockHashWindow is the number of most recent block hashes reachable by the BLOCKHASH opcode
+const BlockHashWindow = 256
+
 // AddressStoragePrefix returns a prefix to iterate over a given account storage.
 func AddressStoragePrefix(address sdk.Address) []byte {
 	return append(KeyPrefixStorage, address.Bytes()...)
 }
+
+// BlockHashKey returns the key of the block hash at the given height
+func BlockHashKey(height int64) []byte {
+	return append(KeyPrefixBlockHash, sdk.Uint64ToBigEndian(uint64(height))...)
+}
+
+// LogsByAddressPrefix returns a prefix to iterate over the logs emitted by a given contract
+func LogsByAddressPrefix(address sdk.AccAddress) []byte {
+	return append(append([]byte{}, KeyPrefixLogsByAddress...), address.Bytes()...)
+}
+
+// LogsByAddressKey returns the address index key of a log
+func LogsByAddressKey(address sdk.AccAddress, height, index uint64) []byte {
+	return append(LogsByAddressPrefix(address), logPositionKey(height, index)...)
+}
+
+// LogsByTopicPrefix returns a prefix to iterate over the logs carrying a topic at a given position
+func LogsByTopicPrefix(position int, topic sdk.Hash) []byte {
+	return append(append(append([]byte{}, KeyPrefixLogsByTopic...), byte(position)), topic.Bytes()...)
+}
+
+// LogsByTopicKey returns the topic index key of a log
+func LogsByTopicKey(position int, topic sdk.Hash, height, index uint64) []byte {
+	return append(LogsByTopicPrefix(position, topic), logPositionKey(height, index)...)
+}
+
+// LogsByHeightKey returns the height index key of a log
+func LogsByHeightKey(height, index uint64) []byte {
+	return append(append([]byte{}, KeyPrefixLogsByHeight...), logPositionKey(height, index)...)
+}
+
+// LogsByHeightPrefix returns a prefix to iterate over the height index entries of a block
+func LogsByHeightPrefix(height uint64) []byte {
+	return append(append([]byte{}, KeyPrefixLogsByHeight...), sdk.Uint64ToBigEndian(height)...)
+}
+
+// BloomKey returns the key of the logs bloom of the block at the given height
+func BloomKey(height uint64) []byte {
+	return append(append([]byte{}, KeyPrefixBloom...), sdk.Uint64ToBigEndian(height)...)
+}
+
+// logPositionKey orders the keys of an index by block height then by log index
+func logPositionKey(height, index uint64) []byte {
+	return append(sdk.Uint64ToBigEndian(height), sdk.Uint64ToBigEndian(index)...)
+}
@@ -0,0 +1,128 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+const (
+	// MaxFilterLogsResults bounds the number of logs returned by a single log filter query
+	MaxFilterLogsResults = 10000
+
+	// MaxFilterLogsBlockRange bounds the number of blocks a single log filter query spans
+	MaxFilterLogsBlockRange = 10000
+)
+
+// LogFilter selects contract logs in the same way as eth_getLogs. A log matches
+// when it was emitted by one of the addresses, and at every topic position by
+// one of the topics of the position, within the block range.
+// An empty address list or topic position matches anything, a zero ToBlock
+// stands for the latest block.
+type LogFilter struct {
+	FromBlock uint64           `json:"from_block" yaml:"from_block"`
+	ToBlock   uint64           `json:"to_block" yaml:"to_block"`
+	Addresses []sdk.AccAddress `json:"addresses" yaml:"addresses"`
+	Topics    [][]sdk.Hash     `json:"topics" yaml:"topics"`
+}
+
+// NewLogFilter returns a LogFilter
+func NewLogFilter(fromBlock, toBlock uint64, addresses []sdk.AccAddress, topics [][]sdk.Hash) LogFilter {
+	return LogFilter{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: addresses,
+		Topics:    topics,
+	}
+}
+
+// Validate checks the block range and the number of topic positions. The span of
+// the block range is only checked when ToBlock is set, the querier setting a zero
+// ToBlock to the latest block.
+func (f LogFilter) Validate() error {
+	if f.ToBlock != 0 && f.FromBlock > f.ToBlock {
+		return fmt.Errorf("from block %d is after to block %d", f.FromBlock, f.ToBlock)
+	}
+
+	if f.ToBlock != 0 && f.ToBlock-f.FromBlock >= MaxFilterLogsBlockRange {
+		return fmt.Errorf("block range %d-%d spans more than %d blocks", f.FromBlock, f.ToBlock, MaxFilterLogsBlockRange)
+	}
+
+	if len(f.Topics) > MaxLogTopics {
+		return fmt.Errorf("too many topic positions: %d, at most %d", len(f.Topics), MaxLogTopics)
+	}
+
+	return nil
+}
+
+// Match returns whether the log satisfies the filter
+func (f LogFilter) Match(log *Log) bool {
+	if log.BlockNumber < f.FromBlock || (f.ToBlock != 0 && log.BlockNumber > f.ToBlock) {
+		return false
+	}
+
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if addr.Equals(log.Address) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+
+	for i, sub := range f.Topics {
+		if len(sub) == 0 {
+			continue
+		}
+
+		found := false
+		for _, topic := range sub {
+			if topic == log.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// indexedTopicPosition returns the first topic position restricted by the filter, or -1
+func (f LogFilter) indexedTopicPosition() int {
+	for i, sub := range f.Topics {
+		if len(sub) > 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (f LogFilter) String() string {
+	topics := make([]string, len(f.Topics))
+	for i, sub := range f.Topics {
+		s := make([]string, len(sub))
+		for j, topic := range sub {
+			s[j] = topic.String()
+		}
+		topics[i] = "[" + strings.Join(s, ",") + "]"
+	}
+
+	return fmt.Sprintf(`Log Filter:
+  From Block: %d
+  To Block:   %d
+  Addresses:  %v
+  Topics:     [%s]`, f.FromBlock, f.ToBlock, f.Addresses, strings.Join(topics, ","))
+}
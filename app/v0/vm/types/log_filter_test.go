@@ -0,0 +1,38 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestLogFilterMatch(t *testing.T) {
+	addr1 := sdk.AccAddress([]byte("addr1_______________"))
+	addr2 := sdk.AccAddress([]byte("addr2_______________"))
+	topicA := sdk.BytesToHash([]byte{0x0a})
+	topicB := sdk.BytesToHash([]byte{0x0b})
+
+	log := &Log{Address: addr1, Topics: []sdk.Hash{topicA, topicB}, BlockNumber: 10}
+
+	require.True(t, NewLogFilter(0, 0, nil, nil).Match(log))
+	require.True(t, NewLogFilter(10, 10, nil, nil).Match(log))
+	require.False(t, NewLogFilter(11, 0, nil, nil).Match(log))
+	require.False(t, NewLogFilter(0, 9, nil, nil).Match(log))
+	require.True(t, NewLogFilter(0, 0, []sdk.AccAddress{addr2, addr1}, nil).Match(log))
+	require.False(t, NewLogFilter(0, 0, []sdk.AccAddress{addr2}, nil).Match(log))
+	require.True(t, NewLogFilter(0, 0, nil, [][]sdk.Hash{{topicA}}).Match(log))
+	require.True(t, NewLogFilter(0, 0, nil, [][]sdk.Hash{nil, {topicA, topicB}}).Match(log))
+	require.False(t, NewLogFilter(0, 0, nil, [][]sdk.Hash{{topicB}}).Match(log))
+	require.False(t, NewLogFilter(0, 0, nil, [][]sdk.Hash{nil, nil, {topicA}}).Match(log))
+}
+
+func TestLogFilterValidate(t *testing.T) {
+	require.NoError(t, NewLogFilter(1, 0, nil, nil).Validate())
+	require.NoError(t, NewLogFilter(1, 1, nil, make([][]sdk.Hash, MaxLogTopics)).Validate())
+	require.Error(t, NewLogFilter(2, 1, nil, nil).Validate())
+	require.Error(t, NewLogFilter(0, 0, nil, make([][]sdk.Hash, MaxLogTopics+1)).Validate())
+	require.NoError(t, NewLogFilter(1, MaxFilterLogsBlockRange, nil, nil).Validate())
+	require.Error(t, NewLogFilter(0, MaxFilterLogsBlockRange, nil, nil).Validate())
+}
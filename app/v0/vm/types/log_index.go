@@ -0,0 +1,182 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	dbm "github.com/tendermint/tm-db"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// TxLogsReader returns the committed logs of a tx, given by its hash
+type TxLogsReader func(txHash []byte) []*Log
+
+// LogIndex indexes the contract logs by address, topic and height, and stores the
+// logs bloom of the blocks, in a database local to the node, out of the consensus
+// state. Each node chooses whether to keep the index and for how many blocks. The
+// values of the indexes are the hashes of the txs emitting the logs, the logs are
+// read from the vm store.
+type LogIndex struct {
+	db        dbm.DB
+	retention uint64
+}
+
+// NewLogIndex returns a LogIndex keeping the logs of the last retention blocks, 0 keeps them all
+func NewLogIndex(db dbm.DB, retention uint64) *LogIndex {
+	return &LogIndex{
+		db:        db,
+		retention: retention,
+	}
+}
+
+// IndexBlock adds the logs of the block at height to the indexes, stores the logs
+// bloom of the block and prunes the blocks falling out of the retention window.
+// Blocks without logs have no stored bloom.
+func (li *LogIndex) IndexBlock(height uint64, logs []*Log, readLogs TxLogsReader) Bloom {
+	batch := li.db.NewBatch()
+	defer batch.Close()
+
+	var bloom Bloom
+	for _, log := range logs {
+		txHash := log.TxHash.Bytes()
+		batch.Set(LogsByAddressKey(log.Address, log.BlockNumber, log.Index), txHash)
+		for i, topic := range log.Topics {
+			batch.Set(LogsByTopicKey(i, topic, log.BlockNumber, log.Index), txHash)
+		}
+		batch.Set(LogsByHeightKey(log.BlockNumber, log.Index), txHash)
+
+		bloom.Or(LogsBloom([]*Log{log}))
+	}
+
+	if !bloom.Empty() {
+		batch.Set(BloomKey(height), bloom.Bytes())
+	}
+
+	if li.retention > 0 && height > li.retention {
+		li.prune(batch, height-li.retention+1, readLogs)
+	}
+
+	batch.Write()
+	return bloom
+}
+
+// prune removes the logs and the blooms of the blocks below the given height from
+// the indexes. The logs themselves stay queryable by tx hash.
+func (li *LogIndex) prune(batch dbm.Batch, height uint64, readLogs TxLogsReader) {
+	type entry struct {
+		key    []byte
+		txHash []byte
+	}
+	var entries []entry
+
+	iter := li.db.Iterator(KeyPrefixLogsByHeight, LogsByHeightKey(height, 0))
+	for ; iter.Valid(); iter.Next() {
+		entries = append(entries, entry{key: iter.Key(), txHash: iter.Value()})
+	}
+	iter.Close()
+
+	txLogs := make(map[string][]*Log)
+	for _, e := range entries {
+		index := binary.BigEndian.Uint64(e.key[len(e.key)-8:])
+
+		txHash := string(e.txHash)
+		if _, ok := txLogs[txHash]; !ok {
+			txLogs[txHash] = readLogs(e.txHash)
+		}
+
+		for _, log := range txLogs[txHash] {
+			if log.Index != index {
+				continue
+			}
+
+			batch.Delete(LogsByAddressKey(log.Address, log.BlockNumber, log.Index))
+			for i, topic := range log.Topics {
+				batch.Delete(LogsByTopicKey(i, topic, log.BlockNumber, log.Index))
+			}
+		}
+
+		batch.Delete(e.key)
+	}
+
+	var bloomKeys [][]byte
+	iter = li.db.Iterator(KeyPrefixBloom, BloomKey(height))
+	for ; iter.Valid(); iter.Next() {
+		bloomKeys = append(bloomKeys, iter.Key())
+	}
+	iter.Close()
+
+	for _, key := range bloomKeys {
+		batch.Delete(key)
+	}
+}
+
+// FilterLogs returns the indexed logs matching the filter, ordered by log index.
+// It walks the address index if the filter names addresses, else the index of the
+// first restricted topic position, else the height index, and fails when more than
+// limit logs match.
+func (li *LogIndex) FilterLogs(filter LogFilter, limit int, readLogs TxLogsReader) ([]*Log, error) {
+	var prefixes [][]byte
+	if len(filter.Addresses) > 0 {
+		for _, addr := range filter.Addresses {
+			prefixes = append(prefixes, LogsByAddressPrefix(addr))
+		}
+	} else if pos := filter.indexedTopicPosition(); pos >= 0 {
+		for _, topic := range filter.Topics[pos] {
+			prefixes = append(prefixes, LogsByTopicPrefix(pos, topic))
+		}
+	} else {
+		prefixes = append(prefixes, KeyPrefixLogsByHeight)
+	}
+
+	txLogs := make(map[string][]*Log)
+	seen := make(map[uint64]struct{})
+	var logs []*Log
+
+	for _, p := range prefixes {
+		start := append(append([]byte{}, p...), sdk.Uint64ToBigEndian(filter.FromBlock)...)
+		end := sdk.PrefixEndBytes(p)
+		if filter.ToBlock != 0 && filter.ToBlock != math.MaxUint64 {
+			end = append(append([]byte{}, p...), sdk.Uint64ToBigEndian(filter.ToBlock+1)...)
+		}
+
+		iter := li.db.Iterator(start, end)
+		for ; iter.Valid(); iter.Next() {
+			key := iter.Key()
+			index := binary.BigEndian.Uint64(key[len(key)-8:])
+			if _, ok := seen[index]; ok {
+				continue
+			}
+			seen[index] = struct{}{}
+
+			txHash := string(iter.Value())
+			if _, ok := txLogs[txHash]; !ok {
+				txLogs[txHash] = readLogs(iter.Value())
+			}
+
+			for _, log := range txLogs[txHash] {
+				if log.Index != index || !filter.Match(log) {
+					continue
+				}
+
+				if len(logs) == limit {
+					iter.Close()
+					return nil, fmt.Errorf("more than %d logs match the filter, narrow the block range or the filter", limit)
+				}
+				logs = append(logs, log)
+			}
+		}
+		iter.Close()
+	}
+
+	sort.Slice(logs, func(i, j int) bool { return logs[i].Index < logs[j].Index })
+	return logs, nil
+}
+
+// GetBlockBloom returns the logs bloom of the block at the given height, empty if
+// the block has no logs
+func (li *LogIndex) GetBlockBloom(height uint64) Bloom {
+	return BytesToBloom(li.db.Get(BloomKey(height)))
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tm-db"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestLogIndex_FilterLogs(t *testing.T) {
+	commitStateDB := buildCommitStateDB()
+	logIndex := NewLogIndex(dbm.NewMemDB(), 0)
+
+	token := sdk.AccAddress([]byte("token_______________"))
+	other := sdk.AccAddress([]byte("other_______________"))
+	transfer := sdk.BytesToHash([]byte("transfer"))
+	approval := sdk.BytesToHash([]byte("approval"))
+	alice := sdk.BytesToHash([]byte("alice"))
+	bob := sdk.BytesToHash([]byte("bob"))
+
+	// one tx per block, emitting a transfer and an approval of the token and a transfer of the other contract
+	for height := uint64(1); height <= 10; height++ {
+		commitStateDB.WithTxHash(sdk.BigToHash(new(big.Int).SetUint64(height)).Bytes())
+		commitStateDB.AddLog(&Log{Address: token, Topics: []sdk.Hash{transfer, alice, bob}, BlockNumber: height})
+		commitStateDB.AddLog(&Log{Address: token, Topics: []sdk.Hash{approval, alice}, BlockNumber: height})
+		commitStateDB.AddLog(&Log{Address: other, Topics: []sdk.Hash{transfer, bob, alice}, BlockNumber: height})
+		commitStateDB.Finalise(true)
+
+		blockLogs := commitStateDB.BlockLogs()
+		require.Len(t, blockLogs, 3)
+		logIndex.IndexBlock(height, blockLogs, commitStateDB.StoredLogs)
+	}
+	require.Empty(t, commitStateDB.BlockLogs())
+
+	filter := func(f LogFilter) []*Log {
+		logs, err := logIndex.FilterLogs(f, MaxFilterLogsResults, commitStateDB.StoredLogs)
+		require.NoError(t, err)
+		return logs
+	}
+
+	require.Len(t, filter(NewLogFilter(0, 0, nil, nil)), 30)
+	require.Len(t, filter(NewLogFilter(3, 4, nil, nil)), 6)
+	require.Len(t, filter(NewLogFilter(0, 0, []sdk.AccAddress{token}, nil)), 20)
+	require.Len(t, filter(NewLogFilter(0, 0, []sdk.AccAddress{token, other}, nil)), 30)
+	require.Len(t, filter(NewLogFilter(0, 0, nil, [][]sdk.Hash{{transfer}})), 20)
+	require.Len(t, filter(NewLogFilter(0, 0, nil, [][]sdk.Hash{{transfer, approval}})), 30)
+
+	logs := filter(NewLogFilter(5, 6, []sdk.AccAddress{token}, [][]sdk.Hash{{transfer}, nil, {bob}}))
+	require.Len(t, logs, 2)
+	for i, log := range logs {
+		require.Equal(t, uint64(5+i), log.BlockNumber)
+		require.Equal(t, token, log.Address)
+		require.Equal(t, transfer, log.Topics[0])
+	}
+
+	logs = filter(NewLogFilter(0, 0, nil, [][]sdk.Hash{nil, {bob}}))
+	require.Len(t, logs, 10)
+	for i := 1; i < len(logs); i++ {
+		require.True(t, logs[i-1].Index < logs[i].Index)
+	}
+
+	_, err := logIndex.FilterLogs(NewLogFilter(0, 0, nil, nil), 29, commitStateDB.StoredLogs)
+	require.Error(t, err)
+
+	bloom := logIndex.GetBlockBloom(3)
+	require.True(t, bloom.Test(token.Bytes()))
+	require.True(t, bloom.Test(approval.Bytes()))
+	require.False(t, bloom.Test([]byte("absent")))
+	require.True(t, logIndex.IndexBlock(11, nil, commitStateDB.StoredLogs).Empty())
+	require.True(t, logIndex.GetBlockBloom(11).Empty())
+
+	// the committed logs are exported and imported with the vm state, ordered by height for indexing
+	importStateDB := buildCommitStateDB()
+	importStateDB.ImportState(commitStateDB.ExportState())
+	allLogs := importStateDB.AllStoredLogs()
+	require.Len(t, allLogs, 30)
+	for i := 1; i < len(allLogs); i++ {
+		require.True(t, allLogs[i-1].BlockNumber <= allLogs[i].BlockNumber)
+	}
+}
+
+func TestLogIndex_Retention(t *testing.T) {
+	commitStateDB := buildCommitStateDB()
+	logIndex := NewLogIndex(dbm.NewMemDB(), 3)
+
+	token := sdk.AccAddress([]byte("token_______________"))
+	transfer := sdk.BytesToHash([]byte("transfer"))
+	alice := sdk.BytesToHash([]byte("alice"))
+
+	for height := uint64(1); height <= 10; height++ {
+		commitStateDB.WithTxHash(sdk.BigToHash(new(big.Int).SetUint64(height)).Bytes())
+		commitStateDB.AddLog(&Log{Address: token, Topics: []sdk.Hash{transfer, alice}, BlockNumber: height})
+		commitStateDB.Finalise(true)
+		logIndex.IndexBlock(height, commitStateDB.BlockLogs(), commitStateDB.StoredLogs)
+	}
+
+	// the blocks 8 to 10 stay in the indexes, the pruned logs stay queryable by tx hash
+	for _, f := range []LogFilter{
+		NewLogFilter(0, 0, nil, nil),
+		NewLogFilter(0, 0, []sdk.AccAddress{token}, nil),
+		NewLogFilter(0, 0, nil, [][]sdk.Hash{nil, {alice}}),
+	} {
+		logs, err := logIndex.FilterLogs(f, MaxFilterLogsResults, commitStateDB.StoredLogs)
+		require.NoError(t, err)
+		require.Len(t, logs, 3)
+		require.Equal(t, uint64(8), logs[0].BlockNumber)
+	}
+	require.Len(t, commitStateDB.GetLogs(sdk.BigToHash(big.NewInt(1))), 1)
+
+	// the blooms of the pruned blocks are removed with their logs
+	for height := uint64(1); height <= 7; height++ {
+		require.True(t, logIndex.GetBlockBloom(height).Empty())
+	}
+	for height := uint64(8); height <= 10; height++ {
+		require.True(t, logIndex.GetBlockBloom(height).Test(token))
+	}
+}
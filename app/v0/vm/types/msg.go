@@ -20,6 +20,10 @@ type MsgContract struct {
 	To      sdk.AccAddress `json:"to" yaml:"to"`
 	Payload hexutil.Bytes  `json:"payload" yaml:"payload"`
 	Amount  sdk.Coin       `json:"amount" yaml:"amount"`
+
+	// AccessList is optional, the accounts and storage slots it declares are warm
+	// from the start of the execution, it requires the berlin fork
+	AccessList AccessList `json:"access_list,omitempty" yaml:"access_list,omitempty"`
 }
 
 func (msg MsgContract) Route() string {
@@ -46,6 +50,9 @@ func (msg MsgContract) ValidateBasic() error {
 	if len(msg.Payload) == 0 {
 		return ErrNoPayload
 	}
+	if err := msg.AccessList.Validate(); err != nil {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
 
 	return nil
 }
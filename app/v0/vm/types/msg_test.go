@@ -1,7 +1,9 @@
 package types
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -112,3 +114,24 @@ func TestMsgContractRoute(t *testing.T) {
 	require.Equal(t, msg.Type(), TypeMsgContractCall)
 
 }
+
+func TestMsgContractAccessList(t *testing.T) {
+	addr1 := sdk.AccAddress([]byte("from"))
+	addr2 := sdk.AccAddress([]byte("to"))
+	msg := NewMsgContract(addr1, addr2, []byte("payload"), sdk.NewInt64Coin(sdk.NativeTokenName, 123))
+
+	msg.AccessList = AccessList{{Address: addr2, StorageKeys: []sdk.Hash{sdk.BigToHash(big.NewInt(1))}}}
+	require.NoError(t, msg.ValidateBasic())
+	require.Equal(t, 1, msg.AccessList.StorageKeys())
+
+	expected := `{"type":"nch/MsgContract","value":{"access_list":[{"address":"nch1w3hsls558e","storage_keys":["0000000000000000000000000000000000000000000000000000000000000001"]}],"amount":{"amount":"123","denom":"pnch"},"from":"nch1veex7mg3k0xqr","payload":"7061796c6f6164","to":"nch1w3hsls558e"}}`
+	require.Equal(t, expected, string(msg.GetSignBytes()))
+
+	var decoded AccessList
+	list := AccessList{{Address: sdk.AccAddress(bytes.Repeat([]byte{1}, sdk.AddrLen)), StorageKeys: []sdk.Hash{sdk.BigToHash(big.NewInt(1))}}}
+	require.NoError(t, ModuleCdc.UnmarshalJSON(ModuleCdc.MustMarshalJSON(list), &decoded))
+	require.Equal(t, list, decoded)
+
+	msg.AccessList = append(msg.AccessList, AccessTuple{})
+	require.Error(t, msg.ValidateBasic())
+}
@@ -13,6 +13,15 @@ const (
 
 	defaultContractCreationGas = 53000
 	defaultGasPerByte          = 200
+
+	defaultRefundQuotient = 2
+	defaultEVMChainID     = 0
+)
+
+// Permissions to deploy contracts
+const (
+	DeployPermissionOpen              = "open"               // any account can deploy contracts
+	DeployPermissionGuardianWhitelist = "guardian_whitelist" // only the deployers of the guardian module can deploy contracts
 )
 
 // nolint
@@ -21,6 +30,10 @@ var (
 	KeyMaxCallCreateDepth          = []byte("MaxCallCreateDepth")
 	KeyVMOpGasParams               = []byte("VMOpGasParams")
 	KeyVMContractCreationGasParams = []byte("VMContractCreationGasParams")
+	KeyRefundQuotient              = []byte("RefundQuotient")
+	KeyEVMChainID                  = []byte("EVMChainID")
+	KeyDeployPermission            = []byte("DeployPermission")
+	KeyEVMForks                    = []byte("EVMForks")
 
 	DefaultVMOpGasParams = [256]uint64{
 		0, 3, 5, 3, 5, 5, 5, 5, 8, 8, 0, 5, 0, 0, 0, 0, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 0, 0, //0-31
@@ -34,6 +47,8 @@ var (
 	}
 
 	vmContractCreationGasParams = VMContractCreationGasParams{Gas: defaultContractCreationGas, GasPerByte: defaultGasPerByte}
+
+	defaultEVMForks = EVMForks{{Name: ForkIstanbul, Height: 0}}
 )
 
 // VMContractCreationGasParams contract creation gas params
@@ -47,17 +62,25 @@ type Params struct {
 	MaxCallCreateDepth          uint64                      `json:"max_call_create_depth" yaml:"max_call_create_depth"`
 	VMOpGasParams               [256]uint64                 `json:"vm_op_gas_params" yaml:"vm_op_gas_params"`
 	VMContractCreationGasParams VMContractCreationGasParams `json:"vm_contract_creation_gas_params" yaml:"vm_contract_creation_gas_params"`
+	RefundQuotient              uint64                      `json:"refund_quotient" yaml:"refund_quotient"`     // the SSTORE and SELFDESTRUCT gas refund is capped at gas used / refund quotient, 0 disables the refund
+	EVMChainID                  uint64                      `json:"evm_chain_id" yaml:"evm_chain_id"`           // chain id returned by the CHAINID opcode, used by contracts in EIP-712 domains and for replay protection
+	DeployPermission            string                      `json:"deploy_permission" yaml:"deploy_permission"` // accounts allowed to deploy contracts, open or guardian_whitelist
+	EVMForks                    EVMForks                    `json:"evm_forks" yaml:"evm_forks"`                 // heights the EVM forks are activated at by the upgrade module
 }
 
 var _ params.ParamSet = (*Params)(nil)
 
 // NewParams return Params
-func NewParams(maxCodeSize, callCreateDepth uint64, vmOpGasParams [256]uint64, vmContractCreationGasParams VMContractCreationGasParams) Params {
+func NewParams(maxCodeSize, callCreateDepth uint64, vmOpGasParams [256]uint64, vmContractCreationGasParams VMContractCreationGasParams, refundQuotient, evmChainID uint64, deployPermission string, evmForks EVMForks) Params {
 	return Params{
 		MaxCodeSize:                 maxCodeSize,
 		MaxCallCreateDepth:          callCreateDepth,
 		VMOpGasParams:               vmOpGasParams,
 		VMContractCreationGasParams: vmContractCreationGasParams,
+		RefundQuotient:              refundQuotient,
+		EVMChainID:                  evmChainID,
+		DeployPermission:            deployPermission,
+		EVMForks:                    evmForks,
 	}
 }
 
@@ -67,6 +90,10 @@ func (p *Params) ParamSetPairs() params.ParamSetPairs {
 		params.NewParamSetPair(KeyMaxCallCreateDepth, &p.MaxCallCreateDepth, validateMaxCallCreateDepth),
 		params.NewParamSetPair(KeyVMOpGasParams, &p.VMOpGasParams, validateVMOpGasParams),
 		params.NewParamSetPair(KeyVMContractCreationGasParams, &p.VMContractCreationGasParams, validateVMCommonGasParams),
+		params.NewParamSetPair(KeyRefundQuotient, &p.RefundQuotient, validateRefundQuotient),
+		params.NewParamSetPair(KeyEVMChainID, &p.EVMChainID, validateEVMChainID),
+		params.NewParamSetPair(KeyDeployPermission, &p.DeployPermission, validateDeployPermission),
+		params.NewParamSetPair(KeyEVMForks, &p.EVMForks, validateEVMForks),
 	}
 }
 
@@ -76,6 +103,10 @@ func DefaultParams() Params {
 		defaultCallCreateDepth,
 		DefaultVMOpGasParams,
 		vmContractCreationGasParams,
+		defaultRefundQuotient,
+		defaultEVMChainID,
+		DeployPermissionOpen,
+		defaultEVMForks,
 	)
 }
 
@@ -106,6 +137,38 @@ func validateMaxCallCreateDepth(i interface{}) error {
 	return nil
 }
 
+func validateRefundQuotient(i interface{}) error {
+	_, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("RefundQuotient'type must be uint64: %T", i)
+	}
+
+	return nil
+}
+
+func validateEVMChainID(i interface{}) error {
+	_, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("EVMChainID'type must be uint64: %T", i)
+	}
+
+	return nil
+}
+
+func validateDeployPermission(i interface{}) error {
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("DeployPermission'type must be string: %T", i)
+	}
+
+	// genesis files written before the param was introduced leave it empty, it is open
+	if v != "" && v != DeployPermissionOpen && v != DeployPermissionGuardianWhitelist {
+		return fmt.Errorf("invalid deploy permission: %s", v)
+	}
+
+	return nil
+}
+
 func validateVMOpGasParams(i interface{}) error {
 	return nil
 }
@@ -3,7 +3,10 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/netcloth/netcloth-chain/codec"
+	"github.com/netcloth/netcloth-chain/hexutil"
 	sdk "github.com/netcloth/netcloth-chain/types"
 )
 
@@ -13,8 +16,14 @@ const (
 	QueryCode       = "code"
 	QueryStorage    = "storage"
 	QueryTxLogs     = "logs"
+	QueryFilterLogs = "filter_logs"
+	QueryBloom      = "bloom"
 	EstimateGas     = "estimate_gas"
 	QueryCall       = "call"
+	QueryTrace      = "trace"
+	QueryTraceCall  = "trace_call"
+
+	QueryCreateAccessList = "create_access_list"
 )
 
 // QueryLogsResult - for query logs
@@ -26,6 +35,16 @@ func (q QueryLogsResult) String() string {
 	return fmt.Sprintf("%+v", q.Logs)
 }
 
+// QueryBloomResult - for query the logs bloom of a block
+type QueryBloomResult struct {
+	Height uint64 `json:"height"`
+	Bloom  Bloom  `json:"bloom"`
+}
+
+func (q QueryBloomResult) String() string {
+	return fmt.Sprintf("Height = %d\nBloom = %s", q.Height, q.Bloom)
+}
+
 // QueryStorageResult - for query storage
 type QueryStorageResult struct {
 	Value sdk.Hash `json:"value"`
@@ -35,16 +54,37 @@ func (q QueryStorageResult) String() string {
 	return q.Value.String()
 }
 
-// SimulationResult - for Gas Estimate
+// SimulationResult - for Gas Estimate, Err is set when the execution failed
 type SimulationResult struct {
 	Gas uint64
 	Res string
+	Err *ExecutionError `json:"Err,omitempty"`
 }
 
 func (r SimulationResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("Gas = %d\nRes = %s\nErr = %s", r.Gas, r.Res, r.Err)
+	}
 	return fmt.Sprintf("Gas = %d\nRes = %s", r.Gas, r.Res)
 }
 
+// AccessListResult - for the create_access_list query, the access list of a simulated
+// contract call or creation and the gas it uses with the list, Err is set when the
+// execution failed
+type AccessListResult struct {
+	AccessList AccessList      `json:"access_list"`
+	Gas        uint64          `json:"gas"`
+	Err        *ExecutionError `json:"error,omitempty"`
+}
+
+func (r AccessListResult) String() string {
+	j, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("Gas = %d\nAccessList = %v", r.Gas, r.AccessList)
+	}
+	return string(j)
+}
+
 // VMQueryResult
 type VMQueryResult struct {
 	Gas    uint64
@@ -64,3 +104,127 @@ type QueryStateParams struct {
 	ShowCode     bool `json:"show_code" yaml:"show_code"`
 	ContractOnly bool `json:"contract_only" yaml:"contract_only"`
 }
+
+// names of the tracers a trace query can use
+const (
+	StructLoggerTracer = "structLogger"
+	CallTracer         = "callTracer"
+)
+
+// TraceConfig - tracer used to trace a tx, the struct logger by default, and the switches of the struct logger
+type TraceConfig struct {
+	Tracer         string `json:"tracer" yaml:"tracer"`
+	DisableMemory  bool   `json:"disable_memory" yaml:"disable_memory"`
+	DisableStack   bool   `json:"disable_stack" yaml:"disable_stack"`
+	DisableStorage bool   `json:"disable_storage" yaml:"disable_storage"`
+	Limit          int    `json:"limit" yaml:"limit"`
+}
+
+// Validate checks the tracer name
+func (c TraceConfig) Validate() error {
+	switch c.Tracer {
+	case "", StructLoggerTracer, CallTracer:
+		return nil
+	default:
+		return fmt.Errorf("unknown tracer: %s", c.Tracer)
+	}
+}
+
+// QueryTraceParams - for trace a committed tx, queried against the state of the parent block
+type QueryTraceParams struct {
+	Height          int64       `json:"height"`
+	Time            time.Time   `json:"time"`
+	ProposerAddress []byte      `json:"proposer_address"`
+	LastBlockHash   []byte      `json:"last_block_hash"`
+	PrecedingTxs    [][]byte    `json:"preceding_txs"`
+	Tx              []byte      `json:"tx"`
+	Config          TraceConfig `json:"config"`
+}
+
+// QuerySimulateParams - for the call and estimate_gas queries, simulating a contract call
+// or creation with optional state overrides
+type QuerySimulateParams struct {
+	Msg       MsgContractQuery `json:"msg"`
+	Overrides StateOverrides   `json:"overrides,omitempty"`
+	// estimate_gas estimates the gas of msg sent by an Ethereum tx rather than a StdTx
+	EthereumTx bool `json:"ethereum_tx,omitempty"`
+}
+
+// UnmarshalSimulateParams decodes the params of the call and estimate_gas queries.
+// A bare MsgContractQuery, as sent by the clients which don't override the state,
+// is accepted as well.
+func UnmarshalSimulateParams(cdc *codec.Codec, bz []byte) (params QuerySimulateParams, err error) {
+	if err = cdc.UnmarshalJSON(bz, &params); err == nil && !params.Msg.From.Empty() {
+		return params, params.Overrides.Validate()
+	}
+
+	var msg MsgContractQuery
+	if err = cdc.UnmarshalJSON(bz, &msg); err != nil {
+		return params, err
+	}
+
+	return QuerySimulateParams{Msg: msg}, nil
+}
+
+// QueryTraceCallParams - for trace a simulated contract call or creation
+type QueryTraceCallParams struct {
+	Msg       MsgContractQuery `json:"msg"`
+	Config    TraceConfig      `json:"config"`
+	Overrides StateOverrides   `json:"overrides,omitempty"`
+}
+
+// TraceResult - trace of a tx, opcode level logs of the struct logger or the call tree of the call tracer
+type TraceResult struct {
+	Gas         uint64         `json:"gas"`
+	Failed      bool           `json:"failed"`
+	Error       string         `json:"error,omitempty"`
+	ReturnValue string         `json:"return_value"`
+	StructLogs  []StructLogRes `json:"struct_logs,omitempty"`
+	CallTrace   *CallFrame     `json:"call_trace,omitempty"`
+}
+
+func (r TraceResult) String() string {
+	j, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("Gas = %d\nFailed = %v\nReturnValue = %s", r.Gas, r.Failed, r.ReturnValue)
+	}
+	return string(j)
+}
+
+// TraceResults - traces of the MsgContracts of a tx, in order
+type TraceResults []TraceResult
+
+func (r TraceResults) String() string {
+	j, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%d traces", len(r))
+	}
+	return string(j)
+}
+
+// StructLogRes - state of the vm before the execution of an opcode
+type StructLogRes struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gas_cost"`
+	Depth   uint64            `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
+
+// CallFrame - a call or creation made during the execution of a tx, with the internal calls it made
+type CallFrame struct {
+	Type    string         `json:"type"`
+	From    sdk.AccAddress `json:"from"`
+	To      sdk.AccAddress `json:"to,omitempty"`
+	Value   sdk.Int        `json:"value"`
+	Gas     uint64         `json:"gas"`
+	GasUsed uint64         `json:"gas_used"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Calls   []CallFrame    `json:"calls,omitempty"`
+}
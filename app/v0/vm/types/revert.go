@@ -0,0 +1,81 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// selector of Error(string), raised by revert("reason") and require(cond, "reason")
+	revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// selector of Panic(uint256), raised by failed asserts and checked arithmetic since solidity 0.8
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons describes the codes of Panic(uint256)
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert(false)",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "out-of-bounds array access; popping on an empty array",
+	0x32: "out-of-bounds access of an array or bytesN",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// UnpackRevertReason decodes the data returned by a reverted execution: the message
+// of Error(string), the description of Panic(uint256), or the selector of a custom
+// error, which can be decoded from the raw data with the ABI of the contract. Empty
+// data, or data not starting with a selector, gives an empty reason.
+func UnpackRevertReason(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+
+	selector, args := data[:4], data[4:]
+	switch {
+	case bytes.Equal(selector, revertSelector):
+		reason, err := unpackString(args)
+		if err != nil {
+			return fmt.Sprintf("invalid Error(string) data: %s", err)
+		}
+		return reason
+
+	case bytes.Equal(selector, panicSelector) && len(args) == 32:
+		code := new(big.Int).SetBytes(args)
+		if code.IsUint64() {
+			if reason, ok := panicReasons[code.Uint64()]; ok {
+				return fmt.Sprintf("panic: %s (0x%x)", reason, code)
+			}
+		}
+		return fmt.Sprintf("panic: unknown code 0x%x", code)
+
+	default:
+		return fmt.Sprintf("custom error 0x%x", selector)
+	}
+}
+
+// unpackString decodes an ABI encoded string, its offset followed by its length and
+// its padded content
+func unpackString(data []byte) (string, error) {
+	if len(data) < 64 {
+		return "", fmt.Errorf("data too short: %d bytes", len(data))
+	}
+
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(data)-32) {
+		return "", fmt.Errorf("invalid offset %s", offset)
+	}
+	start := offset.Uint64() + 32
+
+	length := new(big.Int).SetBytes(data[start-32 : start])
+	if !length.IsUint64() || length.Uint64() > uint64(len(data))-start {
+		return "", fmt.Errorf("invalid length %s", length)
+	}
+
+	return string(data[start : start+length.Uint64()]), nil
+}
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestUnpackRevertReason(t *testing.T) {
+	cases := []struct {
+		data   string
+		reason string
+	}{
+		{"", ""},
+		{"08c379a0", "invalid Error(string) data: data too short: 0 bytes"},
+		{"08c379a000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000016496e73756666696369656e7420616c6c6f77616e636500000000000000000000", "Insufficient allowance"},
+		{"08c379a000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000080496e73756666696369656e7420616c6c6f77616e636500000000000000000000", "invalid Error(string) data: invalid length 128"},
+		{"4e487b710000000000000000000000000000000000000000000000000000000000000011", "panic: arithmetic underflow or overflow (0x11)"},
+		{"4e487b7100000000000000000000000000000000000000000000000000000000000000ff", "panic: unknown code 0xff"},
+		{"fb8f41b2000000000000000000000000000000000000000000000000000000000000002a", "custom error 0xfb8f41b2"},
+	}
+
+	for i, tc := range cases {
+		require.Equal(t, tc.reason, UnpackRevertReason(sdk.FromHex(tc.data)), "case %d", i)
+	}
+}
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/netcloth/netcloth-chain/hexutil"
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+// StorageOverride sets the value of a contract storage slot
+type StorageOverride struct {
+	Key   sdk.Hash `json:"key" yaml:"key"`
+	Value sdk.Hash `json:"value" yaml:"value"`
+}
+
+// AccountOverride replaces parts of the state of an account during a simulation,
+// the fields left empty keep their current value. Only the listed storage slots
+// are replaced, the other slots of the contract keep their value.
+type AccountOverride struct {
+	Address sdk.AccAddress    `json:"address" yaml:"address"`
+	Balance *sdk.Int          `json:"balance,omitempty" yaml:"balance,omitempty"`
+	Nonce   *uint64           `json:"nonce,omitempty" yaml:"nonce,omitempty"`
+	Code    *hexutil.Bytes    `json:"code,omitempty" yaml:"code,omitempty"`
+	Storage []StorageOverride `json:"storage,omitempty" yaml:"storage,omitempty"`
+}
+
+// StateOverrides are the temporary changes to the state a call or estimate_gas
+// query is simulated with, nothing is written to the chain state
+type StateOverrides []AccountOverride
+
+// Validate checks that every account is overridden once and that balances aren't negative
+func (o StateOverrides) Validate() error {
+	seen := make(map[string]bool, len(o))
+	for _, account := range o {
+		if account.Address.Empty() {
+			return fmt.Errorf("state override without address")
+		}
+
+		addr := account.Address.String()
+		if seen[addr] {
+			return fmt.Errorf("duplicate state override of %s", addr)
+		}
+		seen[addr] = true
+
+		if account.Balance != nil && account.Balance.IsNegative() {
+			return fmt.Errorf("negative balance override of %s: %s", addr, account.Balance)
+		}
+	}
+
+	return nil
+}
+
+// Apply applies the overrides to the state objects of the state db. The overridden
+// storage slots are set as committed state, so the gas of an SSTORE to such a
+// slot is computed as if the value was stored on chain.
+func (o StateOverrides) Apply(csdb *CommitStateDB) {
+	for _, account := range o {
+		if account.Balance != nil {
+			csdb.SetBalance(account.Address, account.Balance.BigInt())
+		}
+
+		if account.Nonce != nil {
+			csdb.SetNonce(account.Address, *account.Nonce)
+		}
+
+		if account.Code != nil {
+			csdb.SetCode(account.Address, *account.Code)
+		}
+
+		if len(account.Storage) > 0 {
+			so := csdb.GetOrNewStateObject(account.Address).(*stateObject)
+			for _, slot := range account.Storage {
+				so.originStorage[so.GetStorageByAddressKey(slot.Key.Bytes())] = slot.Value
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -26,6 +27,12 @@ type revision struct {
 	journalIndex int
 }
 
+type nativeCache struct {
+	prev   sdk.Context // context the cache wraps
+	write  func()
+	events sdk.Events
+}
+
 type CommitStateDB struct {
 	// TODO: We need to store the context as part of the structure itself opposed
 	// to being passed as a parameter (as it should be) in order to implement the
@@ -36,6 +43,9 @@ type CommitStateDB struct {
 
 	storageKey sdk.StoreKey
 
+	// transient store recording the txs of the block which emit logs
+	transientKey sdk.StoreKey
+
 	// maps that hold 'live' objects, which will get modified while processing a
 	// state transition
 	stateObjects      map[string]*stateObject
@@ -65,6 +75,13 @@ type CommitStateDB struct {
 	validRevisions []revision
 	nextRevisionID int
 
+	// Caches of the context holding the changes made to the NetCloth modules by the
+	// native contracts, written down once the state is finalised.
+	nativeCaches []nativeCache
+
+	// EIP-2929 addresses and storage slots accessed by the transaction
+	accessList *accessList
+
 	// mutex for state deep copying
 	lock sync.Mutex
 }
@@ -75,15 +92,17 @@ type CommitStateDB struct {
 // CONTRACT: Stores used for state must be cache-wrapped as the ordering of the
 // key/value space matters in determining the merkle root.
 //func NewCommitStateDB(ctx sdk.Context, ak auth.AccountKeeper, storageKey, codeKey sdk.StoreKey) *CommitStateDB {
-func NewCommitStateDB(ak auth.AccountKeeper, storageKey sdk.StoreKey) *CommitStateDB {
+func NewCommitStateDB(ak auth.AccountKeeper, storageKey, transientKey sdk.StoreKey) *CommitStateDB {
 	return &CommitStateDB{
 		ak:                ak,
 		storageKey:        storageKey,
+		transientKey:      transientKey,
 		stateObjects:      make(map[string]*stateObject),
 		stateObjectsDirty: make(map[string]struct{}),
 		logs:              make(map[sdk.Hash][]*Log),
 		preimages:         make(map[sdk.Hash][]byte),
 		journal:           newJournal(),
+		accessList:        newAccessList(),
 	}
 }
 
@@ -91,11 +110,13 @@ func NewStateDB(db *CommitStateDB) *CommitStateDB {
 	return &CommitStateDB{
 		ak:                db.ak,
 		storageKey:        db.storageKey,
+		transientKey:      db.transientKey,
 		stateObjects:      make(map[string]*stateObject),
 		stateObjectsDirty: make(map[string]struct{}),
 		logs:              make(map[sdk.Hash][]*Log),
 		preimages:         make(map[sdk.Hash][]byte),
 		journal:           newJournal(),
+		accessList:        newAccessList(),
 	}
 }
 
@@ -105,6 +126,64 @@ func (csdb *CommitStateDB) WithContext(ctx sdk.Context) *CommitStateDB {
 	return csdb
 }
 
+// Context returns the sdk context of the state db
+func (csdb *CommitStateDB) Context() sdk.Context {
+	return csdb.ctx
+}
+
+// RunNative runs fn, a change to the state of the NetCloth modules, against a cache of
+// the context. The cached accounts are written to the cache before fn runs and their
+// balances are read back after it, the change is journaled so that it is discarded
+// when the state is reverted.
+func (csdb *CommitStateDB) RunNative(fn func(ctx sdk.Context) error) error {
+	cacheCtx, write := csdb.ctx.CacheContext()
+	for _, so := range csdb.stateObjects {
+		if !so.deleted && !so.suicided {
+			csdb.ak.SetAccount(cacheCtx, so.account)
+		}
+	}
+
+	if err := fn(cacheCtx); err != nil {
+		return err
+	}
+
+	csdb.journal.append(nativeChange{})
+	csdb.nativeCaches = append(csdb.nativeCaches, nativeCache{prev: csdb.ctx, write: write, events: cacheCtx.EventManager().Events()})
+	csdb.ctx = cacheCtx
+
+	for _, so := range csdb.stateObjects {
+		if so.deleted || so.suicided {
+			continue
+		}
+
+		acc, ok := csdb.ak.GetAccount(cacheCtx, so.address).(*types.BaseAccount)
+		if ok && acc.Balance().BigInt().Cmp(so.Balance()) != 0 {
+			so.SetBalance(acc.Balance().BigInt())
+		}
+	}
+
+	return nil
+}
+
+// commitNative writes the changes made by the native contracts down to the context
+// the state db started with
+func (csdb *CommitStateDB) commitNative() {
+	if len(csdb.nativeCaches) == 0 {
+		return
+	}
+
+	// each cache wraps the context of the previous one
+	for i := len(csdb.nativeCaches) - 1; i >= 0; i-- {
+		csdb.nativeCaches[i].write()
+	}
+
+	csdb.ctx = csdb.nativeCaches[0].prev
+	for _, cache := range csdb.nativeCaches {
+		csdb.ctx.EventManager().EmitEvents(cache.events)
+	}
+	csdb.nativeCaches = nil
+}
+
 // ContractCreatedEvent emit event of contract created
 // nolint
 func (csdb *CommitStateDB) ContractCreatedEvent(addr sdk.AccAddress) {
@@ -318,6 +397,10 @@ func (csdb *CommitStateDB) GetLogs(hash sdk.Hash) (logs []*Log) {
 	ctx := csdb.ctx
 	store := prefix.NewStore(ctx.KVStore(csdb.storageKey), KeyPrefixLogs)
 	d := store.Get(hash.Bytes())
+	if d == nil {
+		return
+	}
+
 	err := json.Unmarshal(d, &logs)
 	if err != nil {
 		ctx.Logger().Error(err.Error())
@@ -399,6 +482,7 @@ func (csdb *CommitStateDB) Commit(deleteEmptyObjects bool) (root sdk.Hash, err e
 
 		delete(csdb.stateObjectsDirty, addr)
 	}
+	csdb.commitNative()
 
 	// NOTE: Ethereum returns the trie merkle root here, but as commitment
 	// actually happens in the BaseApp at EndBlocker, we do not know the root at
@@ -426,7 +510,76 @@ func (csdb *CommitStateDB) commitLogs() {
 
 		ctx.Logger().Debug(fmt.Sprintf("set logs, txHash: %s, logs: %s", hash.String(), string(d)))
 		store.Set(hash.Bytes(), d)
+		csdb.blockTxsStore().Set(hash.Bytes(), []byte{})
+	}
+}
+
+// blockTxsStore returns the transient store of the hashes of the txs of the block
+// which emit logs. Indexing is a service of the node rather than an execution cost,
+// so recording the txs doesn't consume the gas of the tx.
+func (csdb *CommitStateDB) blockTxsStore() sdk.KVStore {
+	return csdb.ctx.WithGasMeter(sdk.NewInfiniteGasMeter()).TransientStore(csdb.transientKey)
+}
+
+// StoredLogs returns the committed logs of a tx
+func (csdb *CommitStateDB) StoredLogs(txHash []byte) (logs []*Log) {
+	store := prefix.NewStore(csdb.ctx.KVStore(csdb.storageKey), KeyPrefixLogs)
+	bz := store.Get(txHash)
+	if bz == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(bz, &logs); err != nil {
+		csdb.ctx.Logger().Error(err.Error())
+		return nil
+	}
+
+	return
+}
+
+// BlockLogs returns the logs committed during the current block, ordered by log index,
+// and clears the record of the txs emitting them
+func (csdb *CommitStateDB) BlockLogs() (logs []*Log) {
+	store := csdb.blockTxsStore()
+
+	var txHashes [][]byte
+	iter := sdk.KVStorePrefixIterator(store, nil)
+	for ; iter.Valid(); iter.Next() {
+		txHashes = append(txHashes, iter.Key())
+	}
+	iter.Close()
+
+	for _, txHash := range txHashes {
+		logs = append(logs, csdb.StoredLogs(txHash)...)
+		store.Delete(txHash)
+	}
+
+	sort.Slice(logs, func(i, j int) bool { return logs[i].Index < logs[j].Index })
+	return logs
+}
+
+// AllStoredLogs returns all the committed logs, ordered by block number and log index
+func (csdb *CommitStateDB) AllStoredLogs() (logs []*Log) {
+	store := prefix.NewStore(csdb.ctx.KVStore(csdb.storageKey), KeyPrefixLogs)
+	iter := store.Iterator(nil, nil)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		var txLogs []*Log
+		if err := json.Unmarshal(iter.Value(), &txLogs); err != nil {
+			csdb.ctx.Logger().Error(err.Error())
+			continue
+		}
+		logs = append(logs, txLogs...)
 	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+	return logs
 }
 
 func (csdb *CommitStateDB) updateLogIndexByOne(isSubtract bool) uint64 {
@@ -491,6 +644,7 @@ func (csdb *CommitStateDB) Finalise(deleteEmptyObjects bool) {
 
 	csdb.commitLogs()
 	csdb.ClearLogs()
+	csdb.commitNative()
 
 	// invalidate journal because reverting across transactions is not allowed
 	csdb.clearJournalAndRefund()
@@ -663,6 +817,61 @@ func (csdb *CommitStateDB) Prepare(thash, bhash sdk.Hash, txi int) {
 	csdb.txIndex = txi
 }
 
+// PrepareAccessList resets the access list at the start of a transaction executed
+// with the EIP-2929 rules, the sender, the destination, the precompiled contracts
+// and the entries of the EIP-2930 access list of the transaction are warm from the start
+func (csdb *CommitStateDB) PrepareAccessList(sender sdk.AccAddress, dst sdk.AccAddress, precompiles []sdk.AccAddress, list AccessList) {
+	csdb.accessList = newAccessList()
+	csdb.accessList.AddAddress(sender)
+	if !dst.Empty() {
+		csdb.accessList.AddAddress(dst)
+	}
+	for _, addr := range precompiles {
+		csdb.accessList.AddAddress(addr)
+	}
+	for _, tuple := range list {
+		csdb.accessList.AddAddress(tuple.Address)
+		for _, key := range tuple.StorageKeys {
+			csdb.accessList.AddSlot(tuple.Address, key)
+		}
+	}
+}
+
+// AddAddressToAccessList adds the given address to the access list
+func (csdb *CommitStateDB) AddAddressToAccessList(addr sdk.AccAddress) {
+	if csdb.accessList.AddAddress(addr) {
+		csdb.journal.append(accessListAddAccountChange{&addr})
+	}
+}
+
+// AddSlotToAccessList adds the given (address, slot) to the access list
+func (csdb *CommitStateDB) AddSlotToAccessList(addr sdk.AccAddress, slot sdk.Hash) {
+	addrMod, slotMod := csdb.accessList.AddSlot(addr, slot)
+	if addrMod {
+		// In practice, this should not happen, since there is no way to enter the
+		// scope of 'address' without having the 'address' become already added
+		// to the access list (via call-variant, create, etc).
+		// Better safe than sorry, though
+		csdb.journal.append(accessListAddAccountChange{&addr})
+	}
+	if slotMod {
+		csdb.journal.append(accessListAddSlotChange{
+			address: &addr,
+			slot:    &slot,
+		})
+	}
+}
+
+// AddressInAccessList returns true if the given address is in the access list
+func (csdb *CommitStateDB) AddressInAccessList(addr sdk.AccAddress) bool {
+	return csdb.accessList.ContainsAddress(addr)
+}
+
+// SlotInAccessList returns true if the given (address, slot) is in the access list
+func (csdb *CommitStateDB) SlotInAccessList(addr sdk.AccAddress, slot sdk.Hash) (addressPresent bool, slotPresent bool) {
+	return csdb.accessList.Contains(addr, slot)
+}
+
 // CreateAccount explicitly creates a state object. If a state object with the
 // address already exists the balance is carried over to the new account.
 //
@@ -698,6 +907,7 @@ func (csdb *CommitStateDB) Copy() *CommitStateDB {
 		logs:              make(map[sdk.Hash][]*Log, len(csdb.logs)),
 		preimages:         make(map[sdk.Hash][]byte),
 		journal:           newJournal(),
+		accessList:        csdb.accessList.Copy(),
 	}
 
 	// copy the dirty states, logs, and preimages
@@ -869,6 +1079,10 @@ func (csdb *CommitStateDB) ExportState() (s GenesisState) {
 	s.Storage = csdb.exportStorage()
 	s.Codes = csdb.exportCodes()
 	s.VMLogs = csdb.exportLogs()
+	s.BlockHashes = csdb.exportBlockHashes()
+	if bz := csdb.ctx.KVStore(csdb.storageKey).Get(EVMForkKey); bz != nil {
+		s.EVMFork = string(bz)
+	}
 	return
 }
 
@@ -888,6 +1102,12 @@ func (csdb *CommitStateDB) ImportState(s GenesisState) {
 	if err != nil {
 		panic(err)
 	}
+
+	csdb.importBlockHashes(s.BlockHashes)
+
+	if s.EVMFork != "" {
+		csdb.SetEVMFork(s.EVMFork)
+	}
 }
 
 func (csdb *CommitStateDB) exportCodes() map[string]sdk.Code {
@@ -986,6 +1206,66 @@ func (csdb *CommitStateDB) importLogs(vmLogs VMLogs) error {
 	return nil
 }
 
+// SetBlockHash stores the hash of the block at the given height and prunes the
+// hash which falls out of the BLOCKHASH window
+func (csdb *CommitStateDB) SetBlockHash(height int64, hash sdk.Hash) {
+	store := csdb.ctx.KVStore(csdb.storageKey)
+	store.Set(BlockHashKey(height), hash.Bytes())
+
+	if height > BlockHashWindow {
+		store.Delete(BlockHashKey(height - BlockHashWindow))
+	}
+}
+
+// GetBlockHash returns the hash of the block at the given height, or the zero
+// hash if the height is outside of the BLOCKHASH window
+func (csdb *CommitStateDB) GetBlockHash(height int64) sdk.Hash {
+	bz := csdb.ctx.KVStore(csdb.storageKey).Get(BlockHashKey(height))
+	if bz == nil {
+		return sdk.Hash{}
+	}
+
+	return sdk.BytesToHash(bz)
+}
+
+// SetEVMFork stores the name of the fork the vm runs with
+func (csdb *CommitStateDB) SetEVMFork(name string) {
+	csdb.ctx.KVStore(csdb.storageKey).Set(EVMForkKey, []byte(name))
+}
+
+// GetEVMFork returns the name of the fork the vm runs with, Istanbul until a fork is activated
+func (csdb *CommitStateDB) GetEVMFork() string {
+	bz := csdb.ctx.KVStore(csdb.storageKey).Get(EVMForkKey)
+	if bz == nil {
+		return ForkIstanbul
+	}
+
+	return string(bz)
+}
+
+func (csdb *CommitStateDB) exportBlockHashes() (blockHashes []BlockHash) {
+	store := prefix.NewStore(csdb.ctx.KVStore(csdb.storageKey), KeyPrefixBlockHash)
+	iter := store.Iterator(nil, nil)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		blockHashes = append(blockHashes, BlockHash{
+			Height: int64(binary.BigEndian.Uint64(iter.Key())),
+			Hash:   iter.Value(),
+		})
+	}
+
+	return
+}
+
+func (csdb *CommitStateDB) importBlockHashes(blockHashes []BlockHash) {
+	store := csdb.ctx.KVStore(csdb.storageKey)
+
+	for _, blockHash := range blockHashes {
+		store.Set(BlockHashKey(blockHash.Height), blockHash.Hash)
+	}
+}
+
 // for simulation
 func (csdb *CommitStateDB) GetAllHotContractAddrs() (accs []sdk.AccAddress) {
 	for _, obj := range csdb.stateObjects {
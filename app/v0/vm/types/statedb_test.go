@@ -1,12 +1,14 @@
 package types
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/log"
 	dbm "github.com/tendermint/tm-db"
 
 	"github.com/netcloth/netcloth-chain/app/v0/auth"
@@ -27,7 +29,7 @@ var (
 		StoreKey,
 	)
 
-	tkeys = sdk.NewTransientStoreKeys(params.TStoreKey)
+	tkeys = sdk.NewTransientStoreKeys(params.TStoreKey, TStoreKey)
 
 	cdc = MakeTestCodec()
 )
@@ -54,8 +56,9 @@ func buildCommitStateDB() *CommitStateDB {
 	ms.MountStoreWithDB(keys[auth.StoreKey], sdk.StoreTypeDB, nil)
 	ms.MountStoreWithDB(keys[StoreKey], sdk.StoreTypeDB, nil)
 	ms.MountStoreWithDB(tkeys[params.TStoreKey], sdk.StoreTypeTransient, nil)
+	ms.MountStoreWithDB(tkeys[TStoreKey], sdk.StoreTypeTransient, nil)
 	_ = ms.LoadLatestVersion()
-	return NewCommitStateDB(accountKeeper, keys[StoreKey]).WithContext(sdk.NewContext(ms, abci.Header{}, false, nil))
+	return NewCommitStateDB(accountKeeper, keys[StoreKey], tkeys[TStoreKey]).WithContext(sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger()))
 }
 
 func TestCommitStateDB_ImportExport_State(t *testing.T) {
@@ -118,3 +121,62 @@ func TestCommitStateDB_ImportExport_Code(t *testing.T) {
 	// after SetCode, the state will changed
 	require.True(t, gsImport.EqualWithoutParams(gsExport))
 }
+
+func TestCommitStateDB_BlockHash(t *testing.T) {
+	commitStateDB := buildCommitStateDB()
+
+	for height := int64(1); height <= BlockHashWindow+10; height++ {
+		commitStateDB.SetBlockHash(height, sdk.BigToHash(big.NewInt(height)))
+	}
+
+	require.Equal(t, sdk.Hash{}, commitStateDB.GetBlockHash(10))
+	require.Equal(t, sdk.BigToHash(big.NewInt(11)), commitStateDB.GetBlockHash(11))
+	require.Equal(t, sdk.BigToHash(big.NewInt(BlockHashWindow+10)), commitStateDB.GetBlockHash(BlockHashWindow+10))
+	require.Equal(t, sdk.Hash{}, commitStateDB.GetBlockHash(BlockHashWindow+11))
+
+	gsExport := commitStateDB.ExportState()
+	require.Len(t, gsExport.BlockHashes, BlockHashWindow)
+	require.Equal(t, int64(11), gsExport.BlockHashes[0].Height)
+	gsExport.Params = DefaultParams()
+	require.NoError(t, ValidateGenesis(gsExport))
+
+	importStateDB := buildCommitStateDB()
+	importStateDB.ImportState(gsExport)
+	require.Equal(t, sdk.BigToHash(big.NewInt(11)), importStateDB.GetBlockHash(11))
+	require.Equal(t, sdk.Hash{}, importStateDB.GetBlockHash(10))
+
+	gsExport.BlockHashes = append(gsExport.BlockHashes, BlockHash{Height: 1, Hash: make([]byte, 32)})
+	require.Error(t, ValidateGenesis(gsExport))
+	gsExport.BlockHashes = []BlockHash{{Height: 1, Hash: []byte{0x01}}}
+	require.Error(t, ValidateGenesis(gsExport))
+}
+
+func TestCommitStateDB_RunNative(t *testing.T) {
+	commitStateDB := buildCommitStateDB()
+	ctx := commitStateDB.Context()
+	addr := sdk.AccAddress(crypto.AddressHash([]byte("addr")))
+	commitStateDB.AddBalance(addr, big.NewInt(100))
+
+	// a native change moving coins out of the account, balance is pending in the state db
+	spend := func(ctx sdk.Context) error {
+		acc := commitStateDB.ak.GetAccount(ctx, addr)
+		require.Equal(t, int64(100), acc.GetCoins().AmountOf(sdk.NativeTokenName).Int64())
+		require.NoError(t, acc.SetCoins(sdk.NewCoins(sdk.NewInt64Coin(sdk.NativeTokenName, 40))))
+		commitStateDB.ak.SetAccount(ctx, acc)
+		ctx.EventManager().EmitEvent(sdk.NewEvent("spend"))
+		return nil
+	}
+
+	snapshot := commitStateDB.Snapshot()
+	require.NoError(t, commitStateDB.RunNative(spend))
+	require.Equal(t, big.NewInt(40), commitStateDB.GetBalance(addr))
+
+	commitStateDB.RevertToSnapshot(snapshot)
+	require.Equal(t, big.NewInt(100), commitStateDB.GetBalance(addr))
+	require.Nil(t, commitStateDB.ak.GetAccount(ctx, addr))
+
+	require.NoError(t, commitStateDB.RunNative(spend))
+	commitStateDB.Finalise(true)
+	require.Equal(t, int64(40), commitStateDB.ak.GetAccount(ctx, addr).GetCoins().AmountOf(sdk.NativeTokenName).Int64())
+	require.Len(t, ctx.EventManager().Events(), 1)
+}
@@ -0,0 +1,186 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/netcloth/netcloth-chain/app/v0/auth"
+	"github.com/netcloth/netcloth-chain/codec"
+	sdk "github.com/netcloth/netcloth-chain/types"
+	sdkerrors "github.com/netcloth/netcloth-chain/types/errors"
+)
+
+var (
+	_ sdk.Tx = EthereumTx{}
+)
+
+// EthereumTx is a native tx type carrying an RLP encoded legacy or EIP-155
+// Ethereum transaction signed with secp256k1. It is executed as a MsgContract
+// sent by the address recovered from the signature, so standard Ethereum
+// tooling can create and call contracts directly.
+type EthereumTx struct {
+	tx   *ethtypes.Transaction
+	from sdk.AccAddress
+}
+
+// NewEthereumTx wraps a signed Ethereum transaction
+func NewEthereumTx(tx *ethtypes.Transaction) EthereumTx {
+	ethTx := EthereumTx{tx: tx}
+	ethTx.from, _ = ethTx.RecoverSender()
+	return ethTx
+}
+
+// DecodeEthereumTx decodes an RLP encoded Ethereum transaction and recovers its sender
+func DecodeEthereumTx(txBytes []byte) (EthereumTx, error) {
+	tx := new(ethtypes.Transaction)
+	if err := rlp.DecodeBytes(txBytes, tx); err != nil {
+		return EthereumTx{}, sdkerrors.Wrap(sdkerrors.ErrTxDecode, err.Error())
+	}
+
+	ethTx := EthereumTx{tx: tx}
+	from, err := ethTx.RecoverSender()
+	if err != nil {
+		return EthereumTx{}, err
+	}
+	ethTx.from = from
+
+	return ethTx, nil
+}
+
+// NewSimulationEthereumTx returns the Ethereum tx sending msg with the given nonce, gas
+// limit and gas price, which the gas estimation runs through the ante handler, and its encoding.
+// It carries a placeholder EIP-155 signature for chainID, as large as a real one, and
+// msg.From as sender: the ante handler doesn't recover the sender of a simulated tx.
+func NewSimulationEthereumTx(msg MsgContract, nonce, gasLimit uint64, gasPrice, chainID *big.Int) (EthereumTx, []byte, error) {
+	var tx *ethtypes.Transaction
+	if msg.To.Empty() {
+		tx = ethtypes.NewContractCreation(nonce, msg.Amount.Amount.BigInt(), gasLimit, gasPrice, msg.Payload)
+	} else {
+		tx = ethtypes.NewTransaction(nonce, common.BytesToAddress(msg.To), msg.Amount.Amount.BigInt(), gasLimit, gasPrice, msg.Payload)
+	}
+
+	sig := bytes.Repeat([]byte{0xff}, crypto.SignatureLength)
+	sig[crypto.SignatureLength-1] = 1
+	tx, err := tx.WithSignature(ethtypes.NewEIP155Signer(chainID), sig)
+	if err != nil {
+		return EthereumTx{}, nil, err
+	}
+
+	txBytes, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return EthereumTx{}, nil, err
+	}
+
+	return EthereumTx{tx: tx, from: msg.From}, txBytes, nil
+}
+
+// EthereumTxHash returns the hash Ethereum clients compute for an RLP encoded
+// Ethereum transaction, the keccak256 hash of its encoding, and false if txBytes
+// isn't an Ethereum transaction. Tendermint identifies the tx by the sha256 hash of
+// txBytes instead.
+func EthereumTxHash(txBytes []byte) (sdk.Hash, bool) {
+	if err := rlp.DecodeBytes(txBytes, new(ethtypes.Transaction)); err != nil {
+		return sdk.Hash{}, false
+	}
+
+	return sdk.BytesToHash(crypto.Keccak256(txBytes)), true
+}
+
+// RecoverSender recovers the sender address from the tx signature. Legacy
+// signatures are accepted as well as EIP-155 signatures.
+func (tx EthereumTx) RecoverSender() (sdk.AccAddress, error) {
+	var signer ethtypes.Signer = ethtypes.HomesteadSigner{}
+	if tx.tx.Protected() {
+		signer = ethtypes.NewEIP155Signer(tx.tx.ChainId())
+	}
+
+	from, err := ethtypes.Sender(signer, tx.tx)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrUnauthorized, err.Error())
+	}
+
+	return sdk.AccAddress(from.Bytes()), nil
+}
+
+// GetMsgs returns the MsgContract executed by the tx
+func (tx EthereumTx) GetMsgs() []sdk.Msg {
+	return []sdk.Msg{tx.Msg()}
+}
+
+// Msg returns the MsgContract executed by the tx
+func (tx EthereumTx) Msg() MsgContract {
+	var to sdk.AccAddress
+	if tx.tx.To() != nil {
+		to = sdk.AccAddress(tx.tx.To().Bytes())
+	}
+
+	return NewMsgContract(tx.from, to, tx.tx.Data(), sdk.NewCoin(sdk.NativeTokenName, sdk.NewIntFromBigInt(tx.tx.Value())))
+}
+
+// ValidateBasic does a simple and lightweight validation check that doesn't
+// require access to any other information.
+func (tx EthereumTx) ValidateBasic() error {
+	if tx.from.Empty() {
+		return sdkerrors.Wrap(sdkerrors.ErrUnauthorized, "invalid signature, sender can't be recovered")
+	}
+
+	if tx.tx.Gas() == 0 || int64(tx.tx.Gas()) < 0 {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "invalid gas supplied: %d", tx.tx.Gas())
+	}
+
+	if tx.tx.GasPrice().Sign() < 0 {
+		return sdkerrors.Wrapf(sdkerrors.ErrInsufficientFee, "invalid gas price provided: %s", tx.tx.GasPrice())
+	}
+
+	return tx.Msg().ValidateBasic()
+}
+
+// GetGas returns the gas limit of the tx
+func (tx EthereumTx) GetGas() uint64 { return tx.tx.Gas() }
+
+// GetFee returns gasPrice * gasLimit in pnch
+func (tx EthereumTx) GetFee() sdk.Coins {
+	fee := new(big.Int).Mul(tx.tx.GasPrice(), new(big.Int).SetUint64(tx.tx.Gas()))
+	return sdk.NewCoins(sdk.NewCoin(sdk.NativeTokenName, sdk.NewIntFromBigInt(fee)))
+}
+
+// FeePayer returns the sender recovered from the signature
+func (tx EthereumTx) FeePayer() sdk.AccAddress { return tx.from }
+
+// GetSigners returns the sender recovered from the signature
+func (tx EthereumTx) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{tx.from} }
+
+// GetNonce returns the nonce the sender's account sequence must match
+func (tx EthereumTx) GetNonce() uint64 { return tx.tx.Nonce() }
+
+// ChainID returns the EIP-155 chain id of the signature, nil for legacy signatures
+func (tx EthereumTx) ChainID() *big.Int {
+	if !tx.tx.Protected() {
+		return nil
+	}
+	return tx.tx.ChainId()
+}
+
+// TxDecoder decodes amino encoded StdTxs and, failing that, RLP encoded Ethereum txs
+func TxDecoder(cdc *codec.Codec) sdk.TxDecoder {
+	stdTxDecoder := auth.DefaultTxDecoder(cdc)
+
+	return func(txBytes []byte) (sdk.Tx, error) {
+		tx, err := stdTxDecoder(txBytes)
+		if err == nil {
+			return tx, nil
+		}
+
+		ethTx, ethErr := DecodeEthereumTx(txBytes)
+		if ethErr != nil {
+			return nil, sdkerrors.Wrapf(err, "neither a StdTx nor an Ethereum tx: %s", ethErr.Error())
+		}
+
+		return ethTx, nil
+	}
+}
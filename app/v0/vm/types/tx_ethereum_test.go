@@ -0,0 +1,116 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/netcloth/netcloth-chain/types"
+)
+
+func TestDecodeEthereumTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := sdk.AccAddress(crypto.PubkeyToAddress(key.PublicKey).Bytes())
+
+	to := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	payload := []byte("payload")
+
+	signers := []ethtypes.Signer{
+		ethtypes.HomesteadSigner{},
+		ethtypes.NewEIP155Signer(big.NewInt(88)),
+	}
+
+	for i, signer := range signers {
+		tx := ethtypes.NewTransaction(3, to, big.NewInt(123), 100000, big.NewInt(1000), payload)
+		signedTx, err := ethtypes.SignTx(tx, signer, key)
+		require.NoError(t, err, "case %d", i)
+
+		bz, err := rlp.EncodeToBytes(signedTx)
+		require.NoError(t, err, "case %d", i)
+
+		ethTx, err := DecodeEthereumTx(bz)
+		require.NoError(t, err, "case %d", i)
+		require.NoError(t, ethTx.ValidateBasic(), "case %d", i)
+
+		require.Equal(t, sender, ethTx.FeePayer(), "case %d", i)
+		require.Equal(t, []sdk.AccAddress{sender}, ethTx.GetSigners(), "case %d", i)
+		require.Equal(t, uint64(3), ethTx.GetNonce(), "case %d", i)
+		require.Equal(t, uint64(100000), ethTx.GetGas(), "case %d", i)
+		require.Equal(t, sdk.NewCoins(sdk.NewInt64Coin(sdk.NativeTokenName, 100000000)), ethTx.GetFee(), "case %d", i)
+
+		msg := ethTx.Msg()
+		require.Equal(t, sender, msg.From, "case %d", i)
+		require.Equal(t, sdk.AccAddress(to.Bytes()), msg.To, "case %d", i)
+		require.Equal(t, payload, []byte(msg.Payload), "case %d", i)
+		require.Equal(t, sdk.NewInt64Coin(sdk.NativeTokenName, 123), msg.Amount, "case %d", i)
+	}
+
+	ethTx, err := DecodeEthereumTx(mustSignedTxBytes(t, ethtypes.NewEIP155Signer(big.NewInt(88)), key))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(88), ethTx.ChainID())
+
+	ethTx, err = DecodeEthereumTx(mustSignedTxBytes(t, ethtypes.HomesteadSigner{}, key))
+	require.NoError(t, err)
+	require.Nil(t, ethTx.ChainID())
+}
+
+func TestDecodeEthereumTxInvalid(t *testing.T) {
+	_, err := DecodeEthereumTx([]byte("invalid"))
+	require.Error(t, err)
+
+	// unsigned tx, the sender can't be recovered
+	tx := ethtypes.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1000), []byte("payload"))
+	bz, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+	_, err = DecodeEthereumTx(bz)
+	require.Error(t, err)
+}
+
+func TestEthereumTxHash(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	tx, err := ethtypes.SignTx(ethtypes.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1000), []byte("payload")), ethtypes.HomesteadSigner{}, key)
+	require.NoError(t, err)
+	bz, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+
+	hash, ok := EthereumTxHash(bz)
+	require.True(t, ok)
+	require.Equal(t, tx.Hash().Bytes(), hash.Bytes())
+
+	_, ok = EthereumTxHash([]byte("invalid"))
+	require.False(t, ok)
+}
+
+func TestTxDecoder(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	decoder := TxDecoder(ModuleCdc)
+
+	tx, err := decoder(mustSignedTxBytes(t, ethtypes.HomesteadSigner{}, key))
+	require.NoError(t, err)
+	_, ok := tx.(EthereumTx)
+	require.True(t, ok)
+
+	_, err = decoder([]byte("invalid"))
+	require.Error(t, err)
+}
+
+func mustSignedTxBytes(t *testing.T, signer ethtypes.Signer, key *ecdsa.PrivateKey) []byte {
+	tx := ethtypes.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1000), []byte("payload"))
+	signedTx, err := ethtypes.SignTx(tx, signer, key)
+	require.NoError(t, err)
+
+	bz, err := rlp.EncodeToBytes(signedTx)
+	require.NoError(t, err)
+	return bz
+}
@@ -218,7 +218,11 @@ func (app *BaseApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliv
 			data = append(data, result.Data...)
 		}
 
-		return sdkerrors.ResponseDeliverTx(err, data, gInfo.GasWanted, gInfo.GasUsed, log)
+		res := sdkerrors.ResponseDeliverTx(err, data, gInfo.GasWanted, gInfo.GasUsed, log)
+		if result != nil {
+			res.Events = result.Events.ToABCIEvents()
+		}
+		return res
 	}
 
 	return abci.ResponseDeliverTx{
@@ -302,7 +306,10 @@ func handleQueryApp(app *BaseApp, path []string, req abci.RequestQuery) (res abc
 				return sdkerrors.QueryResult(sdkerrors.Wrap(err, "failed to decode tx"))
 			}
 
-			gInfo, _, _ := app.Simulate(txBytes, tx)
+			gas, err := app.EstimateGas(txBytes, tx)
+			if err != nil {
+				return sdkerrors.QueryResult(sdkerrors.Wrap(err, "failed to simulate tx"))
+			}
 
 			if req.Height == 0 {
 				req.Height = app.LastBlockHeight()
@@ -311,7 +318,7 @@ func handleQueryApp(app *BaseApp, path []string, req abci.RequestQuery) (res abc
 			return abci.ResponseQuery{
 				Codespace: sdkerrors.RootCodespace,
 				Height:    req.Height,
-				Value:     codec.Cdc.MustMarshalBinaryLengthPrefixed(gInfo.GasUsed),
+				Value:     codec.Cdc.MustMarshalBinaryLengthPrefixed(gas),
 			}
 		case "version":
 			return abci.ResponseQuery{
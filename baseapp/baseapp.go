@@ -450,10 +450,18 @@ func (app *BaseApp) runMsgs(ctx sdk.Context, msgs []sdk.Msg, mode runTxMode) (*s
 
 			logJSON := codec.Cdc.MustMarshalJSON(idxLogs)
 
+			// the state changes of the tx are discarded, so are the events of the executed
+			// msgs, only the events describing the failure are kept, e.g. the revert reason
+			// of a contract call
+			failureEvents := sdk.EmptyEvents()
+			if msgResult != nil {
+				failureEvents = msgResult.Events
+			}
+
 			return &sdk.Result{
 				Data:   data,
 				Log:    strings.TrimSpace(string(logJSON)),
-				Events: events,
+				Events: failureEvents,
 			}, sdkerrors.Wrapf(err, "failed to execute message; message index: %d", i)
 		}
 
@@ -481,12 +489,16 @@ func (app *BaseApp) runMsgs(ctx sdk.Context, msgs []sdk.Msg, mode runTxMode) (*s
 // further details on transaction execution, reference the BaseApp SDK
 // documentation.
 func (app *BaseApp) runTx(mode runTxMode, txBytes []byte, tx sdk.Tx) (gInfo sdk.GasInfo, result *sdk.Result, err error) {
+	return app.runTxWithContext(app.getContextForTx(mode, txBytes), mode, txBytes, tx)
+}
+
+// runTxWithContext processes a transaction in the given context, see runTx.
+func (app *BaseApp) runTxWithContext(ctx sdk.Context, mode runTxMode, txBytes []byte, tx sdk.Tx) (gInfo sdk.GasInfo, result *sdk.Result, err error) {
 	// NOTE: GasWanted should be returned by the AnteHandler. GasUsed is
 	// determined by the GasMeter. We need access to the context to get the gas
 	// meter so we initialize upfront.
 	var gasWanted uint64
 
-	ctx := app.getContextForTx(mode, txBytes)
 	ms := ctx.MultiStore()
 
 	// only run the tx if there is block gas remaining
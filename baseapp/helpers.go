@@ -1,6 +1,8 @@
 package baseapp
 
 import (
+	"math"
+
 	sdk "github.com/netcloth/netcloth-chain/types"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
@@ -15,6 +17,29 @@ func (app *BaseApp) Simulate(txBytes []byte, tx sdk.Tx) (sdk.GasInfo, *sdk.Resul
 	return app.runTx(runTxModeSimulate, txBytes, tx)
 }
 
+// EstimateGas returns the smallest gas limit under which the full execution of the
+// tx, AnteHandler included, succeeds. The gas consumed by an unmetered simulation
+// may not be enough, e.g. a contract call only forwards 63/64 of the gas left to
+// an inner call, so greater limits are searched up to the maximum block gas.
+// If the tx fails whatever the limit, the error of its execution is returned.
+func (app *BaseApp) EstimateGas(txBytes []byte, tx sdk.Tx) (uint64, error) {
+	gInfo, _, err := app.Simulate(txBytes, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	maxGas := app.getMaximumBlockGas()
+	if maxGas == 0 {
+		maxGas = math.MaxInt64
+	}
+
+	return sdk.SearchGasLimit(gInfo.GasUsed, maxGas, func(gasLimit uint64) error {
+		ctx := app.getContextForTx(runTxModeSimulate, txBytes).WithGasMeter(sdk.NewGasMeter(gasLimit))
+		_, _, err := app.runTxWithContext(ctx, runTxModeSimulate, txBytes, tx)
+		return err
+	})
+}
+
 // nolint
 func (app *BaseApp) Deliver(tx sdk.Tx) (sdk.GasInfo, *sdk.Result, error) {
 	return app.runTx(runTxModeDeliver, nil, tx)
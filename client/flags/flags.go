@@ -50,6 +50,8 @@ const (
 	FlagGenerateOnly       = "generate-only"
 	FlagIndentResponse     = "indent"
 	FlagListenAddr         = "laddr"
+	FlagEthRPCListenAddr   = "eth-rpc-laddr"
+	FlagSolc               = "solc"
 	FlagMaxOpenConnections = "max-open"
 	FlagRPCReadTimeout     = "read-timeout"
 	FlagRPCWriteTimeout    = "write-timeout"
@@ -118,6 +120,8 @@ func PostCommands(cmds ...*cobra.Command) []*cobra.Command {
 func RegisterRestServerFlags(cmd *cobra.Command) *cobra.Command {
 	cmd = GetCommands(cmd)[0]
 	cmd.Flags().String(FlagListenAddr, "tcp://localhost:1317", "The address for the server to listen on")
+	cmd.Flags().String(FlagEthRPCListenAddr, "", "The address for the Ethereum JSON-RPC server to listen on (disabled if empty, e.g. tcp://localhost:8545)")
+	cmd.Flags().String(FlagSolc, "solc", "The solc binary compiling the sources of the contracts submitted to /vm/verify")
 	cmd.Flags().Uint(FlagMaxOpenConnections, 1000, "The number of maximum open connections")
 	cmd.Flags().Uint(FlagRPCReadTimeout, 10, "The RPC read timeout (in seconds)")
 	cmd.Flags().Uint(FlagRPCWriteTimeout, 10, "The RPC write timeout (in seconds)")
@@ -30,8 +30,13 @@ type RestServer struct {
 	CliCtx  context.CLIContext
 	KeyBase keybase.Keybase
 
-	log      log.Logger
-	listener net.Listener
+	// EthRPCMux holds the routes of the optional Ethereum JSON-RPC server,
+	// which is served on its own listener next to the REST server
+	EthRPCMux *mux.Router
+
+	log         log.Logger
+	listener    net.Listener
+	ethListener net.Listener
 }
 
 // NewRestServer creates a new rest server instance
@@ -41,17 +46,24 @@ func NewRestServer(cdc *codec.Codec) *RestServer {
 	logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "rest-server")
 
 	return &RestServer{
-		Mux:    r,
-		CliCtx: cliCtx,
-		log:    logger,
+		Mux:       r,
+		CliCtx:    cliCtx,
+		EthRPCMux: mux.NewRouter(),
+		log:       logger,
 	}
 }
 
 // Start starts the rest server
 func (rs *RestServer) Start(listenAddr string, maxOpen int, readTimeout, writeTimeout uint) (err error) {
 	server.TrapSignal(func() {
-		err := rs.listener.Close()
-		rs.log.Error("error closing listener", "err", err)
+		if err := rs.listener.Close(); err != nil {
+			rs.log.Error("error closing listener", "err", err)
+		}
+		if rs.ethListener != nil {
+			if err := rs.ethListener.Close(); err != nil {
+				rs.log.Error("error closing eth rpc listener", "err", err)
+			}
+		}
 	})
 
 	cfg := rpcserver.DefaultConfig()
@@ -73,6 +85,22 @@ func (rs *RestServer) Start(listenAddr string, maxOpen int, readTimeout, writeTi
 	return rpcserver.StartHTTPServer(rs.listener, rs.Mux, rs.log, cfg)
 }
 
+// StartEthRPC starts the Ethereum JSON-RPC server, its listener is closed by the signal trap of Start
+func (rs *RestServer) StartEthRPC(listenAddr string, maxOpen int, readTimeout, writeTimeout uint) (err error) {
+	cfg := rpcserver.DefaultConfig()
+	cfg.MaxOpenConnections = maxOpen
+	cfg.ReadTimeout = time.Duration(readTimeout) * time.Second
+	cfg.WriteTimeout = time.Duration(writeTimeout) * time.Second
+
+	rs.ethListener, err = rpcserver.Listen(listenAddr, cfg)
+	if err != nil {
+		return
+	}
+	rs.log.Info("Starting Ethereum JSON-RPC service...", "laddr", listenAddr)
+
+	return rpcserver.StartHTTPServer(rs.ethListener, rs.EthRPCMux, rs.log, cfg)
+}
+
 // ServeCommand will start the application REST service as a blocking process. It
 // takes a codec to create a RestServer object and a function to register all
 // necessary routes.
@@ -86,6 +114,20 @@ func ServeCommand(cdc *codec.Codec, registerRoutesFn func(*RestServer)) *cobra.C
 			registerRoutesFn(rs)
 			rs.registerSwaggerUI()
 
+			if ethLaddr := viper.GetString(flags.FlagEthRPCListenAddr); ethLaddr != "" {
+				go func() {
+					err := rs.StartEthRPC(
+						ethLaddr,
+						viper.GetInt(flags.FlagMaxOpenConnections),
+						uint(viper.GetInt(flags.FlagRPCReadTimeout)),
+						uint(viper.GetInt(flags.FlagRPCWriteTimeout)),
+					)
+					if err != nil {
+						rs.log.Error("eth rpc server stopped", "err", err)
+					}
+				}()
+			}
+
 			// Start the rest server and return error if one exists
 			err = rs.Start(
 				viper.GetString(flags.FlagListenAddr),
@@ -20,6 +20,8 @@ import (
 	cipalcli "github.com/netcloth/netcloth-chain/app/v0/cipal/client/cli"
 	ipalcli "github.com/netcloth/netcloth-chain/app/v0/ipal/client/cli"
 	vmcli "github.com/netcloth/netcloth-chain/app/v0/vm/client/cli"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/client/ethrpc"
+	"github.com/netcloth/netcloth-chain/app/v0/vm/client/verifier"
 	"github.com/netcloth/netcloth-chain/client"
 	"github.com/netcloth/netcloth-chain/client/keys"
 	"github.com/netcloth/netcloth-chain/client/lcd"
@@ -76,6 +78,8 @@ func registerRoutes(rs *lcd.RestServer) {
 	client.RegisterRoutes(rs.CliCtx, rs.Mux)
 	authrest.RegisterTxRoutes(rs.CliCtx, rs.Mux)
 	v0.ModuleBasics.RegisterRESTRoutes(rs.CliCtx, rs.Mux)
+	ethrpc.RegisterRoutes(rs.CliCtx, rs.EthRPCMux)
+	verifier.RegisterRoutes(rs.CliCtx, rs.Mux)
 }
 
 func queryCmd(cdc *amino.Codec) *cobra.Command {
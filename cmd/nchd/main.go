@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"io"
+	"path/filepath"
 
 	"github.com/netcloth/netcloth-chain/baseapp"
 
@@ -21,6 +22,7 @@ import (
 	genutilcli "github.com/netcloth/netcloth-chain/app/v0/genutil/client/cli"
 	"github.com/netcloth/netcloth-chain/app/v0/guardian"
 	"github.com/netcloth/netcloth-chain/app/v0/staking"
+	"github.com/netcloth/netcloth-chain/app/v0/vm"
 	"github.com/netcloth/netcloth-chain/client"
 	"github.com/netcloth/netcloth-chain/server"
 	"github.com/netcloth/netcloth-chain/store"
@@ -30,6 +32,9 @@ import (
 const (
 	flagMinGasPrices   = "minimum-gas-prices"
 	flagInvCheckPeriod = "inv-check-period"
+
+	flagVMLogIndex          = "vm.log-index"
+	flagVMLogIndexRetention = "vm.log-index-retention"
 )
 
 var invCheckPeriod uint
@@ -71,14 +76,28 @@ func main() {
 func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application {
 	minGasPrices := viper.GetString(flagMinGasPrices)
 	return app.NewNCHApp(
-		logger, db, traceStore, true, invCheckPeriod,
+		logger, db, traceStore, true, invCheckPeriod, openLogIndex(),
 		baseapp.SetPruning(store.NewPruningOptionsFromString(viper.GetString("pruning"))), baseapp.SetMinGasPrices(minGasPrices),
 	)
 }
 
+// openLogIndex opens the node-local contract log index if enabled in app.toml
+func openLogIndex() *vm.LogIndex {
+	if !viper.GetBool(flagVMLogIndex) {
+		return nil
+	}
+
+	dataDir := filepath.Join(viper.GetString(cli.HomeFlag), "data")
+	db, err := sdk.NewLevelDB("vm_log_index", dataDir)
+	if err != nil {
+		panic(err)
+	}
+	return vm.NewLogIndex(db, viper.GetUint64(flagVMLogIndexRetention))
+}
+
 func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB, traceStore io.Writer, height int64, forZeroHeight bool, jailWhiteList []string) (json.RawMessage, []tmtypes.GenesisValidator, error) {
 	if height != -1 {
-		nchApp := app.NewNCHApp(logger, db, traceStore, false, uint(1))
+		nchApp := app.NewNCHApp(logger, db, traceStore, false, uint(1), nil)
 		err := nchApp.LoadHeight(height)
 		if err != nil {
 			return nil, nil, err
@@ -86,6 +105,6 @@ func exportAppStateAndTMValidators(logger log.Logger, db dbm.DB, traceStore io.W
 		return nchApp.ExportAppStateAndValidators(forZeroHeight, jailWhiteList)
 	}
 
-	nchApp := app.NewNCHApp(logger, db, traceStore, true, uint(1))
+	nchApp := app.NewNCHApp(logger, db, traceStore, true, uint(1), nil)
 	return nchApp.ExportAppStateAndValidators(forZeroHeight, jailWhiteList)
 }
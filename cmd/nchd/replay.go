@@ -100,7 +100,7 @@ func replayTxs(rootDir string, from, to int64) error {
 
 	// Application
 	fmt.Fprintln(os.Stderr, "Creating application")
-	myapp := app.NewNCHApp(ctx.Logger, appDB, nil, true, uint(1))
+	myapp := app.NewNCHApp(ctx.Logger, appDB, nil, true, uint(1), nil)
 
 	// Genesis
 	var genDocPath = filepath.Join(configDir, "genesis.json")
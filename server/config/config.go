@@ -23,9 +23,23 @@ type BaseConfig struct {
 	HaltHeight uint64 `mapstructure:"halt-height"`
 }
 
+// VMConfig defines the node-local configuration of the vm module
+type VMConfig struct {
+	// LogIndex enables the index of the contract logs by address, topic and height
+	// serving the filter logs and bloom queries. The index is local to the node and
+	// out of the consensus state.
+	LogIndex bool `mapstructure:"log-index"`
+
+	// LogIndexRetention is the number of recent blocks whose logs are kept in the
+	// index, 0 keeps the logs of all the blocks.
+	LogIndexRetention uint64 `mapstructure:"log-index-retention"`
+}
+
 // Config defines the server's top level configuration
 type Config struct {
 	BaseConfig `mapstructure:",squash"`
+
+	VM VMConfig `mapstructure:"vm"`
 }
 
 // SetMinGasPrices sets the validator's minimum gas prices.
@@ -58,9 +72,13 @@ func (c *Config) GetMinGasPrices() sdk.DecCoins {
 // DefaultConfig returns server's default configuration.
 func DefaultConfig() *Config {
 	return &Config{
-		BaseConfig{
+		BaseConfig: BaseConfig{
 			MinGasPrices: defaultMinGasPrices,
 			HaltHeight:   0,
 		},
+		VM: VMConfig{
+			LogIndex:          false,
+			LogIndexRetention: 0,
+		},
 	}
 }
@@ -21,6 +21,17 @@ minimum-gas-prices = "{{ .BaseConfig.MinGasPrices }}"
 # HaltHeight contains a non-zero height at which a node will gracefully halt
 # and shutdown that can be used to assist upgrades and testing.
 halt-height = {{ .BaseConfig.HaltHeight }}
+
+##### vm config options #####
+[vm]
+
+# LogIndex enables the node-local index of the contract logs by address, topic
+# and height, serving the filter logs and bloom queries.
+log-index = {{ .VM.LogIndex }}
+
+# LogIndexRetention is the number of recent blocks whose logs are kept in the
+# index, 0 keeps the logs of all the blocks.
+log-index-retention = {{ .VM.LogIndexRetention }}
 `
 
 var configTemplate *template.Template
@@ -0,0 +1,42 @@
+package types
+
+// SearchGasLimit returns the smallest gas limit within [lo, max] under which run
+// succeeds. The limit is doubled from lo until run succeeds, then bisected. If run
+// fails with max, its error is returned.
+func SearchGasLimit(lo, max Gas, run func(gasLimit Gas) error) (Gas, error) {
+	if lo == 0 {
+		lo = 1
+	}
+	if lo > max {
+		lo = max
+	}
+
+	hi := lo
+	for {
+		err := run(hi)
+		if err == nil {
+			break
+		}
+		if hi >= max {
+			return 0, err
+		}
+
+		lo = hi + 1
+		if hi > max/2 {
+			hi = max
+		} else {
+			hi *= 2
+		}
+	}
+
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if run(mid) == nil {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	return hi, nil
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchGasLimit(t *testing.T) {
+	errOutOfGas := errors.New("out of gas")
+
+	cases := []struct {
+		lo, max, required Gas
+		expected          Gas
+		expectErr         bool
+	}{
+		{100, 1000000, 100, 100, false},
+		{100, 1000000, 101, 101, false},
+		{100, 1000000, 12345, 12345, false},
+		{0, 1000000, 1, 1, false},
+		{100, 1000000, 1000000, 1000000, false},
+		{100, 1000000, 1000001, 0, true},
+		{2000, 1000, 1000, 1000, false},
+	}
+
+	for i, tc := range cases {
+		runs := 0
+		gas, err := SearchGasLimit(tc.lo, tc.max, func(gasLimit Gas) error {
+			runs++
+			if gasLimit < tc.required {
+				return errOutOfGas
+			}
+			return nil
+		})
+
+		if tc.expectErr {
+			require.Equal(t, errOutOfGas, err, "case %d", i)
+			continue
+		}
+		require.NoError(t, err, "case %d", i)
+		require.Equal(t, tc.expected, gas, "case %d", i)
+		require.True(t, runs <= 64, "case %d", i)
+	}
+}